@@ -0,0 +1,370 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ethersphere/beekeeper/pkg/config"
+	"github.com/ethersphere/beekeeper/pkg/orchestration"
+	orchestrationK8S "github.com/ethersphere/beekeeper/pkg/orchestration/k8s"
+	"github.com/spf13/cobra"
+)
+
+func (c *command) initApplyCmd() (err error) {
+	const (
+		optionNameLabelSelector = "label-selector"
+		optionNameYes           = "yes"
+		optionNameDryRun        = "dry-run"
+	)
+
+	cmd := &cobra.Command{
+		Use:   "apply",
+		Short: "reconciles Bee cluster with its configuration",
+		Long: `Reconciles Bee cluster with its configuration.
+
+Compares nodes defined in the cluster configuration with the StatefulSets,
+Services and Ingresses that exist in the cluster namespace and prints the
+differences. After confirmation, missing nodes are deployed and funded, nodes
+whose image, Bee configuration, resource requests and limits, labels,
+persistence or network profile changed are recreated in place and extra nodes
+of the cluster's node groups are deleted. Other StatefulSets in the namespace,
+like nodes of other clusters, are kept.
+StatefulSets whose labels or persistence changed are deleted and created again,
+as their selector and volume claims can not be updated. Recreated nodes keep
+their persistent volumes and keys.`,
+		Example: `beekeeper apply --cluster-name=default --dry-run
+beekeeper apply --cluster-name=default --wallet-key=<key> --yes`,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			ctx, cancel := context.WithTimeout(cmd.Context(), c.globalConfig.GetDuration(optionNameTimeout))
+			defer cancel()
+
+			return c.applyCluster(ctx, c.globalConfig.GetString(optionNameClusterName), applyOptions{
+				labelSelector: c.globalConfig.GetString(optionNameLabelSelector),
+				yes:           c.globalConfig.GetBool(optionNameYes),
+				dryRun:        c.globalConfig.GetBool(optionNameDryRun),
+			}, cmd.InOrStdin(), cmd.OutOrStdout())
+		},
+		PreRunE: c.preRunE,
+	}
+
+	cmd.Flags().String(optionNameClusterName, "", "cluster name")
+	cmd.Flags().String(optionNameLabelSelector, beeLabelSelector, "Kubernetes label selector of the cluster's StatefulSets, Services and Ingresses")
+	cmd.Flags().String(optionNameWalletKey, "", "Hex-encoded private key for funding added nodes. Required when nodes are added.")
+	cmd.Flags().Bool(optionNameYes, false, "apply changes without confirmation")
+	cmd.Flags().Bool(optionNameDryRun, false, "only print changes")
+	cmd.Flags().Duration(optionNameTimeout, 30*time.Minute, "timeout")
+
+	c.root.AddCommand(cmd)
+
+	return nil
+}
+
+type applyOptions struct {
+	labelSelector string
+	yes           bool
+	dryRun        bool
+}
+
+// desiredNode represents a node defined in the cluster configuration
+type desiredNode struct {
+	orchestrationK8S.DesiredNode
+	options orchestration.NodeOptions
+	keys    bool // keys are set in the configuration
+}
+
+func (c *command) applyCluster(ctx context.Context, clusterName string, o applyOptions, in io.Reader, out io.Writer) error {
+	if clusterName == "" {
+		return errMissingClusterName
+	}
+
+	if backend := c.globalConfig.GetString(optionNameBackend); backend != backendK8S {
+		return fmt.Errorf("apply is not supported by the %s backend", backend)
+	}
+	if c.k8sClient == nil {
+		return errors.New("kubernetes client is required to apply the cluster")
+	}
+
+	clusterConfig, ok := c.config.Clusters[clusterName]
+	if !ok {
+		return fmt.Errorf("cluster %s not defined", clusterName)
+	}
+	if clusterConfig.IsUsingStaticEndpoints() {
+		return errors.New("static endpoints are not supported for applying the cluster")
+	}
+
+	cluster, err := c.newCluster(clusterConfig)
+	if err != nil {
+		return err
+	}
+
+	desired, err := desiredNodes(clusterConfig, c.config, cluster)
+	if err != nil {
+		return err
+	}
+
+	namespace := clusterConfig.GetNamespace()
+
+	observed, err := orchestrationK8S.ObserveNodes(ctx, c.k8sClient, namespace, o.labelSelector)
+	if err != nil {
+		return fmt.Errorf("observe nodes in namespace %s: %w", namespace, err)
+	}
+
+	desiredSpecs := make([]orchestrationK8S.DesiredNode, 0, len(desired))
+	for _, d := range desired {
+		desiredSpecs = append(desiredSpecs, d.DesiredNode)
+	}
+
+	// only nodes of the cluster's node groups are removed, as the namespace
+	// may be shared with other clusters
+	nodeGroups := slices.Sorted(maps.Keys(clusterConfig.GetNodeGroups()))
+
+	plan := orchestrationK8S.PlanReconcile(nodeGroups, desiredSpecs, observed)
+	if _, err := fmt.Fprint(out, plan); err != nil {
+		return err
+	}
+
+	if len(plan) == 0 || o.dryRun {
+		return nil
+	}
+
+	if !o.yes {
+		confirmed, err := confirm(in, out, fmt.Sprintf("apply changes to cluster %s?", clusterName))
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			c.log.Infof("changes are not applied")
+			return nil
+		}
+	}
+
+	var chainNodeEndpoint, walletKey string
+	var fundOpts orchestration.FundingOptions
+
+	if len(plan.Changes(orchestrationK8S.ChangeAdd)) > 0 {
+		if chainNodeEndpoint = c.globalConfig.GetString(optionNameGethURL); chainNodeEndpoint == "" {
+			return errBlockchainEndpointNotProvided
+		}
+		if walletKey = c.globalConfig.GetString(optionNameWalletKey); walletKey == "" {
+			return errors.New("wallet key not provided")
+		}
+		fundOpts = ensureFundingDefaults(clusterConfig.Funding.Export(), c.log)
+	}
+
+	inCluster := c.globalConfig.GetBool(optionNameInCluster)
+
+	nodes := make(map[string]desiredNode, len(desired))
+	for _, d := range desired {
+		nodes[d.Name] = d
+	}
+
+	var fundAddresses []string
+
+	// changes are applied one by one, so that the cluster stays available
+	for _, change := range plan {
+		switch change.Type {
+		case orchestrationK8S.ChangeAdd:
+			d := nodes[change.Node]
+			ng, err := cluster.NodeGroup(d.NodeGroup)
+			if err != nil {
+				return fmt.Errorf("get node group: %w", err)
+			}
+
+			ethAddress, err := ng.DeployNode(ctx, d.Name, inCluster, d.options)
+			if err != nil {
+				return fmt.Errorf("deploy node %s: %w", d.Name, err)
+			}
+			fundAddresses = append(fundAddresses, ethAddress)
+		case orchestrationK8S.ChangeUpdate:
+			d := nodes[change.Node]
+			ng, err := cluster.NodeGroup(d.NodeGroup)
+			if err != nil {
+				return fmt.Errorf("get node group: %w", err)
+			}
+
+			nodeOpts := d.options
+			if !d.keys {
+				if nodeOpts.LibP2PKey, nodeOpts.SwarmKey, err = orchestrationK8S.NodeKeys(ctx, c.k8sClient, d.Name, namespace); err != nil {
+					return err
+				}
+			}
+
+			// the selector and volume claims of a statefulset can not be
+			// updated, persistent volumes are kept when it is deleted
+			if change.Recreate {
+				if err := c.k8sClient.StatefulSet.Delete(ctx, d.Name, namespace); err != nil {
+					return fmt.Errorf("delete statefulset of node %s: %w", d.Name, err)
+				}
+			}
+
+			if err := ng.UpdateNode(ctx, d.Name, inCluster, nodeOpts); err != nil {
+				return fmt.Errorf("update node %s: %w", d.Name, err)
+			}
+		case orchestrationK8S.ChangeRemove:
+			if err := cluster.DeleteNode(ctx, change.Node); err != nil {
+				return fmt.Errorf("delete node %s: %w", change.Node, err)
+			}
+		}
+	}
+
+	if len(fundAddresses) > 0 {
+		if err := fund(ctx, fundAddresses, chainNodeEndpoint, walletKey, fundOpts, c.log); err != nil {
+			return fmt.Errorf("fund added nodes: %w", err)
+		}
+		c.log.Infof("added nodes funded")
+	}
+
+	c.log.Infof("cluster %s applied", clusterName)
+
+	return nil
+}
+
+// desiredNodes registers node groups of the cluster configuration and returns
+// their nodes, bootnodes first, with the same options that setupCluster uses
+func desiredNodes(clusterConfig config.Cluster, cfg *config.Config, cluster orchestration.Cluster) (nodes []desiredNode, err error) {
+	nodeGroups := clusterConfig.GetNodeGroups()
+
+	ngNames := make([]string, 0, len(nodeGroups))
+	for ngName := range nodeGroups {
+		ngNames = append(ngNames, ngName)
+	}
+	slices.SortFunc(ngNames, func(a, b string) int {
+		// bootnodes are deployed before other nodes
+		aBootnode, bBootnode := nodeGroups[a].Mode == bootnodeMode, nodeGroups[b].Mode == bootnodeMode
+		if aBootnode != bBootnode {
+			if aBootnode {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a, b)
+	})
+
+	var bootnodes string
+
+	for _, ngName := range ngNames {
+		v := nodeGroups[ngName]
+
+		ngConfig, ok := cfg.NodeGroups[v.Config]
+		if !ok {
+			return nil, fmt.Errorf("node group profile %s not defined", v.Config)
+		}
+		ngOptions := ngConfig.Export()
+
+		beeConfig, ok := cfg.BeeConfigs[v.BeeConfig]
+		if !ok {
+			return nil, fmt.Errorf("bee profile %s not defined", v.BeeConfig)
+		}
+		bConfig := beeConfig.Export()
+
+		if v.Mode != bootnodeMode {
+			if bConfig.Bootnodes == "" {
+				bConfig.Bootnodes = bootnodes
+			}
+			ngOptions.BeeConfig = &bConfig
+		}
+
+		var netemArgs string
+		if ngOptions.NetworkProfile != nil {
+			if netemArgs, err = ngOptions.NetworkProfile.NetemArgs(); err != nil {
+				return nil, fmt.Errorf("node group %s: %w", ngName, err)
+			}
+		}
+
+		cluster.AddNodeGroup(ngName, ngOptions)
+
+		// bootnodes are configured per node, other nodes by their node group
+		add := func(name string, node config.ClusterNode, nodeConfig *orchestration.Config) error {
+			beeConfig := nodeConfig
+			if beeConfig == nil {
+				beeConfig = ngOptions.BeeConfig
+			}
+			yaml, err := beeConfig.YAML()
+			if err != nil {
+				return fmt.Errorf("node %s configuration: %w", name, err)
+			}
+
+			nodes = append(nodes, desiredNode{
+				DesiredNode: orchestrationK8S.DesiredNode{
+					Name:      name,
+					NodeGroup: ngName,
+					Image:     ngOptions.Image,
+					Config:    yaml,
+					Labels:    ngOptions.Labels,
+					Resources: orchestrationK8S.Resources{
+						LimitCPU:      ngOptions.ResourcesLimitCPU,
+						LimitMemory:   ngOptions.ResourcesLimitMemory,
+						RequestCPU:    ngOptions.ResourcesRequestCPU,
+						RequestMemory: ngOptions.ResourcesRequestMemory,
+					},
+					Persistence: orchestrationK8S.Persistence{
+						Enabled:        ngOptions.PersistenceEnabled,
+						StorageClass:   ngOptions.PersistenceStorageClass,
+						StorageRequest: ngOptions.PersistenceStorageRequest,
+					},
+					NetemArgs: netemArgs,
+				},
+				options: setupNodeOptions(node, nodeConfig),
+				keys:    node.LibP2PKey != "" || node.SwarmKey != nil,
+			})
+			return nil
+		}
+
+		for i, node := range v.Nodes {
+			nodeName := fmt.Sprintf("%s-%d", ngName, i)
+			if len(node.Name) > 0 {
+				nodeName = node.Name
+			}
+
+			if v.Mode != bootnodeMode {
+				if err := add(nodeName, node, nil); err != nil {
+					return nil, err
+				}
+				continue
+			}
+
+			nodeConfig := bConfig
+			nodeConfig.Bootnodes = fmt.Sprintf(node.Bootnodes, clusterConfig.GetNamespace())
+			bootnodes = nodeConfig.Bootnodes
+			if err := add(nodeName, node, &nodeConfig); err != nil {
+				return nil, err
+			}
+		}
+
+		if v.Mode != bootnodeMode && len(v.Nodes) == 0 {
+			for i := range v.Count {
+				if err := add(fmt.Sprintf("%s-%d", ngName, i), config.ClusterNode{}, nil); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	return nodes, nil
+}
+
+// confirm asks the question and returns whether it is answered with yes
+func confirm(in io.Reader, out io.Writer, question string) (bool, error) {
+	if _, err := fmt.Fprintf(out, "%s [y/N]: ", question); err != nil {
+		return false, err
+	}
+
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false, fmt.Errorf("read answer: %w", err)
+	}
+
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	default:
+		return false, nil
+	}
+}
@@ -3,13 +3,22 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"os"
+	"os/signal"
 	"strings"
+	"sync"
+	"syscall"
 	"time"
 
+	"github.com/ethersphere/beekeeper/pkg/artifacts"
+	"github.com/ethersphere/beekeeper/pkg/chaos"
 	"github.com/ethersphere/beekeeper/pkg/check"
 	"github.com/ethersphere/beekeeper/pkg/config"
-	"github.com/ethersphere/beekeeper/pkg/metrics"
+	"github.com/ethersphere/beekeeper/pkg/orchestration"
+	orchestrationK8S "github.com/ethersphere/beekeeper/pkg/orchestration/k8s"
+	"github.com/ethersphere/beekeeper/pkg/scheduler"
 	"github.com/ethersphere/beekeeper/pkg/tracing"
+	"github.com/opentracing/opentracing-go"
 	"github.com/prometheus/client_golang/prometheus/push"
 	"github.com/spf13/cobra"
 )
@@ -22,6 +31,19 @@ func (c *command) initCheckCmd() error {
 		optionNameSeed                 = "seed"
 		optionNameTimeout              = "timeout"
 		optionNameMetricsPusherAddress = "metrics-pusher-address"
+		optionNameMaxParallel          = "max-parallel"
+		optionNameSuite                = "suite"
+		optionNameTags                 = "tags"
+		optionNamePlan                 = "plan"
+		optionNamePlanFormat           = "plan-format"
+		optionNameInterval             = "interval"
+		optionNameIterations           = "iterations"
+		optionNameArtifactsDir         = "artifacts-dir"
+		optionNameArtifactsLogLines    = "artifacts-log-lines"
+		optionNameNamespace            = "namespace"
+		optionNameLabelSelector        = "label-selector"
+		optionNameGroupLabel           = "group-label"
+		optionNameChaos                = "chaos"
 	)
 
 	cmd := &cobra.Command{
@@ -38,48 +60,107 @@ The check command executes various tests against your Bee cluster including:
 • and many more...
 
 Use --checks flag to specify which tests to run, or run all tests sequentially.
+Use --suite to run a named suite of checks defined in the configuration, and
+--tags to select checks by their tags (e.g. --tags smoke,!slow).
+Use --max-parallel to run independent checks concurrently. Checks can declare
+depends-on and parallel-group in their configuration to control ordering.
 Use --create-cluster to automatically create a cluster before testing.
-Use --metrics-enabled to collect and push metrics to Prometheus.`,
+Use --namespace to run checks against a cluster that was not created by beekeeper,
+for example one deployed with Helm. Its nodes are discovered by --label-selector
+and grouped into node groups by the pod label set with --group-label.
+Use --metrics-enabled to collect and push metrics to Prometheus.
+Use --report-file with --report-format junit|json to write a result report for CI systems.
+Use --history-db to store results of the run for the history command.
+Use --plan to print the fully resolved options of the selected checks without running them.
+Use --chaos to kill pods and partition node groups while the checks run, as
+defined by the named chaos profile in the configuration.
+Use --interval to run checks continuously, optionally limited with --iterations.
+The timeout then applies to every iteration, and SIGTERM stops the command gracefully.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return c.withTimeoutHandler(cmd, func(ctx context.Context) error {
-				checks := c.globalConfig.GetStringSlice(optionNameChecks)
-				if len(checks) == 0 {
-					return fmt.Errorf("no checks provided")
+			interval := c.globalConfig.GetDuration(optionNameInterval)
+			if interval < 0 {
+				return fmt.Errorf("--%s must not be negative", optionNameInterval)
+			}
+			if c.globalConfig.GetInt(optionNameIterations) < 0 {
+				return fmt.Errorf("--%s must not be negative", optionNameIterations)
+			}
+			if c.globalConfig.GetInt64(optionNameArtifactsLogLines) < 0 {
+				return fmt.Errorf("--%s must not be negative", optionNameArtifactsLogLines)
+			}
+
+			execute := func(ctx context.Context) error {
+				checks, err := c.selectChecks(
+					c.globalConfig.GetStringSlice(optionNameChecks),
+					cmd.Flags().Changed(optionNameChecks),
+					c.globalConfig.GetString(optionNameSuite),
+					c.globalConfig.GetStringSlice(optionNameTags),
+				)
+				if err != nil {
+					return err
 				}
 
-				clusterName := c.globalConfig.GetString(optionNameClusterName)
-				if clusterName == "" {
-					return errMissingClusterName
+				// set global config
+				checkGlobalConfig := config.CheckGlobalConfig{
+					Seed:    c.globalConfig.GetInt64(optionNameSeed),
+					GethURL: c.globalConfig.GetString(optionNameGethURL),
+				}
+
+				if c.globalConfig.GetBool(optionNamePlan) {
+					format, err := check.ParsePlanFormat(c.globalConfig.GetString(optionNamePlanFormat))
+					if err != nil {
+						return err
+					}
+
+					planned, err := check.NewCheckRunner(checkGlobalConfig, c.config.Checks, nil, nil, nil, c.log).Plan(checks)
+					if err != nil {
+						return err
+					}
+
+					return check.WritePlan(cmd.OutOrStdout(), planned, format)
+				}
+
+				if err := c.validateReportFlags(); err != nil {
+					return err
 				}
 
-				// set cluster config
-				cfgCluster, ok := c.config.Clusters[clusterName]
-				if !ok {
-					return fmt.Errorf("cluster %s not defined", clusterName)
+				var chaosOpts *chaos.Options
+				if name := c.globalConfig.GetString(optionNameChaos); name != "" {
+					chaosConfig, ok := c.config.Chaos[name]
+					if !ok {
+						return fmt.Errorf("chaos %s not defined", name)
+					}
+					if c.k8sClient == nil {
+						return fmt.Errorf("kubernetes support must be enabled for chaos")
+					}
+					o := chaosConfig.Export(checkGlobalConfig.Seed)
+					chaosOpts = &o
 				}
 
-				cluster, err := c.setupCluster(ctx, clusterName, c.globalConfig.GetBool(optionNameCreateCluster))
+				clusterName := c.globalConfig.GetString(optionNameClusterName)
+				namespace := c.globalConfig.GetString(optionNameNamespace)
+
+				setupCtx, cancelSetup := withOptionalTimeout(ctx, c.globalConfig.GetDuration(optionNameTimeout))
+				var cluster orchestration.Cluster
+				if namespace != "" {
+					// clusters that were not created by beekeeper are discovered
+					// by the label selector, the cluster name is optional
+					cluster, err = c.discoverCluster(setupCtx, clusterName, namespace, c.globalConfig.GetString(optionNameLabelSelector), c.globalConfig.GetString(optionNameGroupLabel))
+				} else {
+					cluster, err = c.setupCluster(setupCtx, clusterName, c.globalConfig.GetBool(optionNameCreateCluster))
+				}
+				cancelSetup()
 				if err != nil {
 					return fmt.Errorf("cluster setup: %w", err)
 				}
 
-				var (
-					metricsPusher  *push.Pusher
-					metricsEnabled = c.globalConfig.GetBool(optionNameMetricsEnabled)
-					cleanup        func()
-				)
-
-				if metricsEnabled {
-					metricsPusher, cleanup = newMetricsPusher(c.globalConfig.GetString(optionNameMetricsPusherAddress), cfgCluster.GetNamespace(), c.log)
+				var metricsPusher *push.Pusher
+				if c.globalConfig.GetBool(optionNameMetricsEnabled) {
+					var cleanup func()
+					metricsPusher, cleanup = newMetricsPusher(c.globalConfig.GetString(optionNameMetricsPusherAddress), cluster.Namespace(), c.metricsRegistry, c.log)
 					// cleanup executes when the calling context terminates
 					defer cleanup()
 				}
 
-				// logger metrics
-				if l, ok := c.log.(metrics.Reporter); ok && metricsEnabled {
-					metrics.RegisterCollectors(metricsPusher, l.Report()...)
-				}
-
 				// tracing
 				tracingEndpoint := c.globalConfig.GetString(optionNameTracingEndpoint)
 				if c.globalConfig.IsSet(optionNameTracingHost) && c.globalConfig.IsSet(optionNameTracingPort) {
@@ -95,29 +176,265 @@ Use --metrics-enabled to collect and push metrics to Prometheus.`,
 				}
 				defer tracerCloser.Close()
 
-				// set global config
-				checkGlobalConfig := config.CheckGlobalConfig{
-					Seed:    c.globalConfig.GetInt64(optionNameSeed),
-					GethURL: c.globalConfig.GetString(optionNameGethURL),
+				runnerOpts := []check.CheckRunnerOption{
+					check.WithMaxParallel(c.globalConfig.GetInt(optionNameMaxParallel)),
+					check.WithSkipTeardown(c.globalConfig.GetBool(optionNameSkipTeardown)),
+					check.WithTracker(c.statusTracker),
 				}
 
-				checkRunner := check.NewCheckRunner(checkGlobalConfig, c.config.Checks, cluster, metricsPusher, tracer, c.log)
+				if c.globalConfig.GetString(optionNameHistoryDB) != "" {
+					store, err := c.openHistory()
+					if err != nil {
+						return err
+					}
+					defer store.Close()
+					runnerOpts = append(runnerOpts, check.WithHistory(store))
+				}
+
+				if dir := c.globalConfig.GetString(optionNameArtifactsDir); dir != "" {
+					collector := artifacts.NewCollector(dir, cluster, c.k8sClient, c.globalConfig.GetInt64(optionNameArtifactsLogLines), c.log)
+					runnerOpts = append(runnerOpts, check.WithArtifacts(collector))
+				}
+
+				checkRunner := check.NewCheckRunner(checkGlobalConfig, c.config.Checks, cluster, c.metricsRegistry, tracer, c.log, runnerOpts...)
+
+				runChecks := func(ctx context.Context) error {
+					run := func(ctx context.Context) error {
+						return checkRunner.Run(ctx, checks)
+					}
+
+					var runErr error
+					if chaosOpts != nil {
+						runErr = c.runWithChaos(ctx, cluster, tracer, *chaosOpts, run)
+					} else {
+						runErr = run(ctx)
+					}
+
+					if err := c.writeReport(checkRunner.Report()); err != nil {
+						if runErr != nil {
+							c.log.Errorf("report: %v", err)
+							return runErr
+						}
+						return err
+					}
+
+					return runErr
+				}
+
+				if interval == 0 {
+					return runChecks(ctx)
+				}
+
+				return c.executeIterations(ctx, interval, c.globalConfig.GetInt(optionNameIterations), func(ctx context.Context) error {
+					ctx, cancel := withOptionalTimeout(ctx, c.globalConfig.GetDuration(optionNameTimeout))
+					defer cancel()
+
+					err := runChecks(ctx)
+
+					// push metrics of the iteration without waiting for the periodic flush
+					if metricsPusher != nil {
+						if err := metricsPusher.Push(); err != nil {
+							c.log.Debugf("metrics pusher push: %v", err)
+						}
+					}
+
+					return err
+				})
+			}
+
+			if interval == 0 {
+				return c.withTimeoutHandler(cmd, execute)
+			}
+
+			// in continuous mode the timeout applies to the cluster setup and
+			// every iteration separately, and termination signals stop the
+			// iterations gracefully
+			ctx, stop := signal.NotifyContext(cmd.Context(), syscall.SIGTERM, os.Interrupt)
+			defer stop()
 
-				return checkRunner.Run(ctx, checks)
-			})
+			return execute(ctx)
+		},
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			plan, _ := cmd.Flags().GetBool(optionNamePlan)
+			// checks are defined in the configuration directory, which is
+			// loaded by the root command only with the cluster name, so it is
+			// loaded here for planning and for clusters discovered in a
+			// namespace
+			if (plan || cmd.Flags().Changed(optionNameNamespace)) && c.config == nil {
+				if err := c.loadConfigDirectory(); err != nil {
+					return fmt.Errorf("loading configuration directory: %w", err)
+				}
+			}
+			if plan {
+				// planning does not use the cluster, so clients are not created
+				return c.globalConfig.BindPFlags(cmd.Flags())
+			}
+			return c.preRunE(cmd, args)
 		},
-		PreRunE: c.preRunE,
 	}
 
-	cmd.Flags().String(optionNameClusterName, "", "cluster name. Required")
+	cmd.Flags().String(optionNameClusterName, "", "cluster name. Required unless namespace is set")
+	cmd.Flags().String(optionNameNamespace, "", "namespace of a running cluster to discover nodes in, instead of the cluster defined by cluster name")
+	cmd.Flags().String(optionNameLabelSelector, beeLabelSelector, "label selector of pods of discovered nodes (only used with namespace)")
+	cmd.Flags().String(optionNameGroupLabel, orchestrationK8S.DefaultGroupLabel, "pod label by which discovered nodes are grouped into node groups (only used with namespace)")
 	cmd.Flags().String(optionNameMetricsPusherAddress, "pushgateway.staging.internal", "prometheus metrics pusher address")
 	cmd.Flags().Bool(optionNameCreateCluster, false, "creates cluster before executing checks")
 	cmd.Flags().StringSlice(optionNameChecks, []string{"pingpong"}, "list of checks to execute")
 	cmd.Flags().Bool(optionNameMetricsEnabled, true, "enable metrics")
 	cmd.Flags().Int64(optionNameSeed, -1, "seed, -1 for random")
 	cmd.Flags().Duration(optionNameTimeout, 30*time.Minute, "timeout")
+	cmd.Flags().Int(optionNameMaxParallel, 1, "maximum number of checks to run concurrently")
+	cmd.Flags().String(optionNameSuite, "", "name of the check suite to execute")
+	cmd.Flags().StringSlice(optionNameTags, nil, "select checks by tags, tags prefixed with ! exclude checks")
+	cmd.MarkFlagsMutuallyExclusive(optionNameChecks, optionNameSuite)
+	cmd.MarkFlagsMutuallyExclusive(optionNameNamespace, optionNameCreateCluster)
+	cmd.Flags().Bool(optionNamePlan, false, "print resolved options of the selected checks without running them")
+	cmd.Flags().String(optionNamePlanFormat, string(check.PlanFormatYAML), "plan output format: yaml or json")
+	cmd.Flags().String(optionNameHistoryDB, "", "path of the run history database file, results are not stored if empty")
+	cmd.Flags().Duration(optionNameInterval, 0, "interval between runs in continuous mode, checks are run once if 0")
+	cmd.Flags().Int(optionNameIterations, 0, "number of runs in continuous mode, 0 for no limit")
+	cmd.Flags().String(optionNameArtifactsDir, "", "directory to write diagnostics bundles of failed checks to, bundles are not collected if empty")
+	cmd.Flags().Int64(optionNameArtifactsLogLines, artifacts.DefaultLogLines, "number of the most recent log lines collected per container")
+	cmd.Flags().String(optionNameChaos, "", "name of the chaos profile to run alongside the checks, no chaos if empty")
+	addReportFlags(cmd)
 
 	c.root.AddCommand(cmd)
 
 	return nil
 }
+
+// executeIterations runs the action periodically with the given interval
+// until the number of iterations is reached, or the context is canceled. If
+// iterations is 0, there is no limit. It returns an error if any iteration
+// failed.
+func (c *command) executeIterations(ctx context.Context, interval time.Duration, iterations int, action func(ctx context.Context) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu                sync.Mutex // held while an iteration is running
+		iteration, failed int
+	)
+
+	periodicExecutor := scheduler.NewPeriodicExecutor(interval, c.log)
+	periodicExecutor.Start(ctx, func(ctx context.Context) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		iteration++
+		c.log.Infof("starting iteration %d", iteration)
+
+		if err := action(ctx); err != nil {
+			failed++
+			c.log.Errorf("iteration %d failed: %v", iteration, err)
+		} else {
+			c.log.Infof("iteration %d succeeded", iteration)
+		}
+
+		if iterations > 0 && iteration >= iterations {
+			cancel()
+		} else if ctx.Err() == nil {
+			c.log.Infof("next iteration in %s", interval)
+		}
+		return nil
+	})
+
+	<-ctx.Done()
+
+	if err := periodicExecutor.Close(); err != nil {
+		c.log.Errorf("failed to close periodic executor: %v", err)
+	}
+
+	// wait for the current iteration to finish, as closing the executor
+	// does not wait for teardown of long running checks
+	mu.Lock()
+	defer mu.Unlock()
+
+	c.log.Infof("stopped after %d iterations, %d failed", iteration, failed)
+
+	if failed > 0 {
+		return fmt.Errorf("%d/%d iterations failed", failed, iteration)
+	}
+
+	return nil
+}
+
+// runWithChaos runs the action while chaos disrupts the cluster, and stops
+// the chaos when the action returns. If the action fails, the chaos events are
+// logged, so that the failure can be matched to the disruptions.
+func (c *command) runWithChaos(ctx context.Context, cluster orchestration.Cluster, tracer opentracing.Tracer, o chaos.Options, action func(ctx context.Context) error) error {
+	ch, err := chaos.New(cluster, c.k8sClient, tracer, c.log, o)
+	if err != nil {
+		return fmt.Errorf("chaos: %w", err)
+	}
+
+	chaosCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	chaosErrC := make(chan error, 1)
+	go func() {
+		chaosErrC <- ch.Run(chaosCtx)
+	}()
+
+	err = action(ctx)
+
+	cancel()
+	if chaosErr := <-chaosErrC; chaosErr != nil {
+		c.log.Errorf("chaos: %v", chaosErr)
+		if err == nil {
+			err = fmt.Errorf("chaos: %w", chaosErr)
+		}
+	}
+
+	if err != nil {
+		for _, e := range ch.Events() {
+			c.log.Infof("chaos event at %s: %s of nodes %s", e.Time.Format(time.RFC3339), e.Type, strings.Join(e.Targets, ", "))
+		}
+	}
+
+	return err
+}
+
+// withOptionalTimeout returns a context with the timeout, or a context
+// without a deadline if the timeout is not positive.
+func withOptionalTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout > 0 {
+		return context.WithTimeout(ctx, timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// selectChecks resolves the list of checks to execute from the checks flag,
+// a suite and a tag expression, and logs the result.
+func (c *command) selectChecks(checks []string, checksSet bool, suite string, tags []string) (selected []string, err error) {
+	switch {
+	case suite != "":
+		if selected, err = c.config.SuiteChecks(suite); err != nil {
+			return nil, err
+		}
+	case checksSet || len(tags) == 0:
+		for _, name := range checks {
+			selected = append(selected, strings.TrimSpace(name))
+		}
+	default:
+		selected = c.config.CheckNames()
+	}
+
+	if len(tags) > 0 {
+		if selected, err = c.config.FilterChecksByTags(selected, tags); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("no checks provided")
+	}
+
+	c.log.Infof("resolved %d checks: %s", len(selected), strings.Join(selected, ", "))
+
+	return selected, nil
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckNamespaceLoadsConfig(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	configDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(configDir, "checks.yaml"), []byte(`
+checks:
+  ci-pingpong:
+    type: pingpong
+    tags: [smoke]
+  ci-settlements:
+    type: settlements
+`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name string
+		args []string
+	}{
+		{name: "tags", args: []string{"--tags", "smoke"}},
+		{name: "default checks", args: nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := newCommand()
+			if err != nil {
+				t.Fatalf("new command: %v", err)
+			}
+
+			// discovery fails without the kubernetes client, after checks
+			// were selected from the configuration directory
+			c.root.SetArgs(append([]string{
+				"check",
+				"--namespace", "foo",
+				"--config-dir", configDir,
+				"--enable-k8s=false",
+				"--metrics-enabled=false",
+				"--log-verbosity", "0",
+			}, tc.args...))
+
+			err = c.Execute()
+			if err == nil || !strings.Contains(err.Error(), "kubernetes client is required") {
+				t.Fatalf("execute: got error %v, want kubernetes client required", err)
+			}
+			if c.config == nil {
+				t.Fatal("configuration directory not loaded")
+			}
+			if _, ok := c.config.Checks["ci-pingpong"]; !ok {
+				t.Fatal("check ci-pingpong not loaded from the configuration directory")
+			}
+		})
+	}
+}
@@ -9,10 +9,16 @@ import (
 	"github.com/ethersphere/beekeeper/pkg/logging"
 	"github.com/ethersphere/beekeeper/pkg/orchestration"
 	orchestrationK8S "github.com/ethersphere/beekeeper/pkg/orchestration/k8s"
+	"github.com/ethersphere/beekeeper/pkg/orchestration/local"
 	"github.com/ethersphere/node-funder/pkg/funder"
 )
 
-const bootnodeMode string = "bootnode"
+const (
+	bootnodeMode string = "bootnode"
+
+	backendK8S   = "k8s"
+	backendLocal = "local"
+)
 
 type nodeResult struct {
 	ethAddress string
@@ -29,7 +35,15 @@ func (c *command) deleteCluster(ctx context.Context, clusterName string, cfg *co
 		return fmt.Errorf("cluster %s not defined", clusterName)
 	}
 
-	cluster := orchestrationK8S.NewCluster(clusterConfig.GetName(), clusterConfig.Export(), c.k8sClient, c.swapClient, c.log)
+	cluster, err := c.newCluster(clusterConfig)
+	if err != nil {
+		return err
+	}
+
+	if c.globalConfig.GetString(optionNameBackend) == backendLocal {
+		// local nodes are deleted together with their data
+		deleteStorage = false
+	}
 
 	// delete node groups
 	for ngName, v := range clusterConfig.GetNodeGroups() {
@@ -144,7 +158,10 @@ func (c *command) setupCluster(ctx context.Context, clusterName string, startClu
 		fundOpts = ensureFundingDefaults(clusterConfig.Funding.Export(), c.log)
 	}
 
-	cluster = orchestrationK8S.NewCluster(clusterConfig.GetName(), clusterConfig.Export(), c.k8sClient, c.swapClient, c.log)
+	cluster, err = c.newCluster(clusterConfig)
+	if err != nil {
+		return nil, err
+	}
 
 	inCluster := c.globalConfig.GetBool(optionNameInCluster)
 
@@ -180,6 +197,43 @@ func (c *command) setupCluster(ctx context.Context, clusterName string, startClu
 	return cluster, nil
 }
 
+// newCluster returns the cluster with nodes run by the selected backend
+func (c *command) newCluster(clusterConfig config.Cluster) (*orchestrationK8S.Cluster, error) {
+	switch backend := c.globalConfig.GetString(optionNameBackend); backend {
+	case backendK8S:
+		return orchestrationK8S.NewCluster(clusterConfig.GetName(), clusterConfig.Export(), c.k8sClient, c.swapClient, c.log, orchestrationK8S.WithAPIMetrics(c.apiMetrics)), nil
+	case backendLocal:
+		nodeOrchestrator := local.NewNodeOrchestrator(local.Options{
+			Dir:     c.globalConfig.GetString(optionNameLocalDir),
+			BeePath: c.globalConfig.GetString(optionNameBeePath),
+		}, c.log)
+		return orchestrationK8S.NewCluster(clusterConfig.GetName(), clusterConfig.Export(), nil, c.swapClient, c.log, orchestrationK8S.WithNodeOrchestrator(nodeOrchestrator), orchestrationK8S.WithAPIMetrics(c.apiMetrics)), nil
+	default:
+		return nil, fmt.Errorf("unsupported backend %s: must be %s or %s", backend, backendK8S, backendLocal)
+	}
+}
+
+// discoverCluster returns the cluster of running Bee nodes in the namespace
+// that match the label selector, for clusters that were not created by
+// beekeeper.
+func (c *command) discoverCluster(ctx context.Context, clusterName, namespace, labelSelector, groupLabel string) (orchestration.Cluster, error) {
+	if c.k8sClient == nil {
+		return nil, errors.New("kubernetes client is required to discover the cluster")
+	}
+
+	cluster, err := orchestrationK8S.DiscoverCluster(ctx, clusterName, orchestrationK8S.DiscoveryOptions{
+		Namespace:     namespace,
+		LabelSelector: labelSelector,
+		GroupLabel:    groupLabel,
+		InCluster:     c.globalConfig.GetBool(optionNameInCluster),
+	}, c.k8sClient, c.swapClient, c.log, orchestrationK8S.WithAPIMetrics(c.apiMetrics))
+	if err != nil {
+		return nil, err
+	}
+
+	return cluster, nil
+}
+
 func ensureFundingDefaults(fundOpts orchestration.FundingOptions, log logging.Logger) orchestration.FundingOptions {
 	if fundOpts.Eth == 0 {
 		fundOpts.Eth = 0.1 // default eth value
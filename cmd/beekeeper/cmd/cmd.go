@@ -16,20 +16,26 @@ import (
 	"github.com/ethersphere/beekeeper/pkg/httpx"
 	"github.com/ethersphere/beekeeper/pkg/k8s"
 	"github.com/ethersphere/beekeeper/pkg/logging"
+	"github.com/ethersphere/beekeeper/pkg/metrics"
 	"github.com/ethersphere/beekeeper/pkg/node"
+	"github.com/ethersphere/beekeeper/pkg/orchestration/local"
 	"github.com/ethersphere/beekeeper/pkg/scheduler"
+	"github.com/ethersphere/beekeeper/pkg/status"
 	"github.com/ethersphere/beekeeper/pkg/swap"
 	"github.com/go-git/go-billy/v5/memfs"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
 	httptransport "github.com/go-git/go-git/v5/plumbing/transport/http"
 	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
 const (
+	optionNameBackend            = "backend"
+	optionNameBeePath            = "bee-path"
 	optionNameConfigDir          = "config-dir"
 	optionNameConfigGitBranch    = "config-git-branch"
 	optionNameConfigGitDir       = "config-git-dir"
@@ -40,8 +46,12 @@ const (
 	optionNameGethURL            = "geth-url"
 	optionNameInCluster          = "in-cluster"
 	optionNameKubeconfig         = "kubeconfig"
+	optionNameLocalDir           = "local-dir"
 	optionNameLogVerbosity       = "log-verbosity"
 	optionNameLokiEndpoint       = "loki-endpoint"
+	optionNameSkipTeardown       = "skip-teardown"
+	optionNameStatusAddr         = "status-addr"
+	optionNameStatusResults      = "status-results"
 	optionNameTracingEnabled     = "tracing-enable"
 	optionNameTracingEndpoint    = "tracing-endpoint"
 	optionNameTracingHost        = "tracing-host"
@@ -69,6 +79,10 @@ type command struct {
 	k8sClient        *k8s.Client // kubernetes client
 	swapClient       swap.Client
 	log              logging.Logger
+	metricsRegistry  *prometheus.Registry // collectors of all metrics, pushed or served by the status server
+	apiMetrics       *httpx.Metrics       // metrics of API requests to nodes of clusters
+	statusTracker    *status.Tracker
+	statusServer     *status.Server
 }
 
 type option func(*command)
@@ -81,7 +95,13 @@ func newCommand(opts ...option) (c *command, err error) {
 			SilenceErrors: true,
 			SilenceUsage:  true,
 			PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
-				return c.initConfig(cmd.Flags().Changed(optionNameClusterName))
+				if err := c.initConfig(cmd.Flags().Changed(optionNameClusterName)); err != nil {
+					return err
+				}
+				return c.initStatus()
+			},
+			PersistentPostRun: func(cmd *cobra.Command, args []string) {
+				c.closeStatusServer()
 			},
 		},
 		httpClient: &http.Client{
@@ -89,12 +109,18 @@ func newCommand(opts ...option) (c *command, err error) {
 				Next: http.DefaultTransport,
 			},
 		},
+		metricsRegistry: prometheus.NewRegistry(),
+		apiMetrics:      httpx.NewMetrics(),
 	}
 
 	for _, o := range opts {
 		o(c)
 	}
 
+	if err := metrics.RegisterCollectors(c.metricsRegistry, c.apiMetrics.Report()...); err != nil {
+		return nil, fmt.Errorf("registering api metrics: %w", err)
+	}
+
 	// find home directory
 	if err := c.setHomeDir(); err != nil {
 		return nil, err
@@ -102,6 +128,10 @@ func newCommand(opts ...option) (c *command, err error) {
 
 	c.initGlobalFlags()
 
+	if err := c.initApplyCmd(); err != nil {
+		return nil, err
+	}
+
 	if err := c.initCheckCmd(); err != nil {
 		return nil, err
 	}
@@ -118,14 +148,26 @@ func newCommand(opts ...option) (c *command, err error) {
 		return nil, err
 	}
 
+	if err := c.initHistoryCmd(); err != nil {
+		return nil, err
+	}
+
 	if err := c.initNodeFunderCmd(); err != nil {
 		return nil, err
 	}
 
+	if err := c.initNetworkProfileCmd(); err != nil {
+		return nil, err
+	}
+
 	if err := c.initOperatorCmd(); err != nil {
 		return nil, err
 	}
 
+	if err := c.initScaleCmd(); err != nil {
+		return nil, err
+	}
+
 	if err := c.initStamperCmd(); err != nil {
 		return nil, err
 	}
@@ -146,6 +188,14 @@ func newCommand(opts ...option) (c *command, err error) {
 		return nil, err
 	}
 
+	if err := c.initUpgradeCmd(); err != nil {
+		return nil, err
+	}
+
+	if err := c.initTxCmd(); err != nil {
+		return nil, err
+	}
+
 	c.initVersionCmd()
 
 	return c, nil
@@ -177,6 +227,9 @@ func (c *command) initGlobalFlags() {
 	globalFlags.String(optionNameGethURL, "", "URL of the ethereum compatible blockchain RPC endpoint")
 	globalFlags.String(optionNameLogVerbosity, "info", "Log verbosity level (0=silent, 1=error, 2=warn, 3=info, 4=debug, 5=trace)")
 	globalFlags.String(optionNameLokiEndpoint, "", "HTTP endpoint for sending logs to Loki (e.g., http://loki.testnet.internal/loki/api/v1/push)")
+	globalFlags.Bool(optionNameSkipTeardown, false, "Skip teardown of checks and simulations, keeping the state they created for debugging")
+	globalFlags.String(optionNameStatusAddr, "", "Address of the HTTP status server with health, status and metrics endpoints (e.g., :8080), disabled if empty")
+	globalFlags.Int(optionNameStatusResults, status.DefaultResultsLimit, "Number of most recent check and simulation results served by the status server")
 	globalFlags.Bool(optionNameTracingEnabled, false, "Enable tracing for performance monitoring and debugging")
 	globalFlags.String(optionNameTracingEndpoint, "127.0.0.1:6831", "Endpoint for sending tracing data, specified as host:port")
 	globalFlags.String(optionNameTracingHost, "", "Host address for sending tracing data")
@@ -185,6 +238,9 @@ func (c *command) initGlobalFlags() {
 	globalFlags.Bool(optionNameEnableK8S, true, "Enable Kubernetes client functionality")
 	globalFlags.Bool(optionNameInCluster, false, "Use the in-cluster Kubernetes client")
 	globalFlags.String(optionNameKubeconfig, "~/.kube/config", "Path to the kubeconfig file")
+	globalFlags.String(optionNameBackend, backendK8S, "Backend that runs Bee nodes of clusters: k8s or local")
+	globalFlags.String(optionNameLocalDir, filepath.Join(c.homeDir, ".beekeeper-local"), "Directory for data, configuration and logs of nodes run by the local backend")
+	globalFlags.String(optionNameBeePath, local.DefaultBeePath, "Path to the bee binary run by the local backend")
 }
 
 func (c *command) bindGlobalFlags() error {
@@ -198,6 +254,9 @@ func (c *command) bindGlobalFlags() error {
 		optionNameGethURL,
 		optionNameLogVerbosity,
 		optionNameLokiEndpoint,
+		optionNameSkipTeardown,
+		optionNameStatusAddr,
+		optionNameStatusResults,
 	} {
 		if err := c.globalConfig.BindPFlag(flag, c.root.PersistentFlags().Lookup(flag)); err != nil {
 			return fmt.Errorf("binding %s flag: %w", flag, err)
@@ -268,6 +327,14 @@ func (c *command) initLogger() error {
 	}
 
 	c.log = log
+
+	// logger metrics
+	if l, ok := c.log.(metrics.Reporter); ok {
+		if err := metrics.RegisterCollectors(c.metricsRegistry, l.Report()...); err != nil {
+			return fmt.Errorf("registering logger metrics: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -385,6 +452,11 @@ func (c *command) preRunE(cmd *cobra.Command, args []string) (err error) {
 }
 
 func (c *command) setK8sClient() error {
+	if c.globalConfig.GetString(optionNameBackend) == backendLocal {
+		c.log.Info("Kubernetes client disabled for the local backend")
+		return nil
+	}
+
 	if !c.globalConfig.GetBool(optionNameEnableK8S) {
 		c.log.Info("Kubernetes client disabled. Enable it with --enable-k8s=true flag if required")
 		return nil
@@ -17,7 +17,9 @@ func (c *command) initCreateBeeCluster() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "bee-cluster",
 		Short: "creates Bee cluster",
-		Long:  `creates Bee cluster.`,
+		Long: `creates Bee cluster.
+
+Nodes are run in Kubernetes, or as local bee processes with --backend local.`,
 		RunE: func(cmd *cobra.Command, args []string) (err error) {
 			ctx, cancel := context.WithTimeout(cmd.Context(), c.globalConfig.GetDuration(optionNameTimeout))
 			defer cancel()
@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ethersphere/beekeeper/pkg/history"
+	"github.com/ethersphere/beekeeper/pkg/report"
+	"github.com/spf13/cobra"
+)
+
+const optionNameHistoryDB = "history-db"
+
+func (c *command) initHistoryCmd() (err error) {
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "Lists and compares past check runs",
+		Long: `Lists and compares check runs stored in the history database.
+
+Runs are stored by the check command when it is executed with --history-db.
+The history command provides subcommands:
+• list: List past runs with their results and Bee versions
+• compare: Compare two runs and flag checks that regressed
+
+A check regressed if it failed in the target run but not in the base run, or
+if its duration increased by more than --threshold percent.`,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			return cmd.Help()
+		},
+	}
+
+	cmd.PersistentFlags().String(optionNameHistoryDB, "", "path of the run history database file. Required")
+
+	cmd.AddCommand(c.initHistoryList())
+	cmd.AddCommand(c.initHistoryCompare())
+
+	c.root.AddCommand(cmd)
+
+	return nil
+}
+
+func (c *command) initHistoryList() *cobra.Command {
+	const optionNameLimit = "limit"
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "Lists past runs",
+		Long:  `Lists past runs stored in the history database, newest first.`,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			store, err := c.openHistory()
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			runs, err := store.Runs(c.globalConfig.GetInt(optionNameLimit))
+			if err != nil {
+				return fmt.Errorf("listing runs: %w", err)
+			}
+
+			return writeRuns(cmd.OutOrStdout(), runs)
+		},
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return c.globalConfig.BindPFlags(cmd.Flags())
+		},
+	}
+
+	cmd.Flags().Int(optionNameLimit, 20, "maximum number of runs to list, 0 for all")
+
+	return cmd
+}
+
+func (c *command) initHistoryCompare() *cobra.Command {
+	const optionNameThreshold = "threshold"
+
+	cmd := &cobra.Command{
+		Use:   "compare <base-run-id> <target-run-id>",
+		Short: "Compares two runs",
+		Long: `Compares check results of two runs stored in the history database.
+
+The command fails if any check regressed in the target run.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			baseID, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid base run id %q: %w", args[0], err)
+			}
+			targetID, err := strconv.ParseUint(args[1], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid target run id %q: %w", args[1], err)
+			}
+
+			store, err := c.openHistory()
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			base, err := store.Get(baseID)
+			if err != nil {
+				return err
+			}
+			target, err := store.Get(targetID)
+			if err != nil {
+				return err
+			}
+
+			comparisons := history.Compare(base, target, c.globalConfig.GetFloat64(optionNameThreshold))
+			if err := writeComparisons(cmd.OutOrStdout(), base, target, comparisons); err != nil {
+				return err
+			}
+
+			var regressed []string
+			for _, cmp := range comparisons {
+				if cmp.Regressed {
+					regressed = append(regressed, cmp.Name)
+				}
+			}
+			if len(regressed) > 0 {
+				return fmt.Errorf("%d checks regressed: %s", len(regressed), strings.Join(regressed, ", "))
+			}
+
+			return nil
+		},
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return c.globalConfig.BindPFlags(cmd.Flags())
+		},
+	}
+
+	cmd.Flags().Float64(optionNameThreshold, 20, "duration increase in percent above which a check is flagged as regressed")
+
+	return cmd
+}
+
+// openHistory opens the history database set with the history-db flag.
+func (c *command) openHistory() (*history.Store, error) {
+	path := c.globalConfig.GetString(optionNameHistoryDB)
+	if path == "" {
+		return nil, fmt.Errorf("--%s not set", optionNameHistoryDB)
+	}
+	return history.Open(path)
+}
+
+func writeRuns(w io.Writer, runs []history.Run) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tTIME\tCOMMAND\tCLUSTER\tDURATION\tPASSED\tFLAKY\tFAILED\tSKIPPED\tBEE VERSIONS")
+	for _, run := range runs {
+		counts := make(map[report.Status]int)
+		for _, r := range run.Results {
+			counts[r.Status]++
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%d\t%d\t%d\t%d\t%s\n",
+			run.ID,
+			run.Timestamp.Format(time.RFC3339),
+			run.Command,
+			run.Cluster,
+			run.Duration.Round(time.Second),
+			counts[report.StatusPassed],
+			counts[report.StatusFlaky],
+			counts[report.StatusFailed],
+			counts[report.StatusSkipped],
+			versions(run.BeeVersions),
+		)
+	}
+	return tw.Flush()
+}
+
+func writeComparisons(w io.Writer, base, target history.Run, comparisons []history.Comparison) error {
+	fmt.Fprintf(w, "base:   run %d at %s, bee %s\n", base.ID, base.Timestamp.Format(time.RFC3339), versions(base.BeeVersions))
+	fmt.Fprintf(w, "target: run %d at %s, bee %s\n\n", target.ID, target.Timestamp.Format(time.RFC3339), versions(target.BeeVersions))
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CHECK\tBASE STATUS\tTARGET STATUS\tBASE DURATION\tTARGET DURATION\tCHANGE\tREGRESSION")
+	for _, cmp := range comparisons {
+		baseStatus, baseDuration := resultColumns(cmp.Base)
+		targetStatus, targetDuration := resultColumns(cmp.Target)
+		change := "-"
+		if cmp.Base != nil && cmp.Target != nil {
+			change = fmt.Sprintf("%+.1f%%", cmp.DurationChange)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", cmp.Name, baseStatus, targetStatus, baseDuration, targetDuration, change, cmp.Reason)
+	}
+	return tw.Flush()
+}
+
+func resultColumns(r *history.Result) (status, duration string) {
+	if r == nil {
+		return "-", "-"
+	}
+	return string(r.Status), r.Duration.Round(time.Millisecond).String()
+}
+
+// versions returns the distinct Bee versions, sorted and comma separated.
+func versions(beeVersions map[string]string) string {
+	var vs []string
+	for _, v := range beeVersions {
+		if !slices.Contains(vs, v) {
+			vs = append(vs, v)
+		}
+	}
+	if len(vs) == 0 {
+		return "-"
+	}
+	slices.Sort(vs)
+	return strings.Join(vs, ",")
+}
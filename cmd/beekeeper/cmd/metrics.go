@@ -5,13 +5,15 @@ import (
 	"time"
 
 	"github.com/ethersphere/beekeeper/pkg/logging"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/push"
 	"github.com/prometheus/common/expfmt"
 )
 
-// newMetricsPusher returns a new metrics pusher and a cleanup function.
-func newMetricsPusher(pusherAddress, job string, logger logging.Logger) (*push.Pusher, func()) {
-	metricsPusher := push.New(pusherAddress, job)
+// newMetricsPusher returns a new metrics pusher that pushes metrics gathered
+// from the gatherer, and a cleanup function.
+func newMetricsPusher(pusherAddress, job string, gatherer prometheus.Gatherer, logger logging.Logger) (*push.Pusher, func()) {
+	metricsPusher := push.New(pusherAddress, job).Gatherer(gatherer)
 	metricsPusher.Format(expfmt.NewFormat(expfmt.TypeTextPlain))
 
 	killC := make(chan struct{})
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethersphere/beekeeper/pkg/orchestration"
+	"github.com/spf13/cobra"
+)
+
+func (c *command) initNetworkProfileCmd() (err error) {
+	const (
+		optionNameNodeGroups = "node-groups"
+		optionNameLatency    = "latency"
+		optionNameJitter     = "jitter"
+		optionNameLoss       = "loss"
+		optionNameRate       = "rate"
+	)
+
+	cmd := &cobra.Command{
+		Use:   "network-profile",
+		Short: "changes network profile of node groups of a Bee cluster",
+		Long: `Changes network profile of node groups of a running Bee cluster.
+
+The profile shapes outgoing traffic of the nodes with tc netem: latency with
+optional jitter, percentage of lost packets and bandwidth rate. Running the
+command without profile flags removes the shaping.
+
+Only nodes created from a node group with the network-profile field in its
+configuration can be changed, as the profile is applied by their netem sidecar.
+The new profile is applied once Kubernetes refreshes the node's configmap in
+the pod, which usually takes up to a minute.`,
+		Example: `beekeeper network-profile --cluster-name=default --node-groups=light --latency=200ms --jitter=50ms --loss=1 --rate=1mbit
+beekeeper network-profile --cluster-name=default --node-groups=light`,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			return c.withTimeoutHandler(cmd, func(ctx context.Context) error {
+				nodeGroups := c.globalConfig.GetStringSlice(optionNameNodeGroups)
+				if len(nodeGroups) == 0 {
+					return errors.New("node groups not provided")
+				}
+
+				profile := orchestration.NetworkProfile{
+					Latency: c.globalConfig.GetDuration(optionNameLatency),
+					Jitter:  c.globalConfig.GetDuration(optionNameJitter),
+					Loss:    c.globalConfig.GetFloat64(optionNameLoss),
+					Rate:    c.globalConfig.GetString(optionNameRate),
+				}
+				if _, err := profile.NetemArgs(); err != nil {
+					return err
+				}
+
+				cluster, err := c.setupCluster(ctx, c.globalConfig.GetString(optionNameClusterName), false)
+				if err != nil {
+					return fmt.Errorf("cluster setup: %w", err)
+				}
+
+				for _, name := range nodeGroups {
+					ng, err := cluster.NodeGroup(name)
+					if err != nil {
+						return fmt.Errorf("get node group: %w", err)
+					}
+
+					if err := ng.SetNetworkProfile(ctx, profile); err != nil {
+						return fmt.Errorf("node group %s: %w", name, err)
+					}
+					c.log.Infof("network profile of node group %s changed", name)
+				}
+
+				return nil
+			})
+		},
+		PreRunE: c.preRunE,
+	}
+
+	cmd.Flags().String(optionNameClusterName, "", "cluster name")
+	cmd.Flags().StringSlice(optionNameNodeGroups, nil, "node groups to change the network profile of")
+	cmd.Flags().Duration(optionNameLatency, 0, "delay of outgoing packets")
+	cmd.Flags().Duration(optionNameJitter, 0, "random variation of the delay, requires latency")
+	cmd.Flags().Float64(optionNameLoss, 0, "percentage of dropped outgoing packets")
+	cmd.Flags().String(optionNameRate, "", "bandwidth limit in tc units, e.g. 10mbit")
+	cmd.Flags().Duration(optionNameTimeout, 5*time.Minute, "timeout")
+
+	c.root.AddCommand(cmd)
+
+	return nil
+}
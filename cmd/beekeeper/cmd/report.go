@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/ethersphere/beekeeper/pkg/report"
+	"github.com/spf13/cobra"
+)
+
+const (
+	optionNameReportFormat = "report-format"
+	optionNameReportFile   = "report-file"
+)
+
+// addReportFlags adds flags for writing a result report to the command.
+func addReportFlags(cmd *cobra.Command) {
+	cmd.Flags().String(optionNameReportFormat, string(report.FormatJUnit), "result report format: junit or json")
+	cmd.Flags().String(optionNameReportFile, "", "path of the result report file, no report is written if empty")
+}
+
+// validateReportFlags returns an error if the report flags are not valid.
+func (c *command) validateReportFlags() error {
+	if c.globalConfig.GetString(optionNameReportFile) == "" {
+		return nil
+	}
+	_, err := report.ParseFormat(c.globalConfig.GetString(optionNameReportFormat))
+	return err
+}
+
+// writeReport writes the report to the file set with the report-file flag.
+func (c *command) writeReport(r *report.Report) error {
+	path := c.globalConfig.GetString(optionNameReportFile)
+	if path == "" || r == nil {
+		return nil
+	}
+
+	format, err := report.ParseFormat(c.globalConfig.GetString(optionNameReportFormat))
+	if err != nil {
+		return err
+	}
+
+	if err := r.WriteFile(path, format); err != nil {
+		return fmt.Errorf("writing %s report: %w", format, err)
+	}
+
+	c.log.Infof("%s report written to %s", format, path)
+	return nil
+}
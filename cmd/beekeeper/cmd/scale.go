@@ -0,0 +1,268 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ethersphere/beekeeper/pkg/config"
+	"github.com/ethersphere/beekeeper/pkg/orchestration"
+	"github.com/spf13/cobra"
+)
+
+// scaleConnectedPollInterval is the interval of checking whether added nodes are connected
+const scaleConnectedPollInterval = 5 * time.Second
+
+func (c *command) initScaleCmd() (err error) {
+	const (
+		optionNameNodeGroup   = "node-group"
+		optionNameCount       = "count"
+		optionNameWithStorage = "with-storage"
+	)
+
+	cmd := &cobra.Command{
+		Use:   "scale",
+		Short: "scales node group of a Bee cluster",
+		Long: `Scales node group of a Bee cluster to the given number of nodes.
+
+Nodes are named by the node group and their index. Missing nodes are deployed,
+funded through the Geth node with the cluster funding amounts and waited on
+until they are ready and connected to peers. Nodes with indices above the count
+are deleted, together with their persistent volumes if --with-storage is set.`,
+		Example: `beekeeper scale --cluster-name=default --node-group=bee --count=30 --geth-url=http://geth:8545
+beekeeper scale --cluster-name=default --node-group=bee --count=10 --with-storage`,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			ctx, cancel := context.WithTimeout(cmd.Context(), c.globalConfig.GetDuration(optionNameTimeout))
+			defer cancel()
+
+			return c.scaleNodeGroup(ctx,
+				c.globalConfig.GetString(optionNameClusterName),
+				c.globalConfig.GetString(optionNameNodeGroup),
+				c.globalConfig.GetInt(optionNameCount),
+				c.globalConfig.GetBool(optionNameWithStorage),
+			)
+		},
+		PreRunE: c.preRunE,
+	}
+
+	cmd.Flags().String(optionNameClusterName, "", "cluster name")
+	cmd.Flags().String(optionNameNodeGroup, "", "name of the node group in the cluster configuration")
+	cmd.Flags().Int(optionNameCount, 0, "number of nodes in the node group")
+	cmd.Flags().Bool(optionNameWithStorage, false, "delete storage of removed nodes")
+	cmd.Flags().Duration(optionNameTimeout, 30*time.Minute, "timeout")
+
+	c.root.AddCommand(cmd)
+
+	return nil
+}
+
+func (c *command) scaleNodeGroup(ctx context.Context, clusterName, ngName string, count int, deleteStorage bool) error {
+	if clusterName == "" {
+		return errMissingClusterName
+	}
+	if ngName == "" {
+		return errors.New("node group not provided")
+	}
+	if count < 0 {
+		return fmt.Errorf("invalid count %d", count)
+	}
+
+	clusterConfig, ok := c.config.Clusters[clusterName]
+	if !ok {
+		return fmt.Errorf("cluster %s not defined", clusterName)
+	}
+	if clusterConfig.IsUsingStaticEndpoints() {
+		return errors.New("static endpoints are not supported for scaling the cluster")
+	}
+
+	v, ok := clusterConfig.GetNodeGroups()[ngName]
+	if !ok {
+		return fmt.Errorf("node group %s not defined in cluster %s", ngName, clusterName)
+	}
+	if v.Mode == bootnodeMode || len(v.Nodes) > 0 {
+		return fmt.Errorf("node group %s defines its nodes by name and can not be scaled", ngName)
+	}
+
+	ngConfig, ok := c.config.NodeGroups[v.Config]
+	if !ok {
+		return fmt.Errorf("node group profile %s not defined", v.Config)
+	}
+	ngOptions := ngConfig.Export()
+
+	beeConfig, ok := c.config.BeeConfigs[v.BeeConfig]
+	if !ok {
+		return fmt.Errorf("bee profile %s not defined", v.BeeConfig)
+	}
+	bConfig := beeConfig.Export()
+	if bConfig.Bootnodes == "" {
+		bConfig.Bootnodes = clusterBootnodes(clusterConfig.GetNodeGroups(), clusterConfig.GetNamespace())
+	}
+	ngOptions.BeeConfig = &bConfig
+
+	cluster, err := c.newCluster(clusterConfig)
+	if err != nil {
+		return err
+	}
+	cluster.AddNodeGroup(ngName, ngOptions)
+
+	ng, err := cluster.NodeGroup(ngName)
+	if err != nil {
+		return fmt.Errorf("get node group: %w", err)
+	}
+
+	deployed, err := cluster.DeployedNodes(ctx)
+	if err != nil {
+		return err
+	}
+
+	existing := make(map[int]string)
+	nodeNameRe := regexp.MustCompile(`^` + regexp.QuoteMeta(ngName) + `-(\d+)$`)
+	for _, name := range deployed {
+		if m := nodeNameRe.FindStringSubmatch(name); m != nil {
+			i, err := strconv.Atoi(m[1])
+			if err != nil {
+				continue
+			}
+			existing[i] = name
+		}
+	}
+
+	c.log.Infof("scaling node group %s from %d to %d nodes", ngName, len(existing), count)
+
+	// remove nodes above the count
+	for i, name := range existing {
+		if i < count {
+			continue
+		}
+
+		if err := cluster.DeleteNode(ctx, name); err != nil {
+			return fmt.Errorf("delete node %s: %w", name, err)
+		}
+		c.log.Infof("node %s deleted", name)
+
+		if deleteStorage && c.k8sClient != nil && ngOptions.PersistenceEnabled {
+			pvcName := fmt.Sprintf("data-%s-0", name)
+			if err := c.k8sClient.PVC.Delete(ctx, pvcName, clusterConfig.GetNamespace()); err != nil {
+				return fmt.Errorf("deleting pvc %s: %w", pvcName, err)
+			}
+		}
+	}
+
+	var added []string
+	for i := range count {
+		if _, ok := existing[i]; !ok {
+			added = append(added, fmt.Sprintf("%s-%d", ngName, i))
+		}
+	}
+
+	if len(added) == 0 {
+		c.log.Infof("node group %s scaled to %d nodes", ngName, count)
+		return nil
+	}
+
+	if !c.globalConfig.IsSet(optionNameGethURL) {
+		return errBlockchainEndpointNotProvided
+	}
+	fundOpts := ensureFundingDefaults(clusterConfig.Funding.Export(), c.log)
+
+	inCluster := c.globalConfig.GetBool(optionNameInCluster)
+
+	// deploy nodes concurrently, as each of them waits to become ready
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	for _, name := range added {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			err := c.deployAndFundNode(ctx, ng, name, inCluster, fundOpts)
+
+			mu.Lock()
+			defer mu.Unlock()
+			errs = append(errs, err)
+		}()
+	}
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+
+	if err := waitConnected(ctx, ng, added, c.log.Infof); err != nil {
+		return err
+	}
+
+	c.log.Infof("node group %s scaled to %d nodes", ngName, count)
+
+	return nil
+}
+
+// deployAndFundNode deploys the node, waits for it to become ready and funds
+// it through the swap client
+func (c *command) deployAndFundNode(ctx context.Context, ng orchestration.NodeGroup, name string, inCluster bool, fundOpts orchestration.FundingOptions) error {
+	ethAddress, err := ng.DeployNode(ctx, name, inCluster, orchestration.NodeOptions{})
+	if err != nil {
+		return fmt.Errorf("deploy node %s: %w", name, err)
+	}
+
+	tx, err := c.swapClient.SendETH(ctx, ethAddress, fundOpts.Eth)
+	if err != nil {
+		return fmt.Errorf("send eth to node %s: %w", name, err)
+	}
+	c.log.Infof("node %s funded with %.2f ETH, transaction: %s", name, fundOpts.Eth, tx)
+
+	tx, err = c.swapClient.SendBZZ(ctx, ethAddress, fundOpts.Bzz)
+	if err != nil {
+		return fmt.Errorf("send bzz to node %s: %w", name, err)
+	}
+	c.log.Infof("node %s funded with %.2f BZZ, transaction: %s", name, fundOpts.Bzz, tx)
+
+	return nil
+}
+
+// waitConnected waits until each of the nodes is connected to at least one peer
+func waitConnected(ctx context.Context, ng orchestration.NodeGroup, names []string, logf func(string, ...any)) error {
+	for _, name := range names {
+		client, err := ng.NodeClient(name)
+		if err != nil {
+			return fmt.Errorf("node %s client: %w", name, err)
+		}
+
+		for {
+			peers, err := client.Peers(ctx)
+			if err == nil && len(peers) > 0 {
+				logf("node %s is connected to %d peers", name, len(peers))
+				break
+			}
+
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("wait for node %s to connect: %w", name, ctx.Err())
+			case <-time.After(scaleConnectedPollInterval):
+			}
+		}
+	}
+
+	return nil
+}
+
+// clusterBootnodes returns bootnodes of the cluster's bootnode node groups, the
+// same way setupBootnodes sets them for other node groups
+func clusterBootnodes(nodeGroups map[string]config.ClusterNodeGroup, namespace string) (bootnodes string) {
+	for _, v := range nodeGroups {
+		if v.Mode != bootnodeMode {
+			continue
+		}
+		for _, node := range v.Nodes {
+			bootnodes = fmt.Sprintf(node.Bootnodes, namespace)
+		}
+	}
+	return bootnodes
+}
@@ -9,8 +9,11 @@ import (
 	"github.com/ethersphere/beekeeper/pkg/beekeeper"
 	"github.com/ethersphere/beekeeper/pkg/config"
 	"github.com/ethersphere/beekeeper/pkg/metrics"
+	"github.com/ethersphere/beekeeper/pkg/orchestration"
+	"github.com/ethersphere/beekeeper/pkg/report"
+	"github.com/ethersphere/beekeeper/pkg/status"
 	"github.com/ethersphere/beekeeper/pkg/tracing"
-	"github.com/prometheus/client_golang/prometheus/push"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/spf13/cobra"
 )
 
@@ -50,6 +53,10 @@ Use --metrics-enabled to collect performance metrics during simulation.`,
 				return errMissingClusterName
 			}
 
+			if err := c.validateReportFlags(); err != nil {
+				return err
+			}
+
 			// set cluster config
 			cfgCluster, ok := c.config.Clusters[clusterName]
 			if !ok {
@@ -61,23 +68,12 @@ Use --metrics-enabled to collect performance metrics during simulation.`,
 				return fmt.Errorf("cluster setup: %w", err)
 			}
 
-			var (
-				metricsPusher  *push.Pusher
-				metricsEnabled = c.globalConfig.GetBool(optionNameMetricsEnabled)
-				cleanup        func()
-			)
-
-			if metricsEnabled {
-				metricsPusher, cleanup = newMetricsPusher(c.globalConfig.GetString(optionNameMetricsPusherAddress), cfgCluster.GetNamespace(), c.log)
+			if c.globalConfig.GetBool(optionNameMetricsEnabled) {
+				_, cleanup := newMetricsPusher(c.globalConfig.GetString(optionNameMetricsPusherAddress), cfgCluster.GetNamespace(), c.metricsRegistry, c.log)
 				// cleanup executes when the calling context terminates
 				defer cleanup()
 			}
 
-			// logger metrics
-			if l, ok := c.log.(metrics.Reporter); ok && metricsEnabled {
-				metrics.RegisterCollectors(metricsPusher, l.Report()...)
-			}
-
 			// tracing
 			tracingEndpoint := c.globalConfig.GetString(optionNameTracingEndpoint)
 			if c.globalConfig.IsSet(optionNameTracingHost) && c.globalConfig.IsSet(optionNameTracingPort) {
@@ -98,6 +94,23 @@ Use --metrics-enabled to collect performance metrics during simulation.`,
 				Seed: c.globalConfig.GetInt64(optionNameSeed),
 			}
 
+			simulationsReport := &report.Report{
+				Name:      "simulate",
+				Cluster:   clusterName,
+				Seed:      simulationGlobalConfig.Seed,
+				Timestamp: time.Now(),
+			}
+			defer func() {
+				simulationsReport.Duration = time.Since(simulationsReport.Timestamp)
+				if rerr := c.writeReport(simulationsReport); rerr != nil {
+					if err != nil {
+						c.log.Errorf("report: %v", rerr)
+						return
+					}
+					err = rerr
+				}
+			}()
+
 			// run simulations
 			for _, simulationName := range c.globalConfig.GetStringSlice(optionNameSimulations) {
 				// get configuration
@@ -112,22 +125,55 @@ Use --metrics-enabled to collect performance metrics during simulation.`,
 					return fmt.Errorf("simulation %s not implemented", simulationConfig.Type)
 				}
 
-				// create simulation options
-				o, err := simulation.NewOptions(simulationGlobalConfig, simulationConfig)
+				// expand matrix into variants
+				variants, err := simulationConfig.Variants(simulationName)
 				if err != nil {
-					return fmt.Errorf("creating simulation %s options: %w", simulationName, err)
-				}
-
-				// create simulation
-				sim := simulation.NewAction(c.log)
-				if s, ok := sim.(metrics.Reporter); ok && metricsEnabled {
-					metrics.RegisterCollectors(metricsPusher, s.Report()...)
+					return err
 				}
-				sim = beekeeper.NewActionMiddleware(tracer, sim, simulationName)
 
-				// run simulation
-				if err := sim.Run(ctx, cluster, o); err != nil {
-					return fmt.Errorf("running simulation %s: %w", simulationName, err)
+				for _, v := range variants {
+					// create simulation of the variant, with its metrics
+					// labeled by the variant
+					action := simulation.NewAction(c.log)
+					if s, ok := action.(metrics.Reporter); ok {
+						registry := prometheus.WrapRegistererWith(prometheus.Labels{"simulation": v.Name}, c.metricsRegistry)
+						if err := metrics.RegisterCollectors(registry, s.Report()...); err != nil {
+							c.log.Warningf("registering %s simulation metrics: %v", v.Name, err)
+						}
+					}
+
+					// create simulation options
+					o, err := simulation.NewOptions(simulationGlobalConfig, v.Simulation)
+					if err != nil {
+						return fmt.Errorf("creating simulation %s options: %w", v.Name, err)
+					}
+
+					sim := beekeeper.NewActionMiddleware(tracer, action, v.Name)
+
+					// run simulation
+					finish := c.statusTracker.Start(status.KindSimulation, v.Name, simulationConfig.Type)
+					start := time.Now()
+					err = c.runSimulation(ctx, sim, v.Name, cluster, o)
+
+					result := report.Result{
+						Name:      v.Name,
+						Type:      simulationConfig.Type,
+						Status:    report.StatusPassed,
+						Duration:  time.Since(start),
+						Error:     err,
+						Options:   o,
+						Seed:      report.SeedFromOptions(o),
+						Timestamp: time.Now(),
+					}
+					if err != nil {
+						result.Status = report.StatusFailed
+					}
+					finish(result)
+					simulationsReport.Results = append(simulationsReport.Results, result)
+
+					if err != nil {
+						return fmt.Errorf("running simulation %s: %w", v.Name, err)
+					}
 				}
 			}
 
@@ -143,8 +189,37 @@ Use --metrics-enabled to collect performance metrics during simulation.`,
 	cmd.Flags().Bool(optionNameMetricsEnabled, true, "enable metrics")
 	cmd.Flags().Int64(optionNameSeed, -1, "seed, -1 for random")
 	cmd.Flags().Duration(optionNameTimeout, 30*time.Minute, "timeout")
+	addReportFlags(cmd)
 
 	c.root.AddCommand(cmd)
 
 	return nil
 }
+
+// runSimulation runs the simulation action together with its setup and
+// teardown hooks. Teardown is called even if the simulation failed, unless
+// it is disabled with the skip-teardown flag, and is limited by the default
+// teardown timeout.
+func (c *command) runSimulation(ctx context.Context, sim beekeeper.Action, name string, cluster orchestration.Cluster, o any) error {
+	if t, ok := beekeeper.AsTeardownAction(sim); ok {
+		defer func() {
+			if c.globalConfig.GetBool(optionNameSkipTeardown) {
+				c.log.Infof("skipping teardown of simulation %s", name)
+				return
+			}
+			teardownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), beekeeper.DefaultTeardownTimeout)
+			defer cancel()
+			if err := t.Teardown(teardownCtx, cluster, o); err != nil {
+				c.log.Errorf("simulation %s teardown: %v", name, err)
+			}
+		}()
+	}
+
+	if s, ok := beekeeper.AsSetupAction(sim); ok {
+		if err := s.Setup(ctx, cluster, o); err != nil {
+			return fmt.Errorf("setup: %w", err)
+		}
+	}
+
+	return sim.Run(ctx, cluster, o)
+}
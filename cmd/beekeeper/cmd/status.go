@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ethersphere/beekeeper/pkg/status"
+)
+
+// statusServerShutdownTimeout limits the time waiting for pending requests to
+// the status server on exit.
+const statusServerShutdownTimeout = 5 * time.Second
+
+// initStatus creates the tracker of running checks and simulations, and starts
+// the status server if its address is set.
+func (c *command) initStatus() error {
+	results := c.globalConfig.GetInt(optionNameStatusResults)
+	if results <= 0 {
+		return fmt.Errorf("--%s must be positive", optionNameStatusResults)
+	}
+	c.statusTracker = status.NewTracker(results)
+
+	addr := c.globalConfig.GetString(optionNameStatusAddr)
+	if addr == "" {
+		return nil
+	}
+
+	server := status.NewServer(addr, c.statusTracker, c.metricsRegistry, c.log)
+	if err := server.Start(); err != nil {
+		return err
+	}
+	c.statusServer = server
+
+	return nil
+}
+
+// closeStatusServer gracefully shuts down the status server if it is running.
+func (c *command) closeStatusServer() {
+	if c.statusServer == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), statusServerShutdownTimeout)
+	defer cancel()
+
+	if err := c.statusServer.Close(ctx); err != nil {
+		c.log.Errorf("closing status server: %v", err)
+	}
+}
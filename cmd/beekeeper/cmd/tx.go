@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ethersphere/beekeeper/pkg/transactions"
+	"github.com/spf13/cobra"
+)
+
+const optionNameOlderThan = "older-than"
+
+func (c *command) initTxCmd() (err error) {
+	cmd := &cobra.Command{
+		Use:   "tx",
+		Short: "manage pending transactions of nodes",
+		Long: `Manages pending blockchain transactions of Bee nodes in your cluster or namespace.
+
+Transactions sent by nodes to create postage batches, stake or cash out
+cheques can get stuck, for example when the gas price rises after they were
+sent. The tx command provides subcommands:
+• list: List pending transactions of all nodes
+• resend: Send pending transactions older than --older-than again
+• cancel: Cancel pending transactions older than --older-than
+
+A cancelled transaction is replaced by a transaction with the same nonce and a
+higher gas price that does nothing.
+
+Use --cluster-name or --namespace to target specific nodes.
+Use --label-selector to filter nodes within a namespace.
+Use --node-groups to target specific node groups within a cluster.`,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(initTxDefaultFlags(c.initTxList()))
+	cmd.AddCommand(initTxDefaultFlags(c.initTxResend()))
+	cmd.AddCommand(initTxDefaultFlags(c.initTxCancel()))
+
+	c.root.AddCommand(cmd)
+
+	return nil
+}
+
+func initTxDefaultFlags(cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().StringP(optionNameNamespace, "n", "", "Kubernetes namespace (overrides cluster name).")
+	cmd.Flags().String(optionNameClusterName, "", "Target Beekeeper cluster name.")
+	cmd.Flags().String(optionNameLabelSelector, beeLabelSelector, "Kubernetes label selector for filtering resources (use empty string for all). Only used with --namespace.")
+	cmd.Flags().StringSlice(optionNameNodeGroups, nil, "List of node groups to target (applies to all groups if not set). Only used with --cluster-name.")
+	cmd.Flags().Duration(optionNameTimeout, 5*time.Minute, "Operation timeout (e.g., 5s, 10m, 1.5h).")
+	return cmd
+}
+
+func (c *command) initTxList() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List pending transactions",
+		Long: `Lists pending transactions of all selected nodes, ordered by node and nonce.
+
+Use --older-than to list only transactions pending for longer than the duration.`,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			return c.withTimeoutHandler(cmd, func(ctx context.Context) error {
+				txClient, err := c.createTxClient(ctx)
+				if err != nil {
+					return fmt.Errorf("failed to create transactions client: %w", err)
+				}
+
+				// transactions of reachable nodes are written even if some
+				// nodes can not be listed
+				txs, listErr := txClient.Pending(ctx, c.globalConfig.GetDuration(optionNameOlderThan))
+				if err := writeTransactions(cmd.OutOrStdout(), txs); err != nil {
+					return err
+				}
+				if listErr != nil {
+					return fmt.Errorf("listing pending transactions: %w", listErr)
+				}
+				return nil
+			})
+		},
+		PreRunE: c.preRunE,
+	}
+
+	cmd.Flags().Duration(optionNameOlderThan, 0, "List only transactions pending for longer than the duration.")
+
+	return cmd
+}
+
+func (c *command) initTxResend() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "resend",
+		Short: "Resend stuck transactions",
+		Long: `Sends pending transactions older than --older-than to the blockchain again.
+
+Resending helps when a transaction was dropped from the mempool of the
+blockchain node. Transactions keep their hash and gas price.`,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			return c.withTimeoutHandler(cmd, func(ctx context.Context) error {
+				txClient, err := c.createTxClient(ctx)
+				if err != nil {
+					return fmt.Errorf("failed to create transactions client: %w", err)
+				}
+
+				count, err := txClient.Resend(ctx, c.globalConfig.GetDuration(optionNameOlderThan))
+				c.log.Infof("resent %d transactions", count)
+				return err
+			})
+		},
+		PreRunE: c.preRunE,
+	}
+
+	cmd.Flags().Duration(optionNameOlderThan, 10*time.Minute, "Resend transactions pending for longer than the duration.")
+
+	return cmd
+}
+
+func (c *command) initTxCancel() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cancel",
+		Short: "Cancel stuck transactions",
+		Long: `Cancels pending transactions older than --older-than.
+
+Each transaction is replaced by a transaction with the same nonce and a
+higher gas price that does nothing, so that later transactions of the node
+are no longer blocked. The operation that sent the cancelled transaction,
+for example a postage batch creation, does not take place.`,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			return c.withTimeoutHandler(cmd, func(ctx context.Context) error {
+				txClient, err := c.createTxClient(ctx)
+				if err != nil {
+					return fmt.Errorf("failed to create transactions client: %w", err)
+				}
+
+				count, err := txClient.Cancel(ctx, c.globalConfig.GetDuration(optionNameOlderThan))
+				c.log.Infof("cancelled %d transactions", count)
+				return err
+			})
+		},
+		PreRunE: c.preRunE,
+	}
+
+	cmd.Flags().Duration(optionNameOlderThan, 30*time.Minute, "Cancel transactions pending for longer than the duration.")
+
+	return cmd
+}
+
+func (c *command) createTxClient(ctx context.Context) (*transactions.Client, error) {
+	nodeClient, err := c.createNodeClient(ctx, false)
+	if err != nil {
+		return nil, fmt.Errorf("creating node client: %w", err)
+	}
+
+	return transactions.New(&transactions.ClientConfig{
+		Log:        c.log,
+		NodeClient: nodeClient,
+	}), nil
+}
+
+func writeTransactions(w io.Writer, txs []transactions.Transaction) error {
+	now := time.Now()
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NODE\tHASH\tNONCE\tAGE\tGAS PRICE\tTO\tDESCRIPTION")
+	for _, tx := range txs {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%s\t%s\t%s\n",
+			tx.Node,
+			tx.TransactionHash,
+			tx.Nonce,
+			tx.Age(now).Round(time.Second),
+			tx.GasPrice,
+			tx.To,
+			tx.Description,
+		)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ethersphere/beekeeper/pkg/restart"
+	"github.com/spf13/cobra"
+)
+
+func (c *command) initUpgradeCmd() (err error) {
+	const (
+		optionNameLabelSelector   = "label-selector"
+		optionNameNamespace       = "namespace"
+		optionNameImage           = "image"
+		optionNameNodeGroups      = "node-groups"
+		optionNameTimeout         = "timeout"
+		optionNameDeploymentType  = "deployment-type"
+		optionNameByNodeGroup     = "by-node-group"
+		optionNameBatchPercentage = "batch-percentage"
+		optionNameGroupLabel      = "group-label"
+		optionNameMinPeers        = "min-peers"
+		optionNameVersion         = "version"
+		optionNameGateTimeout     = "gate-timeout"
+		optionNameRollback        = "rollback"
+	)
+
+	cmd := &cobra.Command{
+		Use:   "upgrade",
+		Short: "upgrades Bee nodes to a new image in health-gated batches",
+		Long: `Upgrades Bee nodes in a Kubernetes cluster or namespace to a new image in batches.
+
+Nodes are upgraded one node group at a time with --by-node-group, or in batches
+of --batch-percentage of the nodes. After each batch, the upgrade waits until
+the upgraded nodes:
+• run the new image and report ready
+• are connected to at least --min-peers peers
+• report the expected version, --version or the version from the image tag
+
+If a batch does not pass the gates within --gate-timeout, the upgrade stops,
+and with --rollback the previous images of all upgraded nodes are restored.
+
+Requires either --cluster-name or --namespace to be specified.`,
+		Example: `beekeeper upgrade --cluster-name=default --image=ethersphere/bee:2.6.0 --by-node-group --rollback
+beekeeper upgrade --namespace=bee-testnet --image=ethersphere/bee:2.6.0 --batch-percentage=20 --min-peers=5`,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			return c.withTimeoutHandler(cmd, func(ctx context.Context) error {
+				if !c.globalConfig.GetBool(optionNameEnableK8S) {
+					return fmt.Errorf("kubernetes support must be enabled for upgrade command")
+				}
+
+				nodeClient, err := c.createNodeClient(ctx, true)
+				if err != nil {
+					return fmt.Errorf("creating node client: %w", err)
+				}
+
+				restartClient := restart.NewClient(nodeClient, c.k8sClient, c.log)
+
+				if err := restartClient.Upgrade(ctx, restart.UpgradeOptions{
+					Image:           c.globalConfig.GetString(optionNameImage),
+					ByNodeGroup:     c.globalConfig.GetBool(optionNameByNodeGroup),
+					BatchPercentage: c.globalConfig.GetInt(optionNameBatchPercentage),
+					GroupLabel:      c.globalConfig.GetString(optionNameGroupLabel),
+					MinPeers:        c.globalConfig.GetUint64(optionNameMinPeers),
+					Version:         c.globalConfig.GetString(optionNameVersion),
+					GateTimeout:     c.globalConfig.GetDuration(optionNameGateTimeout),
+					Rollback:        c.globalConfig.GetBool(optionNameRollback),
+				}); err != nil {
+					return fmt.Errorf("upgrading nodes: %w", err)
+				}
+
+				return nil
+			})
+		},
+		PreRunE: c.preRunE,
+	}
+
+	cmd.Flags().String(optionNameClusterName, "", "Kubernetes cluster to operate on (overrides namespace and label selector).")
+	cmd.Flags().StringP(optionNameNamespace, "n", "", "Namespace of the nodes to upgrade (only used if cluster name is not set).")
+	cmd.Flags().String(optionNameLabelSelector, beeLabelSelector, "Label selector for resources in the namespace (only used with namespace).")
+	cmd.Flags().String(optionNameImage, "", "Container image to upgrade the nodes to. Required.")
+	cmd.Flags().StringSlice(optionNameNodeGroups, nil, "List of node groups to upgrade (applies to all groups if not set). Only used with --cluster-name.")
+	cmd.Flags().Duration(optionNameTimeout, 60*time.Minute, "Operation timeout (e.g., 5s, 10m, 1.5h).")
+	cmd.Flags().String(optionNameDeploymentType, "beekeeper", "Indicates how the cluster was deployed: 'beekeeper' or 'helm'.")
+	cmd.Flags().Bool(optionNameByNodeGroup, false, "Upgrade one node group per batch.")
+	cmd.Flags().Int(optionNameBatchPercentage, 25, "Percentage of nodes upgraded per batch (only used without --by-node-group).")
+	cmd.Flags().String(optionNameGroupLabel, restart.DefaultGroupLabel, "Pod label with the node group of nodes in the namespace (only used with namespace and --by-node-group).")
+	cmd.Flags().Uint64(optionNameMinPeers, 1, "Minimum number of connected peers of upgraded nodes.")
+	cmd.Flags().String(optionNameVersion, "", "Version reported by upgraded nodes (defaults to the version from the image tag).")
+	cmd.Flags().Duration(optionNameGateTimeout, 10*time.Minute, "Time upgraded nodes of a batch have to pass the gates.")
+	cmd.Flags().Bool(optionNameRollback, false, "Restore the previous image of upgraded nodes when a gate fails.")
+
+	c.root.AddCommand(cmd)
+
+	return nil
+}
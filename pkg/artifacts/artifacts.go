@@ -0,0 +1,258 @@
+// Package artifacts collects diagnostics of a Bee cluster into a tar.gz
+// bundle, to investigate failed checks after the cluster state is gone.
+package artifacts
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethersphere/beekeeper/pkg/bee"
+	"github.com/ethersphere/beekeeper/pkg/k8s"
+	"github.com/ethersphere/beekeeper/pkg/logging"
+	"github.com/ethersphere/beekeeper/pkg/orchestration"
+	"golang.org/x/sync/errgroup"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	// DefaultLogLines is the default number of log lines collected per container
+	DefaultLogLines = 1000
+
+	// nodesConcurrency limits the number of nodes queried at the same time
+	nodesConcurrency = 10
+)
+
+// Collector collects diagnostics bundles of the cluster
+type Collector struct {
+	dir       string
+	cluster   orchestration.Cluster
+	k8sClient *k8s.Client // optional, pod logs are collected if set
+	logLines  int64
+	logger    logging.Logger
+}
+
+// NewCollector creates a Collector that writes bundles to dir
+func NewCollector(dir string, cluster orchestration.Cluster, k8sClient *k8s.Client, logLines int64, logger logging.Logger) *Collector {
+	return &Collector{
+		dir:       dir,
+		cluster:   cluster,
+		k8sClient: k8sClient,
+		logLines:  logLines,
+		logger:    logger,
+	}
+}
+
+// Collect gathers the state of every node and logs of pods of the nodes and
+// writes them to a bundle named after the check. Errors of individual
+// requests are written to the bundle instead of failing the collection. It
+// returns the path of the bundle.
+func (c *Collector) Collect(ctx context.Context, name string, cause error) (string, error) {
+	b := newBundle()
+
+	summary := map[string]any{
+		"check":     name,
+		"cluster":   c.cluster.Name(),
+		"namespace": c.cluster.Namespace(),
+		"timestamp": time.Now().UTC(),
+	}
+	if cause != nil {
+		summary["error"] = cause.Error()
+	}
+	b.addJSON("summary.json", summary)
+
+	c.collectNodes(ctx, b)
+	c.collectLogs(ctx, b)
+
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return "", fmt.Errorf("creating artifacts directory: %w", err)
+	}
+
+	path := filepath.Join(c.dir, fmt.Sprintf("%s-%s.tar.gz", sanitize(name), time.Now().UTC().Format("20060102T150405.000Z")))
+	if err := b.write(path); err != nil {
+		return "", fmt.Errorf("writing artifacts bundle: %w", err)
+	}
+
+	return path, nil
+}
+
+func (c *Collector) collectNodes(ctx context.Context, b *bundle) {
+	clients, err := c.cluster.NodesClients(ctx)
+	if err != nil {
+		b.addError("nodes", err)
+		return
+	}
+
+	var g errgroup.Group
+	g.SetLimit(nodesConcurrency)
+
+	for node, client := range clients {
+		g.Go(func() error {
+			collectNode(ctx, b, "nodes/"+node+"/", client)
+			return nil
+		})
+	}
+
+	_ = g.Wait()
+}
+
+func collectNode(ctx context.Context, b *bundle, prefix string, client *bee.Client) {
+	for _, e := range []struct {
+		file string
+		get  func() (any, error)
+	}{
+		{"addresses.json", func() (any, error) { return client.Addresses(ctx) }},
+		{"chain_state.json", func() (any, error) { return client.ChainState(ctx) }},
+		{"peers.json", func() (any, error) { return client.Peers(ctx) }},
+		{"postage_batches.json", func() (any, error) { return client.PostageBatches(ctx) }},
+		{"reserve_state.json", func() (any, error) { return client.ReserveState(ctx) }},
+		{"status.json", func() (any, error) { return client.Status(ctx) }},
+		{"topology.json", func() (any, error) { return client.Topology(ctx) }},
+	} {
+		v, err := e.get()
+		if err != nil {
+			b.addError(prefix+e.file, err)
+			continue
+		}
+		b.addJSON(prefix+e.file, v)
+	}
+}
+
+func (c *Collector) collectLogs(ctx context.Context, b *bundle) {
+	if c.k8sClient == nil || c.k8sClient.Pods == nil {
+		return
+	}
+
+	if c.k8sClient.Service == nil {
+		return
+	}
+
+	// only pods of the cluster's nodes are collected, not other pods in the
+	// namespace, like the blockchain node or nodes of other clusters
+	namespace := c.cluster.Namespace()
+	nodes := c.cluster.NodeNames()
+	sort.Strings(nodes)
+
+	var pods []v1.Pod
+	for _, node := range nodes {
+		nodePods, err := c.k8sClient.Service.FindPods(ctx, namespace, node)
+		if err != nil {
+			b.addError("logs/"+node, err)
+			continue
+		}
+		pods = append(pods, nodePods...)
+	}
+
+	for _, pod := range pods {
+		for _, container := range pod.Spec.Containers {
+			file := "logs/" + pod.Name + "/" + container.Name + ".log"
+			logs, err := c.k8sClient.Pods.Logs(ctx, pod.Name, namespace, container.Name, c.logLines)
+			if err != nil {
+				b.addError(file, err)
+				continue
+			}
+			b.add(file, logs)
+		}
+	}
+}
+
+// bundle holds files of the archive in memory. It is safe for concurrent use.
+type bundle struct {
+	mu     sync.Mutex
+	files  map[string][]byte
+	errors []string
+}
+
+func newBundle() *bundle {
+	return &bundle{files: make(map[string][]byte)}
+}
+
+func (b *bundle) add(name string, data []byte) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.files[name] = data
+}
+
+func (b *bundle) addJSON(name string, v any) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		b.addError(name, fmt.Errorf("marshal: %w", err))
+		return
+	}
+	b.add(name, data)
+}
+
+func (b *bundle) addError(name string, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.errors = append(b.errors, name+": "+err.Error())
+}
+
+// write writes files, and errors encountered while collecting them, to a
+// gzip compressed tar archive at path.
+func (b *bundle) write(path string) (err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := f.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}()
+
+	gw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gw)
+
+	files := b.files
+	if len(b.errors) > 0 {
+		sort.Strings(b.errors)
+		files["errors.txt"] = []byte(strings.Join(b.errors, "\n") + "\n")
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	now := time.Now()
+	for _, name := range names {
+		data := files[name]
+		if err := tw.WriteHeader(&tar.Header{
+			Name:    name,
+			Mode:    0o644,
+			Size:    int64(len(data)),
+			ModTime: now,
+		}); err != nil {
+			return fmt.Errorf("write header of %s: %w", name, err)
+		}
+		if _, err := tw.Write(data); err != nil {
+			return fmt.Errorf("write %s: %w", name, err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+var unsafeChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// sanitize makes the name safe to use in a file name
+func sanitize(name string) string {
+	return unsafeChars.ReplaceAllString(name, "_")
+}
@@ -0,0 +1,144 @@
+package artifacts
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/ethersphere/beekeeper/pkg/k8s"
+	"github.com/ethersphere/beekeeper/pkg/k8s/pod"
+	"github.com/ethersphere/beekeeper/pkg/k8s/service"
+	"github.com/ethersphere/beekeeper/pkg/logging"
+	"github.com/ethersphere/beekeeper/pkg/orchestration"
+	"github.com/ethersphere/beekeeper/pkg/orchestration/fake"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestBundleWrite(t *testing.T) {
+	b := newBundle()
+	b.add("logs/bee-0/bee.log", []byte("line\n"))
+	b.addJSON("nodes/bee-0/status.json", map[string]int{"peers": 2})
+	b.addError("nodes/bee-1/status.json", errors.New("connection refused"))
+
+	path := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	if err := b.write(path); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	got := readBundle(t, path)
+	want := map[string]string{
+		"errors.txt":              "nodes/bee-1/status.json: connection refused\n",
+		"logs/bee-0/bee.log":      "line\n",
+		"nodes/bee-0/status.json": "{\n  \"peers\": 2\n}",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestSanitize(t *testing.T) {
+	for in, want := range map[string]string{
+		"pingpong":         "pingpong",
+		"smoke/upload 1KB": "smoke_upload_1KB",
+		"a..b":             "a..b",
+	} {
+		if got := sanitize(in); got != want {
+			t.Errorf("sanitize(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func readBundle(t *testing.T, path string) map[string]string {
+	t.Helper()
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := make(map[string]string)
+	tr := tar.NewReader(gr)
+	for {
+		h, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[h.Name] = string(data)
+	}
+	return files
+}
+
+func TestCollectLogsOfClusterPods(t *testing.T) {
+	ctx := context.Background()
+
+	cluster := fake.NewCluster("artifacts", orchestration.ClusterOptions{Namespace: "test"}, nil)
+	defer cluster.Close()
+	if err := cluster.AddNodes(ctx, "bee", 2, orchestration.Config{}); err != nil {
+		t.Fatal(err)
+	}
+
+	objects := []runtime.Object{
+		// the blockchain node in the same namespace is not a node of the cluster
+		&v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "geth-0", Namespace: "test", Labels: map[string]string{"app": "geth"}},
+			Spec:       v1.PodSpec{Containers: []v1.Container{{Name: "geth"}}},
+		},
+	}
+	for _, name := range cluster.NodeNames() {
+		labels := map[string]string{"app.kubernetes.io/instance": name}
+		objects = append(objects,
+			&v1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test"},
+				Spec:       v1.ServiceSpec{Selector: labels},
+			},
+			&v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: name + "-0", Namespace: "test", Labels: labels},
+				Spec:       v1.PodSpec{Containers: []v1.Container{{Name: name}}},
+			},
+		)
+	}
+	clientset := k8sfake.NewClientset(objects...)
+
+	c := NewCollector(t.TempDir(), cluster, &k8s.Client{
+		Pods:    pod.NewClient(clientset, logging.New(io.Discard, 0)),
+		Service: service.NewClient(clientset),
+	}, DefaultLogLines, logging.New(io.Discard, 0))
+
+	b := newBundle()
+	c.collectLogs(ctx, b)
+
+	var logs []string
+	for name := range b.files {
+		logs = append(logs, name)
+	}
+	sort.Strings(logs)
+	if want := []string{"logs/bee-0-0/bee-0.log", "logs/bee-1-0/bee-1.log"}; !reflect.DeepEqual(logs, want) {
+		t.Fatalf("got logs %v, want %v", logs, want)
+	}
+	if len(b.errors) != 0 {
+		t.Fatalf("got errors %v", b.errors)
+	}
+}
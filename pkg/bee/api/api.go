@@ -11,6 +11,8 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+
+	"github.com/ethersphere/beekeeper/pkg/httpx"
 )
 
 const (
@@ -41,27 +43,58 @@ type Client struct {
 	apiURL     *url.URL     // Base URL for API requests.
 	service    service      // Reuse a single struct instead of allocating one for each service on the heap.
 
+	retryPolicy RetryPolicy             // policy of retrying failed requests, requests are sent once by default
+	retried     func(req *http.Request) // records a retry of the request, if metrics are set
+
 	// Services that API provides.
-	Act         *ActService
-	Bytes       *BytesService
-	Chunks      *ChunksService
-	Dirs        *DirsService
-	Feed        *FeedService
-	Files       *FilesService
-	Node        *NodeService
-	PingPong    *PingPongService
-	Pinning     *PinningService
-	Postage     *PostageService
-	PSS         *PSSService
-	SOC         *SOCService
-	Stake       *StakingService
-	Status      *StatusService
-	Stewardship *StewardshipService
-	Tags        *TagsService
+	Act            *ActService
+	Bytes          *BytesService
+	Chunks         *ChunksService
+	Dirs           *DirsService
+	Feed           *FeedService
+	Files          *FilesService
+	Node           *NodeService
+	PingPong       *PingPongService
+	Pinning        *PinningService
+	Postage        *PostageService
+	PSS            *PSSService
+	Redistribution *RedistributionService
+	SOC            *SOCService
+	Stake          *StakingService
+	Status         *StatusService
+	Stewardship    *StewardshipService
+	Tags           *TagsService
+	Transactions   *TransactionsService
+}
+
+// ClientOption holds optional parameters for the Client.
+type ClientOption func(*Client)
+
+// WithMetrics records metrics of requests of the client and of their retries,
+// labeled with the name and node group of the node
+func WithMetrics(m *httpx.Metrics, node, nodeGroup string) ClientOption {
+	return func(c *Client) {
+		next := c.httpClient.Transport
+		if next == nil {
+			next = http.DefaultTransport
+		}
+
+		httpClient := *c.httpClient
+		httpClient.Transport = &httpx.MetricsRoundTripper{
+			Next:      next,
+			Metrics:   m,
+			Node:      node,
+			NodeGroup: nodeGroup,
+		}
+		c.httpClient = &httpClient
+		c.retried = func(req *http.Request) {
+			m.Retried(req, node, nodeGroup)
+		}
+	}
 }
 
 // NewClient constructs a new Client.
-func NewClient(apiURL *url.URL, httpClient *http.Client) (*Client, error) {
+func NewClient(apiURL *url.URL, httpClient *http.Client, opts ...ClientOption) (*Client, error) {
 	if httpClient == nil {
 		httpClient = &http.Client{}
 	}
@@ -69,7 +102,12 @@ func NewClient(apiURL *url.URL, httpClient *http.Client) (*Client, error) {
 		return nil, errors.New("baseURL is required")
 	}
 
-	return newClient(apiURL, httpClient), nil
+	c := newClient(apiURL, httpClient)
+	for _, option := range opts {
+		option(c)
+	}
+
+	return c, nil
 }
 
 func (c *Client) Host() string {
@@ -103,11 +141,13 @@ func newClient(apiURL *url.URL, httpClient *http.Client) (c *Client) {
 	c.Pinning = (*PinningService)(&c.service)
 	c.Postage = (*PostageService)(&c.service)
 	c.PSS = (*PSSService)(&c.service)
+	c.Redistribution = (*RedistributionService)(&c.service)
 	c.SOC = (*SOCService)(&c.service)
 	c.Stake = (*StakingService)(&c.service)
 	c.Status = (*StatusService)(&c.service)
 	c.Stewardship = (*StewardshipService)(&c.service)
 	c.Tags = (*TagsService)(&c.service)
+	c.Transactions = (*TransactionsService)(&c.service)
 
 	return c
 }
@@ -153,7 +193,7 @@ func (c *Client) request(ctx context.Context, method, path string, body io.Reade
 	}
 	req.Header.Set("Accept", contentType)
 
-	r, err := c.httpClient.Do(req)
+	r, err := c.do(req)
 	if err != nil {
 		return err
 	}
@@ -230,7 +270,7 @@ func (c *Client) requestDataGetHeader(ctx context.Context, method, path string,
 	if opts != nil && opts.RedundancyFallbackMode != nil {
 		req.Header.Set(swarmRedundancyFallbackMode, strconv.FormatBool(*opts.RedundancyFallbackMode))
 	}
-	r, err := c.httpClient.Do(req)
+	r, err := c.do(req)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -257,7 +297,7 @@ func (c *Client) requestWithHeader(ctx context.Context, method, path string, hea
 	req.Header = header
 	req.Header.Add("Accept", contentType)
 
-	r, err := c.httpClient.Do(req)
+	r, err := c.do(req)
 	if err != nil {
 		return err
 	}
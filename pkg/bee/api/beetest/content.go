@@ -0,0 +1,743 @@
+package beetest
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethersphere/bee/v2/pkg/cac"
+	"github.com/ethersphere/bee/v2/pkg/crypto"
+	"github.com/ethersphere/bee/v2/pkg/file/pipeline/builder"
+	"github.com/ethersphere/bee/v2/pkg/file/redundancy"
+	"github.com/ethersphere/bee/v2/pkg/soc"
+	"github.com/ethersphere/bee/v2/pkg/storage"
+	"github.com/ethersphere/bee/v2/pkg/swarm"
+	"github.com/ethersphere/beekeeper/pkg/bee/api"
+)
+
+// upload is data uploaded by the bytes or bzz endpoints. Collections are
+// indexed by the paths of their files.
+type upload struct {
+	data          []byte
+	chunks        []swarm.Address
+	collection    map[string][]byte
+	indexDocument string
+	errorDocument string
+}
+
+// feed is a feed created by the feeds endpoint
+type feed struct {
+	owner []byte
+	topic []byte
+}
+
+// socUpdate is a single owner chunk uploaded by the soc endpoint
+type socUpdate struct {
+	signature []byte
+	wrapped   swarm.Chunk
+}
+
+// HasChunk returns whether the chunk is stored on the node
+func (s *Server) HasChunk(addr swarm.Address) bool {
+	_, ok := s.Chunk(addr)
+	return ok
+}
+
+// Chunk returns data of the chunk stored on the node
+func (s *Server) Chunk(addr swarm.Address) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, ok := s.chunks[addr.ByteString()]
+	return data, ok
+}
+
+// Put stores the chunk on the node, evicting the oldest unpinned chunks if
+// the node stores more of them than its capacity
+func (s *Server) Put(ch swarm.Chunk) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := ch.Address().ByteString()
+	if _, ok := s.chunks[key]; !ok {
+		s.order = append(s.order, key)
+	}
+	s.chunks[key] = ch.Data()
+	s.evict()
+}
+
+// Pinned returns whether the reference is pinned
+func (s *Server) Pinned(ref swarm.Address) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.pins[ref.ByteString()]
+	return ok
+}
+
+// push pushes the uploaded chunk to the network of the node, or stores it on
+// the node if it is not connected to a network
+func (s *Server) push(ch swarm.Chunk) {
+	if s.network != nil {
+		s.network.Push(ch)
+		return
+	}
+	s.Put(ch)
+}
+
+// evict removes the oldest unpinned chunks until the node stores at most its
+// capacity of them. It must be called with the lock held.
+func (s *Server) evict() {
+	if s.capacity == 0 {
+		return
+	}
+
+	unpinned := uint64(0)
+	for k := range s.chunks {
+		if s.pinned[k] == 0 {
+			unpinned++
+		}
+	}
+
+	kept := s.order[:0]
+	for _, k := range s.order {
+		if unpinned > s.capacity && s.pinned[k] == 0 {
+			delete(s.chunks, k)
+			unpinned--
+			continue
+		}
+		kept = append(kept, k)
+	}
+	s.order = kept
+}
+
+// pin protects chunks of the uploaded content, or the stored chunk, from
+// eviction. It returns false if the reference is not known to the node. It
+// must be called with the lock held.
+func (s *Server) pin(ref swarm.Address) bool {
+	key := ref.ByteString()
+	if _, ok := s.pins[key]; ok {
+		return true
+	}
+
+	var addrs []swarm.Address
+	if u, ok := s.uploads[key]; ok {
+		addrs = u.chunks
+	} else if _, ok := s.chunks[key]; ok {
+		addrs = []swarm.Address{ref}
+	} else if _, ok := s.feeds[key]; !ok {
+		return false
+	}
+
+	for _, addr := range addrs {
+		s.pinned[addr.ByteString()]++
+	}
+	s.pins[key] = addrs
+	return true
+}
+
+// unpin makes chunks of the pinned content evictable again. It returns false
+// if the reference is not pinned. It must be called with the lock held.
+func (s *Server) unpin(ref swarm.Address) bool {
+	key := ref.ByteString()
+	addrs, ok := s.pins[key]
+	if !ok {
+		return false
+	}
+	for _, addr := range addrs {
+		if s.pinned[addr.ByteString()]--; s.pinned[addr.ByteString()] <= 0 {
+			delete(s.pinned, addr.ByteString())
+		}
+	}
+	delete(s.pins, key)
+	return true
+}
+
+// known returns whether the reference is of uploaded content or a stored
+// chunk
+func (s *Server) known(ref swarm.Address) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.uploads[ref.ByteString()]
+	if !ok {
+		_, ok = s.chunks[ref.ByteString()]
+	}
+	return ok
+}
+
+// uploaded pins the reference and updates the tag of an upload request, as
+// requested by its headers
+func (s *Server) uploaded(r *http.Request, ref swarm.Address, chunks uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if strings.EqualFold(r.Header.Get("Swarm-Pin"), "true") {
+		s.pin(ref)
+	}
+
+	uid, err := strconv.ParseUint(r.Header.Get("Swarm-Tag"), 10, 64)
+	if err != nil {
+		return
+	}
+	if t, ok := s.tags[uid]; ok {
+		t.Split += chunks
+		t.Stored += chunks
+		t.Sent += chunks
+		t.Synced += chunks
+		t.Address = ref
+	}
+}
+
+// validBatch reports whether the request has a usable postage batch of the
+// node, writing an error response if it does not
+func (s *Server) validBatch(w http.ResponseWriter, r *http.Request) bool {
+	id := r.Header.Get("Swarm-Postage-Batch-Id")
+	if id == "" {
+		jsonError(w, http.StatusBadRequest, "missing postage batch id")
+		return false
+	}
+
+	b, ok := s.Batch(id)
+	if !ok {
+		jsonError(w, http.StatusNotFound, "batch with id not found")
+		return false
+	}
+	if !b.Usable {
+		jsonError(w, http.StatusUnprocessableEntity, "batch not usable yet or does not exist")
+		return false
+	}
+	return true
+}
+
+func (s *Server) hasChunk(w http.ResponseWriter, r *http.Request) {
+	addr, ok := pathAddress(w, r)
+	if !ok {
+		return
+	}
+
+	if !s.HasChunk(addr) {
+		jsonError(w, http.StatusNotFound, "chunk not found")
+		return
+	}
+	jsonError(w, http.StatusOK, http.StatusText(http.StatusOK))
+}
+
+func (s *Server) uploadChunk(w http.ResponseWriter, r *http.Request) {
+	if !s.validBatch(w, r) {
+		return
+	}
+
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		jsonError(w, http.StatusBadRequest, "read body")
+		return
+	}
+
+	ch, err := cac.NewWithDataSpan(data)
+	if err != nil {
+		jsonError(w, http.StatusBadRequest, "invalid chunk data")
+		return
+	}
+
+	s.push(ch)
+	s.uploaded(r, ch.Address(), 1)
+
+	jsonResponse(w, http.StatusCreated, api.ChunksUploadResponse{Reference: ch.Address()})
+}
+
+func (s *Server) downloadChunk(w http.ResponseWriter, r *http.Request) {
+	addr, ok := pathAddress(w, r)
+	if !ok {
+		return
+	}
+
+	data, ok := s.Chunk(addr)
+	if !ok && s.network != nil {
+		data, ok = s.network.Retrieve(addr)
+	}
+	if !ok {
+		jsonError(w, http.StatusNotFound, "chunk not found")
+		return
+	}
+
+	w.Header().Set("Content-Type", "binary/octet-stream")
+	_, _ = w.Write(data)
+}
+
+func (s *Server) uploadBytes(w http.ResponseWriter, r *http.Request) {
+	if !s.validBatch(w, r) {
+		return
+	}
+
+	ref, ok := s.store(w, r, upload{})
+	if !ok {
+		return
+	}
+	jsonResponse(w, http.StatusCreated, api.BytesUploadResponse{Reference: ref})
+}
+
+func (s *Server) downloadBytes(w http.ResponseWriter, r *http.Request) {
+	addr, ok := pathAddress(w, r)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	u, ok := s.uploads[addr.ByteString()]
+	s.mu.Unlock()
+
+	if !ok {
+		jsonError(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	_, _ = w.Write(u.data)
+}
+
+// uploadBzz stores files and collections. Unlike Bee, it does not create
+// manifests, so the reference of a file is the same as if it was uploaded by
+// the bytes endpoint.
+func (s *Server) uploadBzz(w http.ResponseWriter, r *http.Request) {
+	if !s.validBatch(w, r) {
+		return
+	}
+
+	var u upload
+	if strings.EqualFold(r.Header.Get("Swarm-Collection"), "true") {
+		u.indexDocument = r.Header.Get("Swarm-Index-Document")
+		u.errorDocument = r.Header.Get("Swarm-Error-Document")
+		u.collection = make(map[string][]byte)
+	}
+
+	ref, ok := s.store(w, r, u)
+	if !ok {
+		return
+	}
+	jsonResponse(w, http.StatusCreated, api.FilesUploadResponse{Reference: ref})
+}
+
+func (s *Server) downloadBzz(w http.ResponseWriter, r *http.Request) {
+	addr, ok := pathAddress(w, r)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	u, isUpload := s.uploads[addr.ByteString()]
+	f, isFeed := s.feeds[addr.ByteString()]
+	s.mu.Unlock()
+
+	if isFeed {
+		update, _, ok := s.latestUpdate(f)
+		if !ok {
+			jsonError(w, http.StatusNotFound, "feed update not found")
+			return
+		}
+		w.Header().Set("Content-Type", "application/octet-stream")
+		_, _ = w.Write(s.content(update.wrapped))
+		return
+	}
+
+	if !isUpload {
+		jsonError(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	data := u.data
+	if u.collection != nil {
+		path := r.PathValue("path")
+		if path == "" {
+			path = u.indexDocument
+		}
+		var ok bool
+		if data, ok = u.collection[path]; !ok {
+			if data, ok = u.collection[u.errorDocument]; !ok {
+				jsonError(w, http.StatusNotFound, "path address not found")
+				return
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	_, _ = w.Write(data)
+}
+
+// store splits the request body into chunks and stores them together with
+// the upload, writing an error response if it fails
+func (s *Server) store(w http.ResponseWriter, r *http.Request, u upload) (swarm.Address, bool) {
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		jsonError(w, http.StatusBadRequest, "read body")
+		return swarm.ZeroAddress, false
+	}
+
+	if u.collection != nil {
+		tr := tar.NewReader(bytes.NewReader(data))
+		for {
+			hdr, err := tr.Next()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				jsonError(w, http.StatusBadRequest, "invalid tar archive")
+				return swarm.ZeroAddress, false
+			}
+			if hdr.Typeflag != tar.TypeReg {
+				continue
+			}
+			file, err := io.ReadAll(tr)
+			if err != nil {
+				jsonError(w, http.StatusBadRequest, "invalid tar archive")
+				return swarm.ZeroAddress, false
+			}
+			u.collection[strings.TrimPrefix(hdr.Name, "./")] = file
+		}
+	}
+
+	var rLevel redundancy.Level
+	if l, err := strconv.Atoi(r.Header.Get("Swarm-Redundancy-Level")); err == nil {
+		rLevel = redundancy.Level(l)
+	}
+
+	var chunks []swarm.Chunk
+	putter := storage.PutterFunc(func(_ context.Context, ch swarm.Chunk) error {
+		chunks = append(chunks, ch)
+		return nil
+	})
+
+	ref, err := builder.FeedPipeline(r.Context(), builder.NewPipelineBuilder(r.Context(), putter, false, rLevel), bytes.NewReader(data))
+	if err != nil {
+		jsonError(w, http.StatusInternalServerError, "split data")
+		return swarm.ZeroAddress, false
+	}
+	u.data = data
+	for _, ch := range chunks {
+		u.chunks = append(u.chunks, ch.Address())
+	}
+
+	// the upload is pinned before its chunks are stored, so that they are
+	// not evicted by each other
+	s.mu.Lock()
+	s.uploads[ref.ByteString()] = u
+	s.mu.Unlock()
+	s.uploaded(r, ref, uint64(len(chunks)))
+
+	// like Bee, the node keeps chunks of the content it splits, so that they
+	// can be pinned
+	for _, ch := range chunks {
+		s.Put(ch)
+		if s.network != nil {
+			s.network.Push(ch)
+		}
+	}
+
+	return ref, true
+}
+
+func (s *Server) uploadSOC(w http.ResponseWriter, r *http.Request) {
+	if !s.validBatch(w, r) {
+		return
+	}
+
+	owner, err := hex.DecodeString(r.PathValue("owner"))
+	if err != nil {
+		jsonError(w, http.StatusBadRequest, "invalid owner")
+		return
+	}
+	id, err := hex.DecodeString(r.PathValue("id"))
+	if err != nil {
+		jsonError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+	sig, err := hex.DecodeString(r.URL.Query().Get("sig"))
+	if err != nil {
+		jsonError(w, http.StatusBadRequest, "invalid signature")
+		return
+	}
+
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		jsonError(w, http.StatusBadRequest, "read body")
+		return
+	}
+
+	ch, err := cac.NewWithDataSpan(data)
+	if err != nil {
+		jsonError(w, http.StatusBadRequest, "invalid chunk data")
+		return
+	}
+
+	sc, err := soc.NewSigned(id, ch, owner, sig)
+	if err != nil {
+		jsonError(w, http.StatusBadRequest, "invalid soc")
+		return
+	}
+	sch, err := sc.Chunk()
+	if err != nil || !soc.Valid(sch) {
+		jsonError(w, http.StatusUnauthorized, "invalid chunk")
+		return
+	}
+
+	s.push(sch)
+
+	s.mu.Lock()
+	s.socs[socKey(owner, id)] = socUpdate{signature: sig, wrapped: ch}
+	s.mu.Unlock()
+	s.uploaded(r, sch.Address(), 1)
+
+	jsonResponse(w, http.StatusCreated, api.SocResponse{Reference: sch.Address()})
+}
+
+func (s *Server) createFeed(w http.ResponseWriter, r *http.Request) {
+	if !s.validBatch(w, r) {
+		return
+	}
+
+	f, ok := pathFeed(w, r)
+	if !ok {
+		return
+	}
+
+	h, err := crypto.LegacyKeccak256(append(append([]byte{}, f.owner...), f.topic...))
+	if err != nil {
+		jsonError(w, http.StatusInternalServerError, "feed reference")
+		return
+	}
+	ref := swarm.NewAddress(h)
+
+	s.mu.Lock()
+	s.feeds[ref.ByteString()] = f
+	s.mu.Unlock()
+	s.uploaded(r, ref, 1)
+
+	jsonResponse(w, http.StatusCreated, api.FeedUploadResponse{Reference: ref})
+}
+
+func (s *Server) findFeedUpdate(w http.ResponseWriter, r *http.Request) {
+	f, ok := pathFeed(w, r)
+	if !ok {
+		return
+	}
+
+	update, index, ok := s.latestUpdate(f)
+	if !ok {
+		jsonError(w, http.StatusNotFound, "feed update not found")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Swarm-Feed-Index", feedIndex(index))
+	w.Header().Set("Swarm-Feed-Index-Next", feedIndex(index+1))
+	w.Header().Set("Swarm-Soc-Signature", hex.EncodeToString(update.signature))
+
+	if strings.EqualFold(r.Header.Get("Swarm-Only-Root-Chunk"), "true") {
+		_, _ = w.Write(update.wrapped.Data())
+		return
+	}
+	_, _ = w.Write(s.content(update.wrapped))
+}
+
+// latestUpdate returns the update of the feed with the highest index of
+// sequentially indexed updates
+func (s *Server) latestUpdate(f feed) (update socUpdate, index uint64, found bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := uint64(0); ; i++ {
+		b := make([]byte, 8)
+		binary.BigEndian.PutUint64(b, i)
+		id, err := crypto.LegacyKeccak256(append(append([]byte{}, f.topic...), b...))
+		if err != nil {
+			return update, index, found
+		}
+		u, ok := s.socs[socKey(f.owner, id)]
+		if !ok {
+			return update, index, found
+		}
+		update, index, found = u, i, true
+	}
+}
+
+// content returns the data of the chunk wrapped by a feed update. Updates
+// with a timestamp and a reference resolve to the referenced content.
+func (s *Server) content(wrapped swarm.Chunk) []byte {
+	payload := wrapped.Data()[swarm.SpanSize:]
+	if len(payload) == 8+swarm.HashSize {
+		s.mu.Lock()
+		u, ok := s.uploads[swarm.NewAddress(payload[8:]).ByteString()]
+		s.mu.Unlock()
+		if ok {
+			return u.data
+		}
+	}
+	return payload
+}
+
+func (s *Server) sendPSS(w http.ResponseWriter, r *http.Request) {
+	if !s.validBatch(w, r) {
+		return
+	}
+	if _, err := hex.DecodeString(r.PathValue("targets")); err != nil {
+		jsonError(w, http.StatusBadRequest, "invalid targets")
+		return
+	}
+	jsonResponse(w, http.StatusCreated, struct{}{})
+}
+
+func (s *Server) pinsHandler(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	refs := make([]swarm.Address, 0, len(s.pins))
+	for ref := range s.pins {
+		refs = append(refs, swarm.NewAddress([]byte(ref)))
+	}
+	s.mu.Unlock()
+
+	sort.Slice(refs, func(i, j int) bool { return refs[i].String() < refs[j].String() })
+
+	jsonResponse(w, http.StatusOK, struct {
+		References []swarm.Address `json:"references"`
+	}{References: refs})
+}
+
+func (s *Server) pinHandler(w http.ResponseWriter, r *http.Request) {
+	ref, ok := pathAddress(w, r)
+	if !ok {
+		return
+	}
+
+	if !s.Pinned(ref) {
+		jsonError(w, http.StatusNotFound, "pin not found")
+		return
+	}
+	jsonResponse(w, http.StatusOK, struct {
+		Reference swarm.Address `json:"reference"`
+	}{Reference: ref})
+}
+
+func (s *Server) createPin(w http.ResponseWriter, r *http.Request) {
+	ref, ok := pathAddress(w, r)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	ok = s.pin(ref)
+	s.mu.Unlock()
+
+	if !ok {
+		jsonError(w, http.StatusNotFound, "content not found")
+		return
+	}
+	jsonError(w, http.StatusCreated, http.StatusText(http.StatusCreated))
+}
+
+func (s *Server) deletePin(w http.ResponseWriter, r *http.Request) {
+	ref, ok := pathAddress(w, r)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	ok = s.unpin(ref)
+	s.mu.Unlock()
+
+	if !ok {
+		jsonError(w, http.StatusNotFound, "pin not found")
+		return
+	}
+	jsonError(w, http.StatusOK, http.StatusText(http.StatusOK))
+}
+
+func (s *Server) isRetrievable(w http.ResponseWriter, r *http.Request) {
+	ref, ok := pathAddress(w, r)
+	if !ok {
+		return
+	}
+	jsonResponse(w, http.StatusOK, struct {
+		IsRetrievable bool `json:"isRetrievable"`
+	}{IsRetrievable: s.known(ref)})
+}
+
+func (s *Server) reupload(w http.ResponseWriter, r *http.Request) {
+	ref, ok := pathAddress(w, r)
+	if !ok {
+		return
+	}
+
+	if !s.known(ref) {
+		jsonError(w, http.StatusNotFound, "content not found")
+		return
+	}
+	jsonError(w, http.StatusOK, http.StatusText(http.StatusOK))
+}
+
+func (s *Server) createTag(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	t := &api.TagResponse{
+		Uid:       uint64(len(s.tags) + 1),
+		StartedAt: time.Now(),
+	}
+	s.tags[t.Uid] = t
+	resp := *t
+	s.mu.Unlock()
+
+	jsonResponse(w, http.StatusCreated, resp)
+}
+
+func (s *Server) tag(w http.ResponseWriter, r *http.Request) {
+	uid, err := strconv.ParseUint(r.PathValue("uid"), 10, 64)
+	if err != nil {
+		jsonError(w, http.StatusBadRequest, "invalid tag")
+		return
+	}
+
+	s.mu.Lock()
+	t, ok := s.tags[uid]
+	var resp api.TagResponse
+	if ok {
+		resp = *t
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		jsonError(w, http.StatusNotFound, "tag not present")
+		return
+	}
+	jsonResponse(w, http.StatusOK, resp)
+}
+
+// pathFeed parses the owner and topic path values, writing an error response
+// if they are not valid
+func pathFeed(w http.ResponseWriter, r *http.Request) (feed, bool) {
+	owner, err := hex.DecodeString(r.PathValue("owner"))
+	if err != nil || len(owner) != crypto.AddressSize {
+		jsonError(w, http.StatusBadRequest, "invalid owner")
+		return feed{}, false
+	}
+	topic, err := hex.DecodeString(r.PathValue("topic"))
+	if err != nil {
+		jsonError(w, http.StatusBadRequest, "invalid topic")
+		return feed{}, false
+	}
+	return feed{owner: owner, topic: topic}, true
+}
+
+func socKey(owner, id []byte) string {
+	return hex.EncodeToString(owner) + "/" + hex.EncodeToString(id)
+}
+
+// feedIndex encodes the feed index the way Bee does in response headers
+func feedIndex(i uint64) string {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, i)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,418 @@
+package beetest
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"math/big"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethersphere/bee/v2/pkg/swarm"
+	"github.com/ethersphere/beekeeper/pkg/bee/api"
+	"github.com/ethersphere/beekeeper/pkg/bigint"
+)
+
+func (s *Server) routes() {
+	s.mux.HandleFunc("GET /health", s.health)
+	s.mux.HandleFunc("GET /readiness", s.health)
+	s.mux.HandleFunc("GET /addresses", s.addresses)
+	s.mux.HandleFunc("GET /peers", s.peersHandler)
+	s.mux.HandleFunc("DELETE /peers/{address}", s.disconnect)
+	s.mux.HandleFunc("POST /connect/{multiaddr...}", s.connect)
+	s.mux.HandleFunc("GET /topology", s.topology)
+	s.mux.HandleFunc("POST /pingpong/{address}", s.pingpong)
+	s.mux.HandleFunc("GET /status", s.status)
+	s.mux.HandleFunc("GET /debugstore", s.debugStore)
+	s.mux.HandleFunc("GET /accounting", s.accounting)
+	s.mux.HandleFunc("GET /balances", s.balances)
+	s.mux.HandleFunc("GET /balances/{address}", s.balance)
+	s.mux.HandleFunc("GET /settlements", s.settlements)
+	s.mux.HandleFunc("GET /settlements/{address}", s.settlement)
+	s.mux.HandleFunc("GET /chequebook/balance", s.chequebookBalance)
+	s.mux.HandleFunc("GET /chequebook/cashout/{address}", s.cashoutStatus)
+	s.mux.HandleFunc("POST /chequebook/cashout/{address}", s.cashout)
+	s.mux.HandleFunc("GET /wallet", s.wallet)
+	s.mux.HandleFunc("POST /wallet/withdraw/{token}", s.withdraw)
+	s.mux.HandleFunc("GET /transactions", s.transactionsHandler)
+	s.mux.HandleFunc("GET /transactions/{hash}", s.transaction)
+	s.mux.HandleFunc("POST /transactions/{hash}", s.resendTransaction)
+	s.mux.HandleFunc("DELETE /transactions/{hash}", s.cancelTransaction)
+
+	s.mux.HandleFunc("GET /chainstate", s.chainState)
+	s.mux.HandleFunc("GET /reservestate", s.reserveState)
+	s.mux.HandleFunc("GET /stamps", s.stamps)
+	s.mux.HandleFunc("GET /stamps/{id}", s.stamp)
+	s.mux.HandleFunc("POST /stamps/{amount}/{depth}", s.createStamp)
+	s.mux.HandleFunc("PATCH /stamps/topup/{id}/{amount}", s.topUpStamp)
+	s.mux.HandleFunc("PATCH /stamps/dilute/{id}/{depth}", s.diluteStamp)
+
+	s.mux.HandleFunc("GET /stake", s.stake)
+	s.mux.HandleFunc("POST /stake/{amount}", s.depositStake)
+	s.mux.HandleFunc("GET /stake/withdrawable", s.withdrawableStake)
+	s.mux.HandleFunc("DELETE /stake", s.migrateStake)
+	s.mux.HandleFunc("GET /redistributionstate", s.redistributionState)
+	s.mux.HandleFunc("GET /rchash/{depth}/{anchor1}/{anchor2}", s.rcHash)
+
+	s.mux.HandleFunc("GET /chunks/{address}", s.hasChunk)
+	s.mux.HandleFunc("POST /v1/chunks", s.uploadChunk)
+	s.mux.HandleFunc("GET /v1/chunks/{address}", s.downloadChunk)
+	s.mux.HandleFunc("POST /v1/bytes", s.uploadBytes)
+	s.mux.HandleFunc("GET /v1/bytes/{address}", s.downloadBytes)
+	s.mux.HandleFunc("POST /v1/bzz", s.uploadBzz)
+	s.mux.HandleFunc("GET /v1/bzz/{address}", s.downloadBzz)
+	s.mux.HandleFunc("GET /v1/bzz/{address}/{path...}", s.downloadBzz)
+	s.mux.HandleFunc("POST /v1/soc/{owner}/{id}", s.uploadSOC)
+	s.mux.HandleFunc("POST /v1/feeds/{owner}/{topic}", s.createFeed)
+	s.mux.HandleFunc("GET /v1/feeds/{owner}/{topic}", s.findFeedUpdate)
+	s.mux.HandleFunc("POST /v1/pss/send/{topic}/{targets}", s.sendPSS)
+
+	s.mux.HandleFunc("GET /pins", s.pinsHandler)
+	s.mux.HandleFunc("GET /pins/{address}", s.pinHandler)
+	s.mux.HandleFunc("POST /pins/{address}", s.createPin)
+	s.mux.HandleFunc("DELETE /pins/{address}", s.deletePin)
+	s.mux.HandleFunc("GET /stewardship/{address}", s.isRetrievable)
+	s.mux.HandleFunc("PUT /stewardship/{address}", s.reupload)
+	s.mux.HandleFunc("POST /tags", s.createTag)
+	s.mux.HandleFunc("GET /tags/{uid}", s.tag)
+}
+
+func (s *Server) health(w http.ResponseWriter, r *http.Request) {
+	jsonResponse(w, http.StatusOK, api.Health{Status: "ok", Version: Version, APIVersion: Version})
+}
+
+func (s *Server) addresses(w http.ResponseWriter, r *http.Request) {
+	key := sha256.Sum256(s.overlay.Bytes())
+	jsonResponse(w, http.StatusOK, api.Addresses{
+		Ethereum:     s.ethereum,
+		Overlay:      s.overlay,
+		PublicKey:    "02" + hex.EncodeToString(key[:]),
+		Underlay:     []string{"/ip4/127.0.0.1/tcp/1634/p2p/" + hex.EncodeToString(key[:8])},
+		PSSPublicKey: "03" + hex.EncodeToString(key[:]),
+	})
+}
+
+// connectedPeers returns peers reported by the network of the node, or the
+// peers set by WithPeers and connected by the connect endpoint
+func (s *Server) connectedPeers() []Peer {
+	if s.network != nil {
+		return s.network.Peers()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	peers := make([]Peer, 0, len(s.peers))
+	for _, p := range s.peers {
+		peers = append(peers, Peer{Overlay: p})
+	}
+	return peers
+}
+
+// connected returns whether the peer is connected to the node
+func (s *Server) connected(peer swarm.Address) bool {
+	return slices.ContainsFunc(s.connectedPeers(), func(p Peer) bool { return p.Overlay.Equal(peer) })
+}
+
+func (s *Server) peersHandler(w http.ResponseWriter, r *http.Request) {
+	peers := []api.Peer{}
+	for _, p := range s.connectedPeers() {
+		peers = append(peers, api.Peer{Address: p.Overlay})
+	}
+
+	jsonResponse(w, http.StatusOK, api.Peers{Peers: peers})
+}
+
+func (s *Server) connect(w http.ResponseWriter, r *http.Request) {
+	h := sha256.Sum256([]byte(r.PathValue("multiaddr")))
+	peer := swarm.NewAddress(h[:])
+
+	s.mu.Lock()
+	if !slices.ContainsFunc(s.peers, peer.Equal) {
+		s.peers = append(s.peers, peer)
+	}
+	s.mu.Unlock()
+
+	jsonResponse(w, http.StatusOK, api.ConnectResponse{Address: peer.String()})
+}
+
+func (s *Server) disconnect(w http.ResponseWriter, r *http.Request) {
+	peer, ok := pathAddress(w, r)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	i := slices.IndexFunc(s.peers, peer.Equal)
+	if i >= 0 {
+		s.peers = slices.Delete(s.peers, i, i+1)
+	}
+	s.mu.Unlock()
+
+	if i < 0 {
+		jsonError(w, http.StatusBadRequest, "peer not found")
+		return
+	}
+	jsonResponse(w, http.StatusOK, struct{}{})
+}
+
+// topology places full node peers into bins by their proximity order and
+// sets depth to the deepest bin that keeps at least nnLowWatermark peers in
+// the neighborhood
+func (s *Server) topology(w http.ResponseWriter, r *http.Request) {
+	resp := api.Topology{
+		BaseAddr:            s.overlay,
+		Timestamp:           time.Now(),
+		NnLowWatermark:      nnLowWatermark,
+		Bins:                make(map[string]api.Bin, swarm.MaxBins),
+		LightNodes:          api.Bin{ConnectedPeers: []api.PeerInfo{}, DisconnectedPeers: []api.PeerInfo{}},
+		Reachability:        "Public",
+		NetworkAvailability: "Available",
+	}
+
+	bins := make([]api.Bin, swarm.MaxBins)
+	for _, p := range s.connectedPeers() {
+		if p.LightNode {
+			resp.LightNodes.ConnectedPeers = append(resp.LightNodes.ConnectedPeers, api.PeerInfo{Address: p.Overlay})
+			resp.LightNodes.Connected++
+			resp.LightNodes.Population++
+			continue
+		}
+		po := swarm.Proximity(s.overlay.Bytes(), p.Overlay.Bytes())
+		bins[po].ConnectedPeers = append(bins[po].ConnectedPeers, api.PeerInfo{Address: p.Overlay})
+		bins[po].Connected++
+		bins[po].Population++
+		resp.Connected++
+		resp.Population++
+	}
+
+	for po, bin := range bins {
+		if bin.ConnectedPeers == nil {
+			bin.ConnectedPeers = []api.PeerInfo{}
+		}
+		bin.DisconnectedPeers = []api.PeerInfo{}
+		resp.Bins["bin_"+strconv.Itoa(po)] = bin
+	}
+
+	for depth, neighbors := 0, resp.Connected; depth < len(bins); depth++ {
+		if neighbors < nnLowWatermark {
+			break
+		}
+		resp.Depth = depth
+		neighbors -= bins[depth].Connected
+	}
+
+	jsonResponse(w, http.StatusOK, resp)
+}
+
+func (s *Server) pingpong(w http.ResponseWriter, r *http.Request) {
+	peer, ok := pathAddress(w, r)
+	if !ok {
+		return
+	}
+
+	if !s.connected(peer) {
+		jsonError(w, http.StatusNotFound, "peer not found")
+		return
+	}
+	jsonResponse(w, http.StatusOK, api.Pong{RTT: "1ms"})
+}
+
+func (s *Server) status(w http.ResponseWriter, r *http.Request) {
+	mode := "full"
+	if s.lightNode {
+		mode = "light"
+	}
+
+	peers := s.connectedPeers()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	jsonResponse(w, http.StatusOK, api.StatusResponse{
+		Overlay:        s.overlay.String(),
+		BeeMode:        mode,
+		ReserveSize:    uint64(len(s.chunks)),
+		ConnectedPeers: uint64(len(peers)),
+		IsReachable:    true,
+	})
+}
+
+func (s *Server) debugStore(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	jsonResponse(w, http.StatusOK, api.DebugStore{"chunks": len(s.chunks)})
+}
+
+func (s *Server) accounting(w http.ResponseWriter, r *http.Request) {
+	peers := s.connectedPeers()
+	accounting := make(map[string]api.Account, len(peers))
+	for _, p := range peers {
+		accounting[p.Overlay.String()] = api.Account{Balance: bigint.Wrap(new(big.Int))}
+	}
+
+	jsonResponse(w, http.StatusOK, api.Accounting{Accounting: accounting})
+}
+
+func (s *Server) balances(w http.ResponseWriter, r *http.Request) {
+	peers := s.connectedPeers()
+	balances := make([]api.Balance, 0, len(peers))
+	for _, p := range peers {
+		balances = append(balances, api.Balance{Peer: p.Overlay.String(), Balance: bigint.Wrap(new(big.Int))})
+	}
+
+	jsonResponse(w, http.StatusOK, api.Balances{Balances: balances})
+}
+
+func (s *Server) balance(w http.ResponseWriter, r *http.Request) {
+	peer, ok := s.pathPeer(w, r)
+	if !ok {
+		return
+	}
+	jsonResponse(w, http.StatusOK, api.Balance{Peer: peer.String(), Balance: bigint.Wrap(new(big.Int))})
+}
+
+func (s *Server) settlements(w http.ResponseWriter, r *http.Request) {
+	peers := s.connectedPeers()
+	settlements := make([]api.Settlement, 0, len(peers))
+	for _, p := range peers {
+		settlements = append(settlements, api.Settlement{
+			Peer:     p.Overlay.String(),
+			Received: bigint.Wrap(new(big.Int)),
+			Sent:     bigint.Wrap(new(big.Int)),
+		})
+	}
+
+	jsonResponse(w, http.StatusOK, api.Settlements{
+		Settlements:   settlements,
+		TotalReceived: bigint.Wrap(new(big.Int)),
+		TotalSent:     bigint.Wrap(new(big.Int)),
+	})
+}
+
+func (s *Server) settlement(w http.ResponseWriter, r *http.Request) {
+	peer, ok := s.pathPeer(w, r)
+	if !ok {
+		return
+	}
+	jsonResponse(w, http.StatusOK, api.Settlement{
+		Peer:     peer.String(),
+		Received: bigint.Wrap(new(big.Int)),
+		Sent:     bigint.Wrap(new(big.Int)),
+	})
+}
+
+func (s *Server) chequebookBalance(w http.ResponseWriter, r *http.Request) {
+	jsonResponse(w, http.StatusOK, api.ChequebookBalanceResponse{
+		TotalBalance:     bigint.Wrap(new(big.Int)),
+		AvailableBalance: bigint.Wrap(new(big.Int)),
+	})
+}
+
+func (s *Server) cashoutStatus(w http.ResponseWriter, r *http.Request) {
+	peer, ok := s.pathPeer(w, r)
+	if !ok {
+		return
+	}
+	jsonResponse(w, http.StatusOK, api.CashoutStatusResponse{
+		Peer:           peer,
+		UncashedAmount: bigint.Wrap(new(big.Int)),
+	})
+}
+
+func (s *Server) cashout(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.pathPeer(w, r); !ok {
+		return
+	}
+	jsonResponse(w, http.StatusOK, api.TransactionHashResponse{TransactionHash: s.transactionHash()})
+}
+
+func (s *Server) wallet(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	jsonResponse(w, http.StatusOK, api.Wallet{
+		BZZ:         bigint.Wrap(new(big.Int).Set(s.bzzBalance)),
+		NativeToken: bigint.Wrap(new(big.Int).Set(s.nativeBalance)),
+	})
+}
+
+func (s *Server) withdraw(w http.ResponseWriter, r *http.Request) {
+	amount, ok := new(big.Int).SetString(r.URL.Query().Get("amount"), 10)
+	if !ok || amount.Sign() <= 0 {
+		jsonError(w, http.StatusBadRequest, "invalid amount")
+		return
+	}
+	if address := r.URL.Query().Get("address"); !common.IsHexAddress(address) || common.HexToAddress(address) == (common.Address{}) {
+		jsonError(w, http.StatusBadRequest, "invalid address")
+		return
+	}
+
+	s.mu.Lock()
+	var balance *big.Int
+	switch r.PathValue("token") {
+	case "BZZ":
+		balance = s.bzzBalance
+	case "NativeToken":
+		balance = s.nativeBalance
+	}
+	if balance == nil {
+		s.mu.Unlock()
+		jsonError(w, http.StatusBadRequest, "invalid coin type")
+		return
+	}
+	if balance.Cmp(amount) < 0 {
+		s.mu.Unlock()
+		jsonError(w, http.StatusBadRequest, "not enough balance")
+		return
+	}
+	balance.Sub(balance, amount)
+	s.mu.Unlock()
+
+	jsonResponse(w, http.StatusOK, struct {
+		TransactionHash string `json:"transactionHash"`
+	}{TransactionHash: s.transactionHash()})
+}
+
+// pathPeer parses the address path value and checks that it is a peer of the
+// node, writing an error response if it is not
+func (s *Server) pathPeer(w http.ResponseWriter, r *http.Request) (swarm.Address, bool) {
+	peer, ok := pathAddress(w, r)
+	if !ok {
+		return swarm.ZeroAddress, false
+	}
+
+	if !s.connected(peer) {
+		jsonError(w, http.StatusNotFound, "peer not found")
+		return swarm.ZeroAddress, false
+	}
+	return peer, true
+}
+
+// transactionHash returns a unique transaction hash
+func (s *Server) transactionHash() string {
+	s.mu.Lock()
+	s.transactionID++
+	id := s.transactionID
+	s.mu.Unlock()
+
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, id)
+	h := sha256.Sum256(append(s.overlay.Bytes(), b...))
+	return "0x" + hex.EncodeToString(h[:])
+}
+
+// pathAddress parses the address path value, writing an error response if it
+// is not valid
+func pathAddress(w http.ResponseWriter, r *http.Request) (swarm.Address, bool) {
+	addr, err := swarm.ParseHexAddress(r.PathValue("address"))
+	if err != nil {
+		jsonError(w, http.StatusBadRequest, "invalid address")
+		return swarm.ZeroAddress, false
+	}
+	return addr, true
+}
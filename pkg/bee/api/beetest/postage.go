@@ -0,0 +1,231 @@
+package beetest
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"math/big"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/ethersphere/beekeeper/pkg/bee/api"
+	"github.com/ethersphere/beekeeper/pkg/bigint"
+)
+
+// AddBatch adds the postage batch to the node, replacing the batch with the
+// same ID
+func (s *Server) AddBatch(batch api.PostageStampResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.batches[batch.BatchID] = &batch
+}
+
+// Batch returns the postage batch of the node with the ID
+func (s *Server) Batch(id string) (api.PostageStampResponse, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.batches[id]
+	if !ok {
+		return api.PostageStampResponse{}, false
+	}
+	return *b, true
+}
+
+func (s *Server) chainState(w http.ResponseWriter, r *http.Request) {
+	jsonResponse(w, http.StatusOK, api.ChainStateResponse{
+		ChainTip:     1000,
+		Block:        1000,
+		TotalAmount:  bigint.Wrap(new(big.Int)),
+		CurrentPrice: bigint.Wrap(big.NewInt(s.currentPrice)),
+	})
+}
+
+func (s *Server) reserveState(w http.ResponseWriter, r *http.Request) {
+	jsonResponse(w, http.StatusOK, api.ReserveState{})
+}
+
+func (s *Server) stamps(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	stamps := make([]api.PostageStampResponse, 0, len(s.batches))
+	for _, b := range s.batches {
+		stamps = append(stamps, *b)
+	}
+	s.mu.Unlock()
+
+	sort.Slice(stamps, func(i, j int) bool { return stamps[i].BatchID < stamps[j].BatchID })
+
+	jsonResponse(w, http.StatusOK, struct {
+		Stamps []api.PostageStampResponse `json:"stamps"`
+	}{Stamps: stamps})
+}
+
+func (s *Server) stamp(w http.ResponseWriter, r *http.Request) {
+	b, ok := s.Batch(r.PathValue("id"))
+	if !ok {
+		jsonError(w, http.StatusNotFound, "issuer does not exist")
+		return
+	}
+	jsonResponse(w, http.StatusOK, b)
+}
+
+func (s *Server) createStamp(w http.ResponseWriter, r *http.Request) {
+	amount, ok := new(big.Int).SetString(r.PathValue("amount"), 10)
+	if !ok || amount.Sign() <= 0 {
+		jsonError(w, http.StatusBadRequest, "invalid amount")
+		return
+	}
+	depth, err := strconv.ParseUint(r.PathValue("depth"), 10, 8)
+	if err != nil {
+		jsonError(w, http.StatusBadRequest, "invalid depth")
+		return
+	}
+
+	s.mu.Lock()
+	nonce := make([]byte, 8)
+	binary.BigEndian.PutUint64(nonce, s.nonce)
+	s.nonce++
+	h := sha256.Sum256(append(s.overlay.Bytes(), nonce...))
+	id := hex.EncodeToString(h[:])
+
+	s.batches[id] = &api.PostageStampResponse{
+		BatchID:       id,
+		Usable:        true,
+		Label:         r.URL.Query().Get("label"),
+		Depth:         uint8(depth),
+		Amount:        bigint.Wrap(amount),
+		BucketDepth:   bucketDepth,
+		BlockNumber:   1000,
+		ImmutableFlag: r.Header.Get("Immutable") == "true",
+		Exists:        true,
+		BatchTTL:      s.batchTTL(amount),
+	}
+	s.mu.Unlock()
+
+	jsonResponse(w, http.StatusCreated, struct {
+		BatchID string `json:"batchID"`
+	}{BatchID: id})
+}
+
+func (s *Server) topUpStamp(w http.ResponseWriter, r *http.Request) {
+	amount, ok := new(big.Int).SetString(r.PathValue("amount"), 10)
+	if !ok || amount.Sign() <= 0 {
+		jsonError(w, http.StatusBadRequest, "invalid amount")
+		return
+	}
+
+	s.mu.Lock()
+	b, ok := s.batches[r.PathValue("id")]
+	if ok {
+		total := new(big.Int).Add(b.Amount.Int, amount)
+		b.Amount = bigint.Wrap(total)
+		b.BatchTTL = s.batchTTL(total)
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		jsonError(w, http.StatusNotFound, "batch not found")
+		return
+	}
+	jsonResponse(w, http.StatusAccepted, struct {
+		BatchID string `json:"batchID"`
+	}{BatchID: r.PathValue("id")})
+}
+
+func (s *Server) diluteStamp(w http.ResponseWriter, r *http.Request) {
+	depth, err := strconv.ParseUint(r.PathValue("depth"), 10, 8)
+	if err != nil {
+		jsonError(w, http.StatusBadRequest, "invalid depth")
+		return
+	}
+
+	s.mu.Lock()
+	b, ok := s.batches[r.PathValue("id")]
+	valid := ok && uint8(depth) > b.Depth
+	if valid {
+		// the amount per chunk is spread over twice as many chunks for every
+		// additional depth
+		b.BatchTTL >>= uint8(depth) - b.Depth
+		b.Depth = uint8(depth)
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		jsonError(w, http.StatusNotFound, "batch not found")
+		return
+	}
+	if !valid {
+		jsonError(w, http.StatusBadRequest, "invalid depth")
+		return
+	}
+	jsonResponse(w, http.StatusAccepted, struct {
+		BatchID string `json:"batchID"`
+	}{BatchID: r.PathValue("id")})
+}
+
+// batchTTL returns the TTL in seconds of a batch with the amount per chunk
+func (s *Server) batchTTL(amount *big.Int) int64 {
+	ttl := new(big.Int).Div(amount, big.NewInt(s.currentPrice))
+	return ttl.Mul(ttl, big.NewInt(DefaultBlockTime)).Int64()
+}
+
+func (s *Server) stake(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	jsonResponse(w, http.StatusOK, struct {
+		StakedAmount *bigint.BigInt `json:"stakedAmount"`
+	}{StakedAmount: bigint.Wrap(new(big.Int).Set(s.staked))})
+}
+
+func (s *Server) withdrawableStake(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	jsonResponse(w, http.StatusOK, struct {
+		WithdrawableAmount *bigint.BigInt `json:"withdrawableAmount"`
+	}{WithdrawableAmount: bigint.Wrap(new(big.Int).Set(s.withdrawable))})
+}
+
+func (s *Server) depositStake(w http.ResponseWriter, r *http.Request) {
+	amount, ok := new(big.Int).SetString(r.PathValue("amount"), 10)
+	if !ok || amount.Sign() <= 0 {
+		jsonError(w, http.StatusBadRequest, "invalid amount")
+		return
+	}
+
+	s.mu.Lock()
+	if s.bzzBalance.Cmp(amount) < 0 {
+		s.mu.Unlock()
+		jsonError(w, http.StatusBadRequest, "insufficient balance")
+		return
+	}
+	s.bzzBalance.Sub(s.bzzBalance, amount)
+	s.staked.Add(s.staked, amount)
+	staked := new(big.Int).Set(s.staked)
+	s.mu.Unlock()
+
+	if s.game != nil {
+		s.game.Staked(staked)
+	}
+
+	jsonResponse(w, http.StatusOK, struct {
+		TxHash string `json:"txhash"`
+	}{TxHash: s.transactionHash()})
+}
+
+func (s *Server) migrateStake(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.withdrawable.Add(s.withdrawable, s.staked)
+	s.staked.SetInt64(0)
+	s.mu.Unlock()
+
+	if s.game != nil {
+		s.game.Staked(new(big.Int))
+	}
+
+	jsonResponse(w, http.StatusOK, struct {
+		TxHash string `json:"txhash"`
+	}{TxHash: s.transactionHash()})
+}
@@ -0,0 +1,92 @@
+package beetest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"math/big"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/ethersphere/bee/v2/pkg/swarm"
+	"github.com/ethersphere/beekeeper/pkg/bee/api"
+	"github.com/ethersphere/beekeeper/pkg/bigint"
+)
+
+// SetRedistributionState sets the state of the node in the storage
+// incentives redistribution game
+func (s *Server) SetRedistributionState(state api.RedistributionState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.redistribution = state
+}
+
+// defaultRedistributionState is the state of a healthy node that has not
+// played yet
+func defaultRedistributionState() api.RedistributionState {
+	return api.RedistributionState{
+		MinimumGasFunds:    bigint.Wrap(big.NewInt(1e15)),
+		HasSufficientFunds: true,
+		IsFullySynced:      true,
+		Phase:              "commit",
+		Round:              1,
+		Block:              1000,
+		Reward:             bigint.Wrap(new(big.Int)),
+		Fees:               bigint.Wrap(new(big.Int)),
+		IsHealthy:          true,
+	}
+}
+
+func (s *Server) redistributionState(w http.ResponseWriter, r *http.Request) {
+	if s.game != nil {
+		jsonResponse(w, http.StatusOK, s.game.State())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	jsonResponse(w, http.StatusOK, s.redistribution)
+}
+
+// rcHash hashes addresses of stored chunks within the depth from the overlay
+// together with the anchor, so that nodes storing the same chunks return the
+// same hash
+func (s *Server) rcHash(w http.ResponseWriter, r *http.Request) {
+	depth, err := strconv.ParseUint(r.PathValue("depth"), 10, 8)
+	if err != nil {
+		jsonError(w, http.StatusBadRequest, "invalid depth")
+		return
+	}
+	anchor, err := hex.DecodeString(r.PathValue("anchor1"))
+	if err != nil {
+		jsonError(w, http.StatusBadRequest, "invalid anchor")
+		return
+	}
+	if _, err := hex.DecodeString(r.PathValue("anchor2")); err != nil {
+		jsonError(w, http.StatusBadRequest, "invalid anchor")
+		return
+	}
+
+	s.mu.Lock()
+	var addrs [][]byte
+	for k := range s.chunks {
+		addr := []byte(k)
+		if swarm.Proximity(s.overlay.Bytes(), addr) >= uint8(depth) {
+			addrs = append(addrs, addr)
+		}
+	}
+	s.mu.Unlock()
+
+	slices.SortFunc(addrs, bytes.Compare)
+
+	h := sha256.New()
+	h.Write([]byte{byte(depth)})
+	h.Write(anchor)
+	for _, addr := range addrs {
+		h.Write(addr)
+	}
+
+	jsonResponse(w, http.StatusOK, api.RCHashResponse{Hash: swarm.NewAddress(h.Sum(nil))})
+}
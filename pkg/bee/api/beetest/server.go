@@ -0,0 +1,432 @@
+// Package beetest provides a mock Bee API server for tests of the API client
+// and of packages that use it. The server implements the Bee REST endpoints
+// used by api.Client on top of in-memory state: chunks, bytes and
+// collections, single owner chunks and feeds, postage batches, staking and
+// the redistribution game, the wallet, pending transactions, tags, pins and
+// the node's addresses and peers. Files are stored without manifests and
+// access control endpoints are not implemented; tests that need them can
+// script their responses.
+//
+// Servers can be connected to a network of other nodes, which pushes uploaded
+// chunks to the nodes that store them and retrieves chunks that the node does
+// not store, and the redistribution game can be played by the network
+// instead of being set by tests.
+//
+// Responses of any endpoint can be scripted, requests can be delayed or made
+// to fail, and all requests are recorded, so that tests can assert both how
+// a client behaves on errors and what it sent to the node.
+package beetest
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethersphere/bee/v2/pkg/swarm"
+	"github.com/ethersphere/beekeeper/pkg/bee/api"
+)
+
+const (
+	// Version is reported by the health endpoint
+	Version = "0.0.0-beetest"
+
+	// DefaultCurrentPrice is the price per chunk per block reported by the
+	// chain state endpoint
+	DefaultCurrentPrice = 24000
+	// DefaultBlockTime is the block time in seconds used to calculate TTLs
+	// of postage batches
+	DefaultBlockTime = 5
+	// bucketDepth is the bucket depth of postage batches
+	bucketDepth = 16
+	// nnLowWatermark is the minimum number of peers in the neighborhood
+	nnLowWatermark = 2
+)
+
+// Request is a request received by the server
+type Request struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+}
+
+// Peer is a peer connected to the node
+type Peer struct {
+	Overlay   swarm.Address
+	LightNode bool
+}
+
+// Network connects the node to other nodes
+type Network interface {
+	// Peers returns peers connected to the node
+	Peers() []Peer
+	// Push stores the uploaded chunk on the nodes responsible for it
+	Push(ch swarm.Chunk)
+	// Retrieve returns data of a chunk that the node does not store
+	Retrieve(addr swarm.Address) ([]byte, bool)
+}
+
+// Redistribution plays the storage incentives redistribution game for the
+// node
+type Redistribution interface {
+	// Staked is called with the stake of the node after it deposits stake
+	Staked(stake *big.Int)
+	// State returns the state of the node in the game
+	State() api.RedistributionState
+}
+
+// Option configures the server
+type Option func(*Server)
+
+// WithOverlay sets the overlay address of the node
+func WithOverlay(overlay swarm.Address) Option {
+	return func(s *Server) {
+		s.overlay = overlay
+	}
+}
+
+// WithPeers sets the overlay addresses of peers connected to the node
+func WithPeers(peers ...swarm.Address) Option {
+	return func(s *Server) {
+		s.peers = append([]swarm.Address(nil), peers...)
+	}
+}
+
+// WithNetwork connects the node to the network. Peers of the node are
+// reported by the network instead of being set by WithPeers, and chunks
+// uploaded by the chunk and soc endpoints are pushed to the network instead of
+// being stored on the node.
+func WithNetwork(n Network) Option {
+	return func(s *Server) {
+		s.network = n
+	}
+}
+
+// WithRedistribution makes the game report the state of the node in the
+// redistribution game, instead of the state set by SetRedistributionState
+func WithRedistribution(r Redistribution) Option {
+	return func(s *Server) {
+		s.game = r
+	}
+}
+
+// WithCapacity sets the maximum number of unpinned chunks stored on the node.
+// The oldest unpinned chunks are evicted when the node stores more of them.
+// Nodes without a capacity never evict chunks.
+func WithCapacity(chunks uint64) Option {
+	return func(s *Server) {
+		s.capacity = chunks
+	}
+}
+
+// WithLightNode makes the node report itself as a light node
+func WithLightNode() Option {
+	return func(s *Server) {
+		s.lightNode = true
+	}
+}
+
+// WithWallet sets the BZZ and native token balances of the node's wallet
+func WithWallet(bzz, nativeToken *big.Int) Option {
+	return func(s *Server) {
+		s.bzzBalance = new(big.Int).Set(bzz)
+		s.nativeBalance = new(big.Int).Set(nativeToken)
+	}
+}
+
+// WithCurrentPrice sets the price per chunk per block of the chain state
+func WithCurrentPrice(price int64) Option {
+	return func(s *Server) {
+		s.currentPrice = price
+	}
+}
+
+// WithLatency delays responses to all requests
+func WithLatency(d time.Duration) Option {
+	return func(s *Server) {
+		s.latency = d
+	}
+}
+
+// Server is a mock Bee API server
+type Server struct {
+	server *httptest.Server
+	mux    *http.ServeMux
+
+	overlay       swarm.Address
+	ethereum      string
+	network       Network
+	game          Redistribution
+	capacity      uint64
+	lightNode     bool
+	currentPrice  int64
+	bzzBalance    *big.Int
+	nativeBalance *big.Int
+
+	mu             sync.Mutex
+	latency        time.Duration
+	requests       []Request
+	scriptMux      *http.ServeMux
+	scripts        map[string]http.HandlerFunc
+	failureMux     *http.ServeMux
+	failures       map[string]*failure
+	peers          []swarm.Address
+	chunks         map[string][]byte
+	order          []string // stored chunks in the order of storing
+	uploads        map[string]upload
+	socs           map[string]socUpdate
+	feeds          map[string]feed
+	batches        map[string]*api.PostageStampResponse
+	staked         *big.Int
+	withdrawable   *big.Int
+	tags           map[uint64]*api.TagResponse
+	pins           map[string][]swarm.Address // chunks of pinned content by reference
+	pinned         map[string]int             // number of pins of stored chunks
+	redistribution api.RedistributionState
+	transactions   map[common.Hash]*api.TransactionInfo
+	resent         map[common.Hash]int
+	nonce          uint64
+	transactionID  uint64
+}
+
+type failure struct {
+	status int
+	count  int
+}
+
+// NewServer starts and returns a new server. The caller must call Close when
+// finished, to shut it down.
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		mux:            http.NewServeMux(),
+		overlay:        swarm.MustParseHexAddress("ca1e9f3938cc1425c6061b96ad9eb93e134dfe8734ad490164ef20af9d1cf59c"),
+		currentPrice:   DefaultCurrentPrice,
+		bzzBalance:     big.NewInt(1e16),
+		nativeBalance:  big.NewInt(1e18),
+		scriptMux:      http.NewServeMux(),
+		scripts:        make(map[string]http.HandlerFunc),
+		failureMux:     http.NewServeMux(),
+		failures:       make(map[string]*failure),
+		chunks:         make(map[string][]byte),
+		uploads:        make(map[string]upload),
+		socs:           make(map[string]socUpdate),
+		feeds:          make(map[string]feed),
+		batches:        make(map[string]*api.PostageStampResponse),
+		staked:         new(big.Int),
+		withdrawable:   new(big.Int),
+		tags:           make(map[uint64]*api.TagResponse),
+		pins:           make(map[string][]swarm.Address),
+		pinned:         make(map[string]int),
+		redistribution: defaultRedistributionState(),
+		transactions:   make(map[common.Hash]*api.TransactionInfo),
+		resent:         make(map[common.Hash]int),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	key := sha256.Sum256(s.overlay.Bytes())
+	s.ethereum = "0x" + hex.EncodeToString(key[:20])
+
+	s.routes()
+	s.server = httptest.NewServer(s)
+
+	return s
+}
+
+// New starts a new server that is closed when the test finishes and returns
+// it together with a client of its API
+func New(t testing.TB, opts ...Option) (*Server, *api.Client) {
+	t.Helper()
+
+	s := NewServer(opts...)
+	t.Cleanup(s.Close)
+
+	return s, s.Client()
+}
+
+// Close shuts down the server
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// URL returns the base URL of the server
+func (s *Server) URL() *url.URL {
+	u, _ := url.Parse(s.server.URL)
+	return u
+}
+
+// Client returns a new API client of the server
+func (s *Server) Client() *api.Client {
+	c, _ := api.NewClient(s.URL(), s.server.Client())
+	return c
+}
+
+// Overlay returns the overlay address of the node
+func (s *Server) Overlay() swarm.Address {
+	return s.overlay
+}
+
+// EthereumAddress returns the Ethereum address of the node
+func (s *Server) EthereumAddress() string {
+	return s.ethereum
+}
+
+// Handle scripts responses of requests matching the pattern, replacing the
+// default handler of the endpoint. Patterns have the syntax of
+// http.ServeMux patterns, for example "GET /stamps/{id}".
+func (s *Server) Handle(pattern string, handler http.HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.scripts[pattern]; !ok {
+		s.scriptMux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+			s.mu.Lock()
+			h := s.scripts[pattern]
+			s.mu.Unlock()
+			h(w, r)
+		})
+	}
+	s.scripts[pattern] = handler
+}
+
+// Respond scripts a response with the status code and JSON encoded body to
+// requests matching the pattern
+func (s *Server) Respond(pattern string, status int, body any) {
+	s.Handle(pattern, func(w http.ResponseWriter, r *http.Request) {
+		jsonResponse(w, status, body)
+	})
+}
+
+// Fail makes the next count requests matching the pattern fail with the
+// status code, before they reach the endpoint. A negative count makes all of
+// them fail, and a zero count none of them.
+func (s *Server) Fail(pattern string, status, count int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.failures[pattern]; !ok {
+		s.failureMux.HandleFunc(pattern, func(http.ResponseWriter, *http.Request) {})
+	}
+	s.failures[pattern] = &failure{status: status, count: count}
+}
+
+// SetLatency delays responses to all subsequent requests
+func (s *Server) SetLatency(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latency = d
+}
+
+// Requests returns requests received by the server in the order of arrival
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// RequestCount returns the number of received requests with the method and
+// path
+func (s *Server) RequestCount(method, path string) (count int) {
+	for _, r := range s.Requests() {
+		if r.Method == method && r.Path == path {
+			count++
+		}
+	}
+	return count
+}
+
+// ServeHTTP records the request, applies latency and injected failures, and
+// serves it by the scripted or the default handler of the endpoint.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		jsonError(w, http.StatusBadRequest, "read body")
+		return
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.Query(),
+		Header: r.Header.Clone(),
+		Body:   body,
+	})
+	latency := s.latency
+	s.mu.Unlock()
+
+	if err := sleep(r.Context(), latency); err != nil {
+		return
+	}
+
+	if status, ok := s.failure(r); ok {
+		jsonError(w, status, http.StatusText(status))
+		return
+	}
+
+	if _, pattern := s.scriptMux.Handler(r); pattern != "" {
+		s.scriptMux.ServeHTTP(w, r)
+		return
+	}
+
+	s.mux.ServeHTTP(w, r)
+}
+
+// failure returns the status code of an injected failure matching the
+// request
+func (s *Server) failure(r *http.Request) (status int, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, pattern := s.failureMux.Handler(r)
+	f, ok := s.failures[pattern]
+	if !ok || f.count == 0 {
+		return 0, false
+	}
+	if f.count > 0 {
+		f.count--
+	}
+	return f.status, true
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func jsonResponse(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func jsonError(w http.ResponseWriter, status int, message string) {
+	jsonResponse(w, status, struct {
+		Message string `json:"message"`
+		Code    int    `json:"code"`
+	}{Message: message, Code: status})
+}
@@ -0,0 +1,198 @@
+package beetest_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/v2/pkg/cac"
+	"github.com/ethersphere/bee/v2/pkg/swarm"
+	"github.com/ethersphere/beekeeper/pkg/bee/api"
+	"github.com/ethersphere/beekeeper/pkg/bee/api/beetest"
+)
+
+func TestRespond(t *testing.T) {
+	s, c := beetest.New(t)
+	ctx := context.Background()
+
+	s.Respond("GET /health", http.StatusOK, api.Health{Status: "ok", Version: "2.0.0"})
+
+	h, err := c.Node.Health(ctx)
+	if err != nil {
+		t.Fatalf("health: %v", err)
+	}
+	if h.Version != "2.0.0" {
+		t.Fatalf("version: got %s, want 2.0.0", h.Version)
+	}
+
+	s.Respond("GET /health", http.StatusServiceUnavailable, api.Health{Status: "nok"})
+
+	if _, err := c.Node.Health(ctx); !api.IsHTTPStatusErrorCode(err, http.StatusServiceUnavailable) {
+		t.Fatalf("health: got error %v, want status %d", err, http.StatusServiceUnavailable)
+	}
+}
+
+func TestFail(t *testing.T) {
+	s, c := beetest.New(t)
+	ctx := context.Background()
+
+	s.Fail("GET /stamps/{id}", http.StatusInternalServerError, 2)
+
+	for range 2 {
+		if _, err := c.Postage.PostageStamp(ctx, "batch"); !api.IsHTTPStatusErrorCode(err, http.StatusInternalServerError) {
+			t.Fatalf("got error %v, want status %d", err, http.StatusInternalServerError)
+		}
+	}
+	if _, err := c.Postage.PostageStamp(ctx, "batch"); !api.IsHTTPStatusErrorCode(err, http.StatusNotFound) {
+		t.Fatalf("got error %v, want status %d", err, http.StatusNotFound)
+	}
+	if _, err := c.Postage.PostageBatches(ctx); err != nil {
+		t.Fatalf("other endpoint failed: %v", err)
+	}
+}
+
+func TestLatency(t *testing.T) {
+	s, c := beetest.New(t, beetest.WithLatency(time.Second))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := c.Node.Addresses(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got error %v, want deadline exceeded", err)
+	}
+
+	s.SetLatency(0)
+	if _, err := c.Node.Addresses(context.Background()); err != nil {
+		t.Fatalf("addresses: %v", err)
+	}
+}
+
+func TestRequests(t *testing.T) {
+	peer := swarm.MustParseHexAddress("0a1b")
+	s, c := beetest.New(t, beetest.WithPeers(peer))
+	ctx := context.Background()
+
+	if _, err := c.PingPong.Ping(ctx, peer); err != nil {
+		t.Fatalf("ping: %v", err)
+	}
+	if _, err := c.Postage.CreatePostageBatch(ctx, 1000, 17, "label"); err != nil {
+		t.Fatalf("create batch: %v", err)
+	}
+
+	requests := s.Requests()
+	if len(requests) != 2 {
+		t.Fatalf("requests: got %d, want 2", len(requests))
+	}
+	if r := requests[0]; r.Method != http.MethodPost || r.Path != "/pingpong/"+peer.String() {
+		t.Fatalf("request: got %s %s", r.Method, r.Path)
+	}
+	if r := requests[1]; r.Path != "/stamps/1000/17" || r.Query.Get("label") != "label" || r.Header.Get("Immutable") != "false" {
+		t.Fatalf("request: got %s?%s with immutable %q", r.Path, r.Query.Encode(), r.Header.Get("Immutable"))
+	}
+	if got := s.RequestCount(http.MethodPost, "/stamps/1000/17"); got != 1 {
+		t.Fatalf("request count: got %d, want 1", got)
+	}
+}
+
+func TestCapacity(t *testing.T) {
+	s, c := beetest.New(t, beetest.WithCapacity(2))
+	ctx := context.Background()
+
+	batchID, err := c.Postage.CreatePostageBatch(ctx, 1000, 17, "capacity")
+	if err != nil {
+		t.Fatalf("create batch: %v", err)
+	}
+
+	pinned, err := c.Bytes.Upload(ctx, bytes.NewReader([]byte("pinned")), api.UploadOptions{BatchID: batchID, Pin: true})
+	if err != nil {
+		t.Fatalf("upload pinned: %v", err)
+	}
+
+	var chunks []swarm.Chunk
+	for i := range 3 {
+		ch, err := cac.New(fmt.Appendf(nil, "chunk-%d", i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := c.Chunks.Upload(ctx, ch.Data(), api.UploadOptions{BatchID: batchID}); err != nil {
+			t.Fatalf("upload chunk %d: %v", i, err)
+		}
+		chunks = append(chunks, ch)
+	}
+
+	if !s.HasChunk(pinned.Reference) {
+		t.Fatal("pinned chunk is evicted")
+	}
+	if s.HasChunk(chunks[0].Address()) {
+		t.Fatal("oldest unpinned chunk is not evicted")
+	}
+	for _, ch := range chunks[1:] {
+		if !s.HasChunk(ch.Address()) {
+			t.Fatalf("chunk %s is evicted", ch.Address())
+		}
+	}
+}
+
+// network stores pushed chunks on the other node
+type network struct {
+	other *beetest.Server
+}
+
+func (n network) Peers() []beetest.Peer {
+	return []beetest.Peer{{Overlay: n.other.Overlay(), LightNode: true}}
+}
+
+func (n network) Push(ch swarm.Chunk) {
+	n.other.Put(ch)
+}
+
+func (n network) Retrieve(addr swarm.Address) ([]byte, bool) {
+	return n.other.Chunk(addr)
+}
+
+func TestNetwork(t *testing.T) {
+	other, _ := beetest.New(t, beetest.WithOverlay(swarm.MustParseHexAddress("0a1b")))
+	s, c := beetest.New(t, beetest.WithNetwork(network{other: other}))
+	ctx := context.Background()
+
+	batchID, err := c.Postage.CreatePostageBatch(ctx, 1000, 17, "network")
+	if err != nil {
+		t.Fatalf("create batch: %v", err)
+	}
+
+	ch, err := cac.New([]byte("pushed"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Chunks.Upload(ctx, ch.Data(), api.UploadOptions{BatchID: batchID}); err != nil {
+		t.Fatalf("upload: %v", err)
+	}
+	if s.HasChunk(ch.Address()) || !other.HasChunk(ch.Address()) {
+		t.Fatal("chunk is not pushed to the other node")
+	}
+
+	r, err := c.Chunks.Download(ctx, ch.Address(), "", nil)
+	if err != nil {
+		t.Fatalf("download: %v", err)
+	}
+	defer r.Close()
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, ch.Data()) {
+		t.Fatalf("data: got %x, want %x", data, ch.Data())
+	}
+
+	topology, err := c.Node.Topology(ctx)
+	if err != nil {
+		t.Fatalf("topology: %v", err)
+	}
+	if topology.Connected != 0 || topology.LightNodes.Connected != 1 {
+		t.Fatalf("topology: got %d connected and %d light nodes, want 0 and 1", topology.Connected, topology.LightNodes.Connected)
+	}
+}
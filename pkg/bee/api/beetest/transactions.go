@@ -0,0 +1,99 @@
+package beetest
+
+import (
+	"cmp"
+	"net/http"
+	"slices"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethersphere/beekeeper/pkg/bee/api"
+)
+
+// AddTransaction adds a pending transaction to the node. Transactions of
+// other endpoints are confirmed immediately and are never pending.
+func (s *Server) AddTransaction(tx api.TransactionInfo) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.transactions[tx.TransactionHash] = &tx
+}
+
+// PendingTransactions returns pending transactions of the node ordered by
+// nonce
+func (s *Server) PendingTransactions() []api.TransactionInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.pendingTransactions()
+}
+
+// Resent returns how many times the pending transaction with the hash was
+// resent
+func (s *Server) Resent(hash common.Hash) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.resent[hash]
+}
+
+func (s *Server) pendingTransactions() []api.TransactionInfo {
+	txs := make([]api.TransactionInfo, 0, len(s.transactions))
+	for _, tx := range s.transactions {
+		txs = append(txs, *tx)
+	}
+	slices.SortFunc(txs, func(a, b api.TransactionInfo) int { return cmp.Compare(a.Nonce, b.Nonce) })
+	return txs
+}
+
+func (s *Server) transactionsHandler(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	jsonResponse(w, http.StatusOK, struct {
+		PendingTransactions []api.TransactionInfo `json:"pendingTransactions"`
+	}{PendingTransactions: s.pendingTransactions()})
+}
+
+func (s *Server) transaction(w http.ResponseWriter, r *http.Request) {
+	hash := common.HexToHash(r.PathValue("hash"))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx, ok := s.transactions[hash]
+	if !ok {
+		jsonError(w, http.StatusNotFound, "transaction not found")
+		return
+	}
+	jsonResponse(w, http.StatusOK, tx)
+}
+
+func (s *Server) resendTransaction(w http.ResponseWriter, r *http.Request) {
+	hash := common.HexToHash(r.PathValue("hash"))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.transactions[hash]; !ok {
+		jsonError(w, http.StatusNotFound, "transaction not found")
+		return
+	}
+	s.resent[hash]++
+
+	jsonResponse(w, http.StatusOK, api.TransactionHashResponse{TransactionHash: hash.Hex()})
+}
+
+// cancelTransaction removes the pending transaction, as if the cancellation
+// transaction that replaced it was confirmed
+func (s *Server) cancelTransaction(w http.ResponseWriter, r *http.Request) {
+	hash := common.HexToHash(r.PathValue("hash"))
+
+	s.mu.Lock()
+	_, ok := s.transactions[hash]
+	delete(s.transactions, hash)
+	s.mu.Unlock()
+
+	if !ok {
+		jsonError(w, http.StatusNotFound, "transaction not found")
+		return
+	}
+
+	jsonResponse(w, http.StatusOK, api.TransactionHashResponse{TransactionHash: s.transactionHash()})
+}
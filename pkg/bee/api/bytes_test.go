@@ -0,0 +1,108 @@
+package api_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/ethersphere/bee/v2/pkg/swarm"
+	"github.com/ethersphere/beekeeper/pkg/bee/api"
+	"github.com/ethersphere/beekeeper/pkg/bee/api/beetest"
+)
+
+func TestBytes(t *testing.T) {
+	s, c := beetest.New(t)
+	ctx := context.Background()
+
+	batchID, err := c.Postage.CreatePostageBatch(ctx, 1000, 17, "bytes")
+	if err != nil {
+		t.Fatalf("create batch: %v", err)
+	}
+
+	data := bytes.Repeat([]byte("swarm"), 2*swarm.ChunkSize)
+	resp, err := c.Bytes.Upload(ctx, bytes.NewReader(data), api.UploadOptions{BatchID: batchID, Pin: true})
+	if err != nil {
+		t.Fatalf("upload: %v", err)
+	}
+	if !s.HasChunk(resp.Reference) {
+		t.Fatal("root chunk is not stored")
+	}
+	if !s.Pinned(resp.Reference) {
+		t.Fatal("reference is not pinned")
+	}
+
+	r, err := c.Bytes.Download(ctx, resp.Reference, nil)
+	if err != nil {
+		t.Fatalf("download: %v", err)
+	}
+	defer r.Close()
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Fatal("downloaded data does not match")
+	}
+
+	if _, err := c.Bytes.Upload(ctx, bytes.NewReader(data), api.UploadOptions{BatchID: "unknown"}); !api.IsHTTPStatusErrorCode(err, http.StatusNotFound) {
+		t.Fatalf("upload with unknown batch: got error %v, want status %d", err, http.StatusNotFound)
+	}
+}
+
+func TestDirs(t *testing.T) {
+	s, c := beetest.New(t)
+	ctx := context.Background()
+
+	batchID, err := c.Postage.CreatePostageBatch(ctx, 1000, 17, "dirs")
+	if err != nil {
+		t.Fatalf("create batch: %v", err)
+	}
+
+	files := map[string]string{
+		"index.html":   "index",
+		"img/logo.svg": "logo",
+	}
+	var archive bytes.Buffer
+	tw := tar.NewWriter(&archive)
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o600, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := c.Dirs.Upload(ctx, bytes.NewReader(archive.Bytes()), int64(archive.Len()), api.UploadOptions{BatchID: batchID, IndexDocument: "index.html", Pin: true})
+	if err != nil {
+		t.Fatalf("upload: %v", err)
+	}
+	if !s.Pinned(resp.Reference) {
+		t.Fatal("collection is not pinned")
+	}
+
+	for path, want := range map[string]string{"": "index", "img/logo.svg": "logo"} {
+		r, err := c.Dirs.Download(ctx, resp.Reference, path)
+		if err != nil {
+			t.Fatalf("download %q: %v", path, err)
+		}
+		got, err := io.ReadAll(r)
+		r.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != want {
+			t.Fatalf("download %q: got %q, want %q", path, got, want)
+		}
+	}
+
+	if _, err := c.Dirs.Download(ctx, resp.Reference, "missing"); !api.IsHTTPStatusErrorCode(err, http.StatusNotFound) {
+		t.Fatalf("download missing: got error %v, want status %d", err, http.StatusNotFound)
+	}
+}
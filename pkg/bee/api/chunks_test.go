@@ -0,0 +1,70 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/ethersphere/bee/v2/pkg/cac"
+	"github.com/ethersphere/beekeeper/pkg/bee/api"
+	"github.com/ethersphere/beekeeper/pkg/bee/api/beetest"
+)
+
+func TestChunks(t *testing.T) {
+	s, c := beetest.New(t)
+	ctx := context.Background()
+
+	batchID, err := c.Postage.CreatePostageBatch(ctx, 1000, 17, "chunks")
+	if err != nil {
+		t.Fatalf("create batch: %v", err)
+	}
+
+	ch, err := cac.New([]byte("chunk"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if found, err := c.Node.HasChunk(ctx, ch.Address()); err != nil || found {
+		t.Fatalf("has chunk before upload: found %v, err %v", found, err)
+	}
+
+	resp, err := c.Chunks.Upload(ctx, ch.Data(), api.UploadOptions{BatchID: batchID, Direct: true})
+	if err != nil {
+		t.Fatalf("upload: %v", err)
+	}
+	if !resp.Reference.Equal(ch.Address()) {
+		t.Fatalf("reference: got %s, want %s", resp.Reference, ch.Address())
+	}
+
+	if found, err := c.Node.HasChunk(ctx, ch.Address()); err != nil || !found {
+		t.Fatalf("has chunk after upload: found %v, err %v", found, err)
+	}
+
+	r, err := c.Chunks.Download(ctx, ch.Address(), "", nil)
+	if err != nil {
+		t.Fatalf("download: %v", err)
+	}
+	defer r.Close()
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, ch.Data()) {
+		t.Fatal("downloaded data does not match")
+	}
+
+	requests := s.Requests()
+	upload := requests[len(requests)-3]
+	if got := upload.Header.Get("Swarm-Deferred-Upload"); got != "false" {
+		t.Fatalf("deferred upload header: got %q, want false", got)
+	}
+	if got := upload.Header.Get("Swarm-Postage-Batch-Id"); got != batchID {
+		t.Fatalf("batch header: got %q, want %s", got, batchID)
+	}
+
+	if _, err := c.Chunks.Upload(ctx, []byte{1}, api.UploadOptions{BatchID: batchID}); !api.IsHTTPStatusErrorCode(err, http.StatusBadRequest) {
+		t.Fatalf("upload invalid chunk: got error %v, want status %d", err, http.StatusBadRequest)
+	}
+}
@@ -28,6 +28,9 @@ func (s *DirsService) Upload(ctx context.Context, data io.Reader, size int64, o
 	header.Set("Content-Type", "application/x-tar")
 	header.Set("Content-Length", strconv.FormatInt(size, 10))
 	header.Set("swarm-collection", "True")
+	if o.Pin {
+		header.Set(swarmPinHeader, "true")
+	}
 	header.Set(postageStampBatchHeader, o.BatchID)
 
 	if o.IndexDocument != "" {
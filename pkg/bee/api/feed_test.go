@@ -0,0 +1,79 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/ethersphere/bee/v2/pkg/crypto"
+	"github.com/ethersphere/beekeeper/pkg/bee/api"
+	"github.com/ethersphere/beekeeper/pkg/bee/api/beetest"
+)
+
+func TestFeed(t *testing.T) {
+	s, c := beetest.New(t)
+	ctx := context.Background()
+
+	batchID, err := c.Postage.CreatePostageBatch(ctx, 1000, 17, "feed")
+	if err != nil {
+		t.Fatalf("create batch: %v", err)
+	}
+
+	key, err := crypto.GenerateSecp256k1Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := crypto.NewDefaultSigner(key)
+	topic := []byte("topic")
+
+	manifest, err := c.Feed.CreateRootManifest(ctx, signer, topic, api.UploadOptions{BatchID: batchID})
+	if err != nil {
+		t.Fatalf("create manifest: %v", err)
+	}
+	if manifest.Topic != hex.EncodeToString(topic) {
+		t.Fatalf("topic: got %s, want %s", manifest.Topic, hex.EncodeToString(topic))
+	}
+
+	var last []byte
+	for i := range 3 {
+		last = fmt.Appendf(nil, "update-%d", i)
+		ref, err := c.Bytes.Upload(ctx, bytes.NewReader(last), api.UploadOptions{BatchID: batchID})
+		if err != nil {
+			t.Fatalf("upload: %v", err)
+		}
+		socResp, err := c.Feed.UpdateWithReference(ctx, signer, topic, uint64(i), ref.Reference, api.UploadOptions{BatchID: batchID})
+		if err != nil {
+			t.Fatalf("update %d: %v", i, err)
+		}
+		if !s.HasChunk(socResp.Reference) {
+			t.Fatalf("update %d: soc chunk is not stored", i)
+		}
+	}
+
+	update, err := c.Feed.FindUpdate(ctx, signer, topic, nil)
+	if err != nil {
+		t.Fatalf("find update: %v", err)
+	}
+	if update.Index != 2 || update.NextIndex != 3 {
+		t.Fatalf("index: got %d and next %d, want 2 and 3", update.Index, update.NextIndex)
+	}
+	if !bytes.Equal(update.Data, last) {
+		t.Fatalf("data: got %q, want %q", update.Data, last)
+	}
+
+	r, err := c.Files.Download(ctx, manifest.Reference, nil)
+	if err != nil {
+		t.Fatalf("download manifest: %v", err)
+	}
+	defer r.Close()
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, last) {
+		t.Fatalf("manifest data: got %q, want %q", data, last)
+	}
+}
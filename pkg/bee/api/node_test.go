@@ -0,0 +1,62 @@
+package api_test
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"testing"
+
+	"github.com/ethersphere/bee/v2/pkg/swarm"
+	"github.com/ethersphere/beekeeper/pkg/bee/api"
+	"github.com/ethersphere/beekeeper/pkg/bee/api/beetest"
+)
+
+func TestNode(t *testing.T) {
+	overlay := swarm.MustParseHexAddress("f000000000000000000000000000000000000000000000000000000000000000")
+	peers := []swarm.Address{
+		swarm.MustParseHexAddress("0000000000000000000000000000000000000000000000000000000000000000"),
+		swarm.MustParseHexAddress("c000000000000000000000000000000000000000000000000000000000000000"),
+	}
+	_, c := beetest.New(t, beetest.WithOverlay(overlay), beetest.WithPeers(peers...), beetest.WithWallet(big.NewInt(10), big.NewInt(10)))
+	ctx := context.Background()
+
+	addresses, err := c.Node.Addresses(ctx)
+	if err != nil {
+		t.Fatalf("addresses: %v", err)
+	}
+	if !addresses.Overlay.Equal(overlay) {
+		t.Fatalf("overlay: got %s, want %s", addresses.Overlay, overlay)
+	}
+
+	topology, err := c.Node.Topology(ctx)
+	if err != nil {
+		t.Fatalf("topology: %v", err)
+	}
+	if topology.Connected != 2 || topology.Bins["bin_0"].Connected != 1 || topology.Bins["bin_2"].Connected != 1 {
+		t.Fatalf("topology: got %d connected, bins %+v", topology.Connected, topology.Bins)
+	}
+
+	if _, err := c.PingPong.Ping(ctx, peers[1]); err != nil {
+		t.Fatalf("ping: %v", err)
+	}
+	if err := c.Node.Disconnect(ctx, peers[1]); err != nil {
+		t.Fatalf("disconnect: %v", err)
+	}
+	if _, err := c.PingPong.Ping(ctx, peers[1]); !api.IsHTTPStatusErrorCode(err, http.StatusNotFound) {
+		t.Fatalf("ping disconnected: got error %v, want status %d", err, http.StatusNotFound)
+	}
+
+	if _, err := c.Node.Withdraw(ctx, "NativeToken", "0x1000000000000000000000000000000000000000", 4); err != nil {
+		t.Fatalf("withdraw: %v", err)
+	}
+	if _, err := c.Node.Withdraw(ctx, "NativeToken", "0x0000000000000000000000000000000000000000", 4); !api.IsHTTPStatusErrorCode(err, http.StatusBadRequest) {
+		t.Fatalf("withdraw to zero address: got error %v, want status %d", err, http.StatusBadRequest)
+	}
+	wallet, err := c.Node.Wallet(ctx)
+	if err != nil {
+		t.Fatalf("wallet: %v", err)
+	}
+	if wallet.NativeToken.Int64() != 6 || wallet.BZZ.Int64() != 10 {
+		t.Fatalf("wallet: got native %s, bzz %s", wallet.NativeToken, wallet.BZZ)
+	}
+}
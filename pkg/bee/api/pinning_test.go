@@ -0,0 +1,61 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/ethersphere/beekeeper/pkg/bee/api"
+	"github.com/ethersphere/beekeeper/pkg/bee/api/beetest"
+)
+
+func TestPinning(t *testing.T) {
+	_, c := beetest.New(t)
+	ctx := context.Background()
+
+	batchID, err := c.Postage.CreatePostageBatch(ctx, 1000, 17, "pinning")
+	if err != nil {
+		t.Fatalf("create batch: %v", err)
+	}
+	tag, err := c.Tags.CreateTag(ctx)
+	if err != nil {
+		t.Fatalf("create tag: %v", err)
+	}
+
+	resp, err := c.Bytes.Upload(ctx, bytes.NewReader([]byte("pinned")), api.UploadOptions{BatchID: batchID, Tag: tag.Uid})
+	if err != nil {
+		t.Fatalf("upload: %v", err)
+	}
+	if err := c.Tags.WaitSync(ctx, tag.Uid); err != nil {
+		t.Fatalf("wait sync: %v", err)
+	}
+
+	if err := c.Pinning.PinRootHash(ctx, resp.Reference); err != nil {
+		t.Fatalf("pin: %v", err)
+	}
+	ref, err := c.Pinning.GetPinnedRootHash(ctx, resp.Reference)
+	if err != nil {
+		t.Fatalf("pinned root hash: %v", err)
+	}
+	if !ref.Equal(resp.Reference) {
+		t.Fatalf("pinned root hash: got %s, want %s", ref, resp.Reference)
+	}
+	pins, err := c.Pinning.GetPins(ctx)
+	if err != nil {
+		t.Fatalf("pins: %v", err)
+	}
+	if len(pins) != 1 {
+		t.Fatalf("pins: got %d, want 1", len(pins))
+	}
+
+	if ok, err := c.Stewardship.IsRetrievable(ctx, resp.Reference); err != nil || !ok {
+		t.Fatalf("retrievable: got %v, err %v", ok, err)
+	}
+
+	if err := c.Pinning.UnpinRootHash(ctx, resp.Reference); err != nil {
+		t.Fatalf("unpin: %v", err)
+	}
+	if _, err := c.Pinning.GetPinnedRootHash(ctx, resp.Reference); err == nil {
+		t.Fatal("unpinned root hash is pinned")
+	}
+}
@@ -0,0 +1,67 @@
+package api_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/ethersphere/beekeeper/pkg/bee/api"
+	"github.com/ethersphere/beekeeper/pkg/bee/api/beetest"
+)
+
+func TestPostage(t *testing.T) {
+	s, c := beetest.New(t)
+	ctx := context.Background()
+
+	batchID, err := c.Postage.CreatePostageBatch(ctx, 24000, 17, "postage")
+	if err != nil {
+		t.Fatalf("create batch: %v", err)
+	}
+
+	batch, err := c.Postage.PostageStamp(ctx, batchID)
+	if err != nil {
+		t.Fatalf("stamp: %v", err)
+	}
+	if batch.Label != "postage" || batch.Depth != 17 || batch.Amount.Int64() != 24000 || batch.BatchTTL != beetest.DefaultBlockTime {
+		t.Fatalf("batch: got label %s, depth %d, amount %s, ttl %d", batch.Label, batch.Depth, batch.Amount, batch.BatchTTL)
+	}
+	if batch.ImmutableFlag {
+		t.Fatal("batch is immutable")
+	}
+
+	if err := c.Postage.TopUpPostageBatch(ctx, batchID, 24000, "10"); err != nil {
+		t.Fatalf("top up: %v", err)
+	}
+	if got := s.Requests()[len(s.Requests())-1].Header.Get("Gas-Price"); got != "10" {
+		t.Fatalf("gas price header: got %q, want 10", got)
+	}
+	if err := c.Postage.DilutePostageBatch(ctx, batchID, 18, ""); err != nil {
+		t.Fatalf("dilute: %v", err)
+	}
+	if err := c.Postage.DilutePostageBatch(ctx, batchID, 17, ""); !api.IsHTTPStatusErrorCode(err, http.StatusBadRequest) {
+		t.Fatalf("dilute to lower depth: got error %v, want status %d", err, http.StatusBadRequest)
+	}
+
+	batches, err := c.Postage.PostageBatches(ctx)
+	if err != nil {
+		t.Fatalf("batches: %v", err)
+	}
+	if len(batches) != 1 {
+		t.Fatalf("batches: got %d, want 1", len(batches))
+	}
+	if b := batches[0]; b.Depth != 18 || b.Amount.Int64() != 48000 || b.BatchTTL != beetest.DefaultBlockTime {
+		t.Fatalf("batch: got depth %d, amount %s, ttl %d", b.Depth, b.Amount, b.BatchTTL)
+	}
+
+	state, err := c.Postage.GetChainState(ctx)
+	if err != nil {
+		t.Fatalf("chain state: %v", err)
+	}
+	if state.CurrentPrice.Int64() != beetest.DefaultCurrentPrice {
+		t.Fatalf("current price: got %s, want %d", state.CurrentPrice, beetest.DefaultCurrentPrice)
+	}
+
+	if _, err := c.Postage.PostageStamp(ctx, "unknown"); !api.IsHTTPStatusErrorCode(err, http.StatusNotFound) {
+		t.Fatalf("unknown batch: got error %v, want status %d", err, http.StatusNotFound)
+	}
+}
@@ -0,0 +1,47 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/ethersphere/bee/v2/pkg/swarm"
+	"github.com/ethersphere/beekeeper/pkg/bee/api"
+	"github.com/ethersphere/beekeeper/pkg/bee/api/beetest"
+)
+
+func TestSendMessage(t *testing.T) {
+	s, c := beetest.New(t)
+	ctx := context.Background()
+
+	batchID, err := c.Postage.CreatePostageBatch(ctx, 1000, 17, "pss")
+	if err != nil {
+		t.Fatalf("create batch: %v", err)
+	}
+
+	target := swarm.MustParseHexAddress("a0b1000000000000000000000000000000000000000000000000000000000000")
+	if err := c.PSS.SendMessage(ctx, target, "03abcd", "topic", 4, bytes.NewReader([]byte("message")), batchID); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+
+	requests := s.Requests()
+	r := requests[len(requests)-1]
+	if r.Method != http.MethodPost || r.Path != "/v1/pss/send/topic/a0b1" {
+		t.Fatalf("request: got %s %s, want POST /v1/pss/send/topic/a0b1", r.Method, r.Path)
+	}
+	if got := r.Query.Get("recipient"); got != "03abcd" {
+		t.Fatalf("recipient: got %s, want 03abcd", got)
+	}
+	if got := r.Header.Get("Swarm-Postage-Batch-Id"); got != batchID {
+		t.Fatalf("batch: got %s, want %s", got, batchID)
+	}
+	if !bytes.Equal(r.Body, []byte("message")) {
+		t.Fatalf("body: got %q, want %q", r.Body, "message")
+	}
+
+	err = c.PSS.SendMessage(ctx, target, "03abcd", "topic", 4, bytes.NewReader([]byte("message")), "unknown")
+	if !api.IsHTTPStatusErrorCode(err, http.StatusNotFound) {
+		t.Fatalf("send with unknown batch: got error %v, want status %d", err, http.StatusNotFound)
+	}
+}
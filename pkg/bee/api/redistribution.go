@@ -0,0 +1,56 @@
+package api
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+
+	"github.com/ethersphere/bee/v2/pkg/swarm"
+	"github.com/ethersphere/beekeeper/pkg/bigint"
+)
+
+// RedistributionService represents Bee's storage incentives redistribution
+// service
+type RedistributionService service
+
+// RedistributionState represents the state of the node in the storage
+// incentives redistribution game
+type RedistributionState struct {
+	MinimumGasFunds           *bigint.BigInt `json:"minimumGasFunds"`
+	HasSufficientFunds        bool           `json:"hasSufficientFunds"`
+	IsFrozen                  bool           `json:"isFrozen"`
+	IsFullySynced             bool           `json:"isFullySynced"`
+	Phase                     string         `json:"phase"`
+	Round                     uint64         `json:"round"`
+	LastWonRound              uint64         `json:"lastWonRound"`
+	LastPlayedRound           uint64         `json:"lastPlayedRound"`
+	LastFrozenRound           uint64         `json:"lastFrozenRound"`
+	LastSelectedRound         uint64         `json:"lastSelectedRound"`
+	LastSampleDurationSeconds float64        `json:"lastSampleDurationSeconds"`
+	Block                     uint64         `json:"block"`
+	Reward                    *bigint.BigInt `json:"reward"`
+	Fees                      *bigint.BigInt `json:"fees"`
+	IsHealthy                 bool           `json:"isHealthy"`
+}
+
+// RCHashResponse represents the reserve commitment hash of a sample of the
+// node's reserve
+type RCHashResponse struct {
+	Hash            swarm.Address `json:"hash"`
+	DurationSeconds float64       `json:"durationSeconds"`
+}
+
+// State returns the redistribution state of the node
+func (r *RedistributionService) State(ctx context.Context) (resp RedistributionState, err error) {
+	err = r.client.requestJSON(ctx, http.MethodGet, "/redistributionstate", nil, &resp)
+	return resp, err
+}
+
+// RCHash samples the node's reserve at the depth with the anchors and returns
+// the reserve commitment hash of the sample
+func (r *RedistributionService) RCHash(ctx context.Context, depth uint8, anchor1, anchor2 []byte) (resp RCHashResponse, err error) {
+	path := fmt.Sprintf("/rchash/%d/%s/%s", depth, hex.EncodeToString(anchor1), hex.EncodeToString(anchor2))
+	err = r.client.requestJSON(ctx, http.MethodGet, path, nil, &resp)
+	return resp, err
+}
@@ -0,0 +1,91 @@
+package api_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethersphere/bee/v2/pkg/cac"
+	"github.com/ethersphere/beekeeper/pkg/bee/api"
+	"github.com/ethersphere/beekeeper/pkg/bee/api/beetest"
+	"github.com/ethersphere/beekeeper/pkg/bigint"
+)
+
+func TestRedistributionState(t *testing.T) {
+	s, c := beetest.New(t)
+	ctx := context.Background()
+
+	state, err := c.Redistribution.State(ctx)
+	if err != nil {
+		t.Fatalf("state: %v", err)
+	}
+	if !state.IsHealthy || state.IsFrozen || state.Reward.Sign() != 0 {
+		t.Fatalf("got state %+v, want healthy node without reward", state)
+	}
+
+	s.SetRedistributionState(api.RedistributionState{
+		Phase:           "claim",
+		Round:           10,
+		LastPlayedRound: 9,
+		LastWonRound:    9,
+		Reward:          bigint.Wrap(big.NewInt(1000)),
+	})
+	state, err = c.Redistribution.State(ctx)
+	if err != nil {
+		t.Fatalf("state: %v", err)
+	}
+	if state.Phase != "claim" || state.Round != 10 || state.LastWonRound != 9 || state.Reward.Int64() != 1000 {
+		t.Fatalf("got state %+v", state)
+	}
+}
+
+func TestRCHash(t *testing.T) {
+	s1, c1 := beetest.New(t)
+	_, c2 := beetest.New(t)
+	ctx := context.Background()
+
+	anchor := []byte{1, 2, 3, 4}
+
+	h1, err := c1.Redistribution.RCHash(ctx, 0, anchor, anchor)
+	if err != nil {
+		t.Fatalf("rchash: %v", err)
+	}
+	h2, err := c2.Redistribution.RCHash(ctx, 0, anchor, anchor)
+	if err != nil {
+		t.Fatalf("rchash: %v", err)
+	}
+	if !h1.Hash.Equal(h2.Hash) {
+		t.Fatalf("got different hashes %s and %s of empty reserves", h1.Hash, h2.Hash)
+	}
+
+	other, err := c1.Redistribution.RCHash(ctx, 0, []byte{5}, anchor)
+	if err != nil {
+		t.Fatalf("rchash: %v", err)
+	}
+	if other.Hash.Equal(h1.Hash) {
+		t.Fatal("got the same hash with a different anchor")
+	}
+
+	batchID, err := c1.Postage.CreatePostageBatch(ctx, 1000, 17, "rchash")
+	if err != nil {
+		t.Fatalf("create batch: %v", err)
+	}
+	ch, err := cac.New([]byte("reserve"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c1.Chunks.Upload(ctx, ch.Data(), api.UploadOptions{BatchID: batchID, Direct: true}); err != nil {
+		t.Fatalf("upload: %v", err)
+	}
+	if !s1.HasChunk(ch.Address()) {
+		t.Fatal("chunk is not stored")
+	}
+
+	h1, err = c1.Redistribution.RCHash(ctx, 0, anchor, anchor)
+	if err != nil {
+		t.Fatalf("rchash: %v", err)
+	}
+	if h1.Hash.Equal(h2.Hash) {
+		t.Fatal("got the same hash of different reserves")
+	}
+}
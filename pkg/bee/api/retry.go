@@ -0,0 +1,145 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// DefaultRetryStatusCodes are status codes of responses that are retried if
+// the retry policy does not set them
+var DefaultRetryStatusCodes = []int{
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// DefaultRetryPolicy is the retry policy of clients of orchestrated nodes
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    4,
+	InitialBackoff: 500 * time.Millisecond,
+	MaxBackoff:     10 * time.Second,
+}
+
+// RetryPolicy represents the policy of retrying failed requests. Requests
+// with idempotent methods are retried on any connection error and on the
+// retried status codes. Other requests are retried only on the retried status
+// codes and when the connection is refused or reset. Requests with bodies
+// that can not be read again are never retried.
+type RetryPolicy struct {
+	MaxAttempts    int           // maximum number of attempts of a request, requests are not retried if less than 2
+	InitialBackoff time.Duration // backoff before the first retry, doubled for every next one
+	MaxBackoff     time.Duration // maximum backoff, also limits waiting for the Retry-After header
+	StatusCodes    []int         // status codes of retried responses, DefaultRetryStatusCodes if nil
+}
+
+// WithRetryPolicy sets the policy of retrying failed requests
+func WithRetryPolicy(p RetryPolicy) ClientOption {
+	return func(c *Client) {
+		if p.StatusCodes == nil {
+			p.StatusCodes = DefaultRetryStatusCodes
+		}
+		c.retryPolicy = p
+	}
+}
+
+// do sends the request, retrying it according to the retry policy
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	p := c.retryPolicy
+	if p.MaxAttempts < 2 || (req.Body != nil && req.Body != http.NoBody && req.GetBody == nil) {
+		return c.httpClient.Do(req)
+	}
+
+	for attempt := 1; ; attempt++ {
+		r, err := c.httpClient.Do(req)
+		if attempt >= p.MaxAttempts || !p.retry(req, r, err) {
+			return r, err
+		}
+
+		wait := p.backoff(attempt)
+		if r != nil {
+			if d, ok := retryAfter(r.Header.Get("Retry-After")); ok {
+				wait = min(d, p.MaxBackoff)
+			}
+			drain(r.Body)
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(wait):
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+
+		if c.retried != nil {
+			c.retried(req)
+		}
+	}
+}
+
+// retry returns whether the request with the response or error should be
+// retried
+func (p RetryPolicy) retry(req *http.Request, r *http.Response, err error) bool {
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return false
+		}
+		if errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) {
+			return true
+		}
+		return idempotent(req.Method)
+	}
+
+	return slices.Contains(p.StatusCodes, r.StatusCode)
+}
+
+// backoff returns exponential backoff before the retry after the attempt,
+// with jitter of up to a half of it
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.InitialBackoff
+	for i := 1; i < attempt && d < p.MaxBackoff; i++ {
+		d *= 2
+	}
+	if p.MaxBackoff > 0 {
+		d = min(d, p.MaxBackoff)
+	}
+	if d <= 0 {
+		return 0
+	}
+
+	return d/2 + rand.N(d/2+1)
+}
+
+// retryAfter parses the Retry-After header value in seconds or as HTTP date
+func retryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if s, err := strconv.Atoi(v); err == nil && s >= 0 {
+		return time.Duration(s) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(time.Until(t), 0), true
+	}
+	return 0, false
+}
+
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
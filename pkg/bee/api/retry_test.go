@@ -0,0 +1,192 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/beekeeper/pkg/bee/api"
+	"github.com/ethersphere/beekeeper/pkg/bee/api/beetest"
+	"github.com/ethersphere/beekeeper/pkg/httpx"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+var testRetryPolicy = api.RetryPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: time.Millisecond,
+	MaxBackoff:     10 * time.Millisecond,
+}
+
+func newRetryClient(t *testing.T, u *url.URL, p api.RetryPolicy) (*api.Client, *httpx.Metrics) {
+	t.Helper()
+
+	m := httpx.NewMetrics()
+	c, err := api.NewClient(u, nil, api.WithRetryPolicy(p), api.WithMetrics(m, "bee-0", "bee"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c, m
+}
+
+func retries(m *httpx.Metrics, route, method string) float64 {
+	return testutil.ToFloat64(m.RetryCount.WithLabelValues(route, method, "bee-0", "bee"))
+}
+
+func TestRetryStatusCodes(t *testing.T) {
+	s, _ := beetest.New(t)
+	c, m := newRetryClient(t, s.URL(), testRetryPolicy)
+	ctx := context.Background()
+
+	s.Fail("GET /addresses", http.StatusServiceUnavailable, 2)
+	if _, err := c.Node.Addresses(ctx); err != nil {
+		t.Fatalf("addresses: %v", err)
+	}
+	if got := s.RequestCount(http.MethodGet, "/addresses"); got != 3 {
+		t.Errorf("got %d requests, want 3", got)
+	}
+	if got := retries(m, "/addresses", http.MethodGet); got != 2 {
+		t.Errorf("got %v retries, want 2", got)
+	}
+
+	s.Fail("GET /peers", http.StatusBadGateway, -1)
+	if _, err := c.Node.Peers(ctx); !api.IsHTTPStatusErrorCode(err, http.StatusBadGateway) {
+		t.Fatalf("peers: got error %v, want status %d", err, http.StatusBadGateway)
+	}
+	if got := s.RequestCount(http.MethodGet, "/peers"); got != testRetryPolicy.MaxAttempts {
+		t.Errorf("got %d requests, want %d", got, testRetryPolicy.MaxAttempts)
+	}
+
+	s.Fail("GET /health", http.StatusInternalServerError, 1)
+	if _, err := c.Node.Health(ctx); !api.IsHTTPStatusErrorCode(err, http.StatusInternalServerError) {
+		t.Fatalf("health: got error %v, want status %d", err, http.StatusInternalServerError)
+	}
+	if got := s.RequestCount(http.MethodGet, "/health"); got != 1 {
+		t.Errorf("got %d requests of status code that is not retried, want 1", got)
+	}
+}
+
+func TestRetryWithoutPolicy(t *testing.T) {
+	s, _ := beetest.New(t)
+	c, m := newRetryClient(t, s.URL(), api.RetryPolicy{})
+
+	s.Fail("GET /addresses", http.StatusServiceUnavailable, 1)
+	if _, err := c.Node.Addresses(context.Background()); !api.IsHTTPStatusErrorCode(err, http.StatusServiceUnavailable) {
+		t.Fatalf("got error %v, want status %d", err, http.StatusServiceUnavailable)
+	}
+	if got := s.RequestCount(http.MethodGet, "/addresses"); got != 1 {
+		t.Errorf("got %d requests, want 1", got)
+	}
+	if got := testutil.CollectAndCount(m.RetryCount); got != 0 {
+		t.Errorf("got %d retried routes, want 0", got)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	s, _ := beetest.New(t)
+	// the backoff is longer than the test timeout, so only the Retry-After
+	// header can make the request succeed
+	c, _ := newRetryClient(t, s.URL(), api.RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Hour, MaxBackoff: time.Hour})
+
+	var calls atomic.Int32
+	s.Handle("GET /health", func(w http.ResponseWriter, _ *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"ok"}`))
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := c.Node.Health(ctx); err != nil {
+		t.Fatalf("health: %v", err)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("got %d requests, want 2", got)
+	}
+}
+
+func TestRetryBody(t *testing.T) {
+	s, _ := beetest.New(t)
+	c, m := newRetryClient(t, s.URL(), testRetryPolicy)
+	ctx := context.Background()
+
+	batchID, err := c.Postage.CreatePostageBatch(ctx, 1000, 17, "retry")
+	if err != nil {
+		t.Fatalf("create batch: %v", err)
+	}
+
+	data := []byte("swarm")
+	s.Fail("POST /v1/bytes", http.StatusServiceUnavailable, 1)
+	resp, err := c.Bytes.Upload(ctx, bytes.NewReader(data), api.UploadOptions{BatchID: batchID})
+	if err != nil {
+		t.Fatalf("upload: %v", err)
+	}
+	r, err := c.Bytes.Download(ctx, resp.Reference, nil)
+	if err != nil {
+		t.Fatalf("download: %v", err)
+	}
+	defer r.Close()
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Fatalf("got data %q, want %q", got, data)
+	}
+
+	// bodies that can not be read again are not retried
+	s.Fail("POST /v1/bytes", http.StatusServiceUnavailable, 1)
+	if _, err := c.Bytes.Upload(ctx, io.MultiReader(bytes.NewReader(data)), api.UploadOptions{BatchID: batchID}); !api.IsHTTPStatusErrorCode(err, http.StatusServiceUnavailable) {
+		t.Fatalf("upload: got error %v, want status %d", err, http.StatusServiceUnavailable)
+	}
+	if got := retries(m, "/bytes", http.MethodPost); got != 1 {
+		t.Errorf("got %v retries, want 1", got)
+	}
+}
+
+func TestRetryConnectionError(t *testing.T) {
+	var calls atomic.Int32
+	// the server closes connections without a response
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		conn.Close()
+	}))
+	defer s.Close()
+
+	u, err := url.Parse(s.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, _ := newRetryClient(t, u, testRetryPolicy)
+	ctx := context.Background()
+
+	if _, err := c.Node.Addresses(ctx); err == nil {
+		t.Fatal("expected error")
+	}
+	if got := calls.Load(); got != int32(testRetryPolicy.MaxAttempts) {
+		t.Errorf("got %d requests with idempotent method, want %d", got, testRetryPolicy.MaxAttempts)
+	}
+
+	calls.Store(0)
+	if _, err := c.Node.Connect(ctx, "/ip4/127.0.0.1/tcp/1634"); err == nil {
+		t.Fatal("expected error")
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("got %d requests with method that is not idempotent, want 1", got)
+	}
+}
@@ -0,0 +1,72 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"net/http"
+	"testing"
+
+	"github.com/ethersphere/bee/v2/pkg/cac"
+	"github.com/ethersphere/bee/v2/pkg/crypto"
+	"github.com/ethersphere/bee/v2/pkg/soc"
+	"github.com/ethersphere/bee/v2/pkg/swarm"
+	"github.com/ethersphere/beekeeper/pkg/bee/api"
+	"github.com/ethersphere/beekeeper/pkg/bee/api/beetest"
+)
+
+func TestUploadSOC(t *testing.T) {
+	s, c := beetest.New(t)
+	ctx := context.Background()
+
+	batchID, err := c.Postage.CreatePostageBatch(ctx, 1000, 17, "soc")
+	if err != nil {
+		t.Fatalf("create batch: %v", err)
+	}
+
+	key, err := crypto.GenerateSecp256k1Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := crypto.NewDefaultSigner(key)
+	owner, err := signer.EthereumAddress()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ch, err := cac.New([]byte("soc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := make([]byte, swarm.HashSize)
+	sch, err := soc.New(id, ch).Sign(signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig := sch.Data()[swarm.HashSize : swarm.HashSize+swarm.SocSignatureSize]
+
+	resp, err := c.SOC.UploadSOC(ctx, hex.EncodeToString(owner.Bytes()), hex.EncodeToString(id), hex.EncodeToString(sig), bytes.NewReader(ch.Data()), batchID)
+	if err != nil {
+		t.Fatalf("upload: %v", err)
+	}
+	if !resp.Reference.Equal(sch.Address()) {
+		t.Fatalf("reference: got %s, want %s", resp.Reference, sch.Address())
+	}
+	if !s.HasChunk(sch.Address()) {
+		t.Fatal("soc chunk is not stored")
+	}
+
+	other, err := cac.New([]byte("other"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = c.SOC.UploadSOC(ctx, hex.EncodeToString(owner.Bytes()), hex.EncodeToString(id), hex.EncodeToString(sig), bytes.NewReader(other.Data()), batchID)
+	if !api.IsHTTPStatusErrorCode(err, http.StatusUnauthorized) {
+		t.Fatalf("upload with invalid signature: got error %v, want status %d", err, http.StatusUnauthorized)
+	}
+
+	_, err = c.SOC.UploadSOC(ctx, hex.EncodeToString(owner.Bytes()), hex.EncodeToString(id), hex.EncodeToString(sig), bytes.NewReader(ch.Data()), "unknown")
+	if !api.IsHTTPStatusErrorCode(err, http.StatusNotFound) {
+		t.Fatalf("upload with unknown batch: got error %v, want status %d", err, http.StatusNotFound)
+	}
+}
@@ -0,0 +1,50 @@
+package api_test
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"testing"
+
+	"github.com/ethersphere/beekeeper/pkg/bee/api"
+	"github.com/ethersphere/beekeeper/pkg/bee/api/beetest"
+)
+
+func TestStake(t *testing.T) {
+	_, c := beetest.New(t, beetest.WithWallet(big.NewInt(100), big.NewInt(1)))
+	ctx := context.Background()
+
+	if _, err := c.Stake.DepositStake(ctx, big.NewInt(60)); err != nil {
+		t.Fatalf("deposit: %v", err)
+	}
+	if _, err := c.Stake.DepositStake(ctx, big.NewInt(60)); !api.IsHTTPStatusErrorCode(err, http.StatusBadRequest) {
+		t.Fatalf("deposit over balance: got error %v, want status %d", err, http.StatusBadRequest)
+	}
+
+	staked, err := c.Stake.GetStakedAmount(ctx)
+	if err != nil {
+		t.Fatalf("staked amount: %v", err)
+	}
+	if staked.Int64() != 60 {
+		t.Fatalf("staked amount: got %s, want 60", staked)
+	}
+
+	wallet, err := c.Node.Wallet(ctx)
+	if err != nil {
+		t.Fatalf("wallet: %v", err)
+	}
+	if wallet.BZZ.Int64() != 40 {
+		t.Fatalf("bzz balance: got %s, want 40", wallet.BZZ)
+	}
+
+	if _, err := c.Stake.MigrateStake(ctx); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	withdrawable, err := c.Stake.GetWithdrawableStake(ctx)
+	if err != nil {
+		t.Fatalf("withdrawable: %v", err)
+	}
+	if withdrawable.Int64() != 60 {
+		t.Fatalf("withdrawable: got %s, want 60", withdrawable)
+	}
+}
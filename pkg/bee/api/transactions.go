@@ -0,0 +1,76 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethersphere/beekeeper/pkg/bigint"
+)
+
+// TransactionsService represents Bee's service of pending blockchain
+// transactions sent by the node
+type TransactionsService service
+
+// TransactionInfo represents a transaction sent by the node
+type TransactionInfo struct {
+	TransactionHash common.Hash    `json:"transactionHash"`
+	To              string         `json:"to"`
+	Nonce           uint64         `json:"nonce"`
+	GasPrice        *bigint.BigInt `json:"gasPrice"`
+	GasLimit        uint64         `json:"gasLimit"`
+	GasTipBoost     int            `json:"gasTipBoost"`
+	GasTipCap       *bigint.BigInt `json:"gasTipCap"`
+	GasFeeCap       *bigint.BigInt `json:"gasFeeCap"`
+	Data            string         `json:"data"`
+	Created         time.Time      `json:"created"`
+	Description     string         `json:"description"`
+	Value           *bigint.BigInt `json:"value"`
+}
+
+// transactionsBasePath is the transactions API base path for http requests.
+const transactionsBasePath = "/transactions"
+
+func transactionsPath(hash common.Hash) string { return transactionsBasePath + "/" + hash.Hex() }
+
+// Pending returns transactions sent by the node that are not yet confirmed
+func (t *TransactionsService) Pending(ctx context.Context) ([]TransactionInfo, error) {
+	resp := struct {
+		PendingTransactions []TransactionInfo `json:"pendingTransactions"`
+	}{}
+	if err := t.client.requestJSON(ctx, http.MethodGet, transactionsBasePath, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.PendingTransactions, nil
+}
+
+// Get returns the pending transaction with the hash
+func (t *TransactionsService) Get(ctx context.Context, hash common.Hash) (resp TransactionInfo, err error) {
+	err = t.client.requestJSON(ctx, http.MethodGet, transactionsPath(hash), nil, &resp)
+	return resp, err
+}
+
+// Resend sends the pending transaction with the hash to the blockchain again
+func (t *TransactionsService) Resend(ctx context.Context, hash common.Hash) (common.Hash, error) {
+	resp := struct {
+		TransactionHash common.Hash `json:"transactionHash"`
+	}{}
+	if err := t.client.requestJSON(ctx, http.MethodPost, transactionsPath(hash), nil, &resp); err != nil {
+		return common.Hash{}, err
+	}
+	return resp.TransactionHash, nil
+}
+
+// Cancel replaces the pending transaction with the hash by a transaction with
+// the same nonce and a higher gas price that does nothing, and returns the
+// hash of the replacement
+func (t *TransactionsService) Cancel(ctx context.Context, hash common.Hash) (common.Hash, error) {
+	resp := struct {
+		TransactionHash common.Hash `json:"transactionHash"`
+	}{}
+	if err := t.client.requestJSON(ctx, http.MethodDelete, transactionsPath(hash), nil, &resp); err != nil {
+		return common.Hash{}, err
+	}
+	return resp.TransactionHash, nil
+}
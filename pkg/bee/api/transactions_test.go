@@ -0,0 +1,73 @@
+package api_test
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethersphere/beekeeper/pkg/bee/api"
+	"github.com/ethersphere/beekeeper/pkg/bee/api/beetest"
+	"github.com/ethersphere/beekeeper/pkg/bigint"
+)
+
+func TestTransactions(t *testing.T) {
+	s, c := beetest.New(t)
+	ctx := context.Background()
+
+	created := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	first := common.HexToHash("0x01")
+	second := common.HexToHash("0x02")
+	s.AddTransaction(api.TransactionInfo{
+		TransactionHash: second,
+		Nonce:           2,
+		GasPrice:        bigint.Wrap(big.NewInt(1000)),
+		Created:         created,
+		Description:     "postage batch creation",
+	})
+	s.AddTransaction(api.TransactionInfo{TransactionHash: first, Nonce: 1, Created: created})
+
+	txs, err := c.Transactions.Pending(ctx)
+	if err != nil {
+		t.Fatalf("pending: %v", err)
+	}
+	if len(txs) != 2 || txs[0].TransactionHash != first || txs[1].TransactionHash != second {
+		t.Fatalf("got pending transactions %+v, want %s and %s", txs, first, second)
+	}
+
+	tx, err := c.Transactions.Get(ctx, second)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if tx.Nonce != 2 || tx.GasPrice.Int64() != 1000 || !tx.Created.Equal(created) || tx.Description != "postage batch creation" {
+		t.Fatalf("got transaction %+v", tx)
+	}
+
+	resent, err := c.Transactions.Resend(ctx, first)
+	if err != nil {
+		t.Fatalf("resend: %v", err)
+	}
+	if resent != first || s.Resent(first) != 1 {
+		t.Fatalf("resend: got hash %s and %d resends, want %s and 1", resent, s.Resent(first), first)
+	}
+
+	cancelled, err := c.Transactions.Cancel(ctx, first)
+	if err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+	if cancelled == first || cancelled == (common.Hash{}) {
+		t.Fatalf("cancel: got hash %s, want hash of a new transaction", cancelled)
+	}
+	if txs := s.PendingTransactions(); len(txs) != 1 || txs[0].TransactionHash != second {
+		t.Fatalf("got pending transactions %+v after cancel, want %s", txs, second)
+	}
+
+	if _, err := c.Transactions.Get(ctx, first); !api.IsHTTPStatusErrorCode(err, http.StatusNotFound) {
+		t.Fatalf("get cancelled: got error %v, want status %d", err, http.StatusNotFound)
+	}
+	if _, err := c.Transactions.Resend(ctx, first); !api.IsHTTPStatusErrorCode(err, http.StatusNotFound) {
+		t.Fatalf("resend cancelled: got error %v, want status %d", err, http.StatusNotFound)
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/ethersphere/bee/v2/pkg/crypto"
 	"github.com/ethersphere/bee/v2/pkg/swarm"
 	"github.com/ethersphere/beekeeper/pkg/bee/api"
+	"github.com/ethersphere/beekeeper/pkg/httpx"
 	"github.com/ethersphere/beekeeper/pkg/logging"
 	"github.com/ethersphere/beekeeper/pkg/swap"
 )
@@ -39,7 +40,9 @@ type ClientOptions struct {
 	APIURL        *url.URL
 	Name          string
 	NodeGroupName string
-	Retry         int
+	Retry         int              // attempts of requests retried by the client, ignored if RetryPolicy is set
+	RetryPolicy   *api.RetryPolicy // policy of retrying failed API requests, requests are not retried if nil
+	Metrics       *httpx.Metrics   // metrics of API requests, not recorded if nil
 	SwapClient    swap.BlockTimeFetcher
 	HTTPClient    *http.Client
 	Logger        logging.Logger
@@ -60,7 +63,15 @@ func NewClient(opts ClientOptions) (c *Client, err error) {
 		apiURL:        opts.APIURL,
 	}
 
-	c.api, err = api.NewClient(opts.APIURL, opts.HTTPClient)
+	var apiOpts []api.ClientOption
+	if opts.RetryPolicy != nil {
+		apiOpts = append(apiOpts, api.WithRetryPolicy(*opts.RetryPolicy))
+	}
+	if opts.Metrics != nil {
+		apiOpts = append(apiOpts, api.WithMetrics(opts.Metrics, opts.Name, opts.NodeGroupName))
+	}
+
+	c.api, err = api.NewClient(opts.APIURL, opts.HTTPClient, apiOpts...)
 	if err != nil {
 		return nil, fmt.Errorf("new api client: %w", err)
 	}
@@ -68,6 +79,11 @@ func NewClient(opts ClientOptions) (c *Client, err error) {
 	if opts.Retry > 0 {
 		c.retryCount = opts.Retry
 	}
+	// requests are retried by the policy, retrying them again would multiply
+	// the attempts
+	if opts.RetryPolicy != nil {
+		c.retryCount = 1
+	}
 
 	return c, nil
 }
@@ -562,6 +578,11 @@ func (c *Client) GetOrCreateMutableBatch(ctx context.Context, postageTTL time.Du
 	return c.CreatePostageBatch(ctx, amount, depth, label, false)
 }
 
+// ChainState returns the chain state of node
+func (c *Client) ChainState(ctx context.Context) (api.ChainStateResponse, error) {
+	return c.api.Postage.GetChainState(ctx)
+}
+
 // PostageBatches returns the list of batches of node
 func (c *Client) PostageBatches(ctx context.Context) ([]api.PostageStampResponse, error) {
 	return c.api.Postage.PostageBatches(ctx)
@@ -1036,6 +1057,40 @@ func (c *Client) MigrateStake(ctx context.Context) (string, error) {
 	return c.api.Stake.MigrateStake(ctx)
 }
 
+// RedistributionState returns the state of the node in the storage
+// incentives redistribution game
+func (c *Client) RedistributionState(ctx context.Context) (api.RedistributionState, error) {
+	return c.api.Redistribution.State(ctx)
+}
+
+// RCHash returns the reserve commitment hash of a sample of the node's reserve
+// at the depth with the anchors
+func (c *Client) RCHash(ctx context.Context, depth uint8, anchor1, anchor2 []byte) (api.RCHashResponse, error) {
+	return c.api.Redistribution.RCHash(ctx, depth, anchor1, anchor2)
+}
+
+// PendingTransactions returns transactions sent by the node that are not yet
+// confirmed
+func (c *Client) PendingTransactions(ctx context.Context) ([]api.TransactionInfo, error) {
+	return c.api.Transactions.Pending(ctx)
+}
+
+// Transaction returns the pending transaction with the hash
+func (c *Client) Transaction(ctx context.Context, hash common.Hash) (api.TransactionInfo, error) {
+	return c.api.Transactions.Get(ctx, hash)
+}
+
+// ResendTransaction sends the pending transaction with the hash again
+func (c *Client) ResendTransaction(ctx context.Context, hash common.Hash) (common.Hash, error) {
+	return c.api.Transactions.Resend(ctx, hash)
+}
+
+// CancelTransaction cancels the pending transaction with the hash and returns
+// the hash of the cancellation transaction
+func (c *Client) CancelTransaction(ctx context.Context, hash common.Hash) (common.Hash, error) {
+	return c.api.Transactions.Cancel(ctx, hash)
+}
+
 // WalletBalance fetches the balance for the given token
 func (c *Client) WalletBalance(ctx context.Context, token string) (*big.Int, error) {
 	resp, err := c.api.Node.Wallet(ctx)
@@ -1089,3 +1144,12 @@ func (c *Client) FindFeedUpdate(ctx context.Context, signer crypto.Signer, topic
 func (c *Client) Status(ctx context.Context) (*api.StatusResponse, error) {
 	return c.api.Status.Status(ctx)
 }
+
+// Version returns the Bee version of the node
+func (c *Client) Version(ctx context.Context) (string, error) {
+	h, err := c.api.Node.Health(ctx)
+	if err != nil {
+		return "", fmt.Errorf("get health: %w", err)
+	}
+	return h.Version, nil
+}
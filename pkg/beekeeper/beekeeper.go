@@ -2,13 +2,77 @@ package beekeeper
 
 import (
 	"context"
+	"time"
 
 	"github.com/ethersphere/beekeeper/pkg/orchestration"
 )
 
+// DefaultTeardownTimeout limits the duration of teardown hooks of actions
+// without a timeout.
+const DefaultTeardownTimeout = 5 * time.Minute
+
 // Action defines Beekeeper Action's interface. An action that
 // needs to expose metrics should implement the metrics.Reporter
-// interface.
+// interface. An action that needs to prepare or clean up the cluster
+// should implement the SetupAction and TeardownAction interfaces.
 type Action interface {
 	Run(ctx context.Context, cluster orchestration.Cluster, o any) (err error)
 }
+
+// SetupAction is implemented by actions that need to prepare the cluster,
+// for example by creating postage batches, before Run is called.
+type SetupAction interface {
+	Setup(ctx context.Context, cluster orchestration.Cluster, o any) (err error)
+}
+
+// TeardownAction is implemented by actions that need to clean up state they
+// left in the cluster, like uploads, pins or postage batches. Teardown is
+// called after Run even if Setup or Run failed or timed out.
+type TeardownAction interface {
+	Teardown(ctx context.Context, cluster orchestration.Cluster, o any) (err error)
+}
+
+// Wrapper is implemented by actions that wrap another action, like the
+// action middleware.
+type Wrapper interface {
+	Unwrap() Action
+}
+
+// AsSetupAction returns the setup hook of the action. A wrapping action
+// implements the hook for any action it wraps, so the hook is returned only
+// if the innermost action implements it as well.
+func AsSetupAction(a Action) (SetupAction, bool) {
+	s, ok := a.(SetupAction)
+	if !ok {
+		return nil, false
+	}
+	if _, ok := unwrap(a).(SetupAction); !ok {
+		return nil, false
+	}
+	return s, true
+}
+
+// AsTeardownAction returns the teardown hook of the action. A wrapping
+// action implements the hook for any action it wraps, so the hook is
+// returned only if the innermost action implements it as well.
+func AsTeardownAction(a Action) (TeardownAction, bool) {
+	t, ok := a.(TeardownAction)
+	if !ok {
+		return nil, false
+	}
+	if _, ok := unwrap(a).(TeardownAction); !ok {
+		return nil, false
+	}
+	return t, true
+}
+
+// unwrap returns the innermost action of wrapping actions.
+func unwrap(a Action) Action {
+	for {
+		w, ok := a.(Wrapper)
+		if !ok {
+			return a
+		}
+		a = w.Unwrap()
+	}
+}
@@ -7,7 +7,12 @@ import (
 	"github.com/opentracing/opentracing-go"
 )
 
-var _ Action = (*actionMiddleware)(nil)
+var (
+	_ Action         = (*actionMiddleware)(nil)
+	_ SetupAction    = (*actionMiddleware)(nil)
+	_ TeardownAction = (*actionMiddleware)(nil)
+	_ Wrapper        = (*actionMiddleware)(nil)
+)
 
 type actionMiddleware struct {
 	tracer     opentracing.Tracer
@@ -33,6 +38,37 @@ func (am *actionMiddleware) Run(ctx context.Context, cluster orchestration.Clust
 	return am.action.Run(ctx, cluster, o)
 }
 
+// Unwrap implements beekeeper.Wrapper.
+func (am *actionMiddleware) Unwrap() Action {
+	return am.action
+}
+
+// Setup implements beekeeper.SetupAction. It is a no-op if the wrapped
+// action does not implement it.
+func (am *actionMiddleware) Setup(ctx context.Context, cluster orchestration.Cluster, o any) (err error) {
+	a, ok := am.action.(SetupAction)
+	if !ok {
+		return nil
+	}
+	span := createSpan(ctx, am.tracer, am.actionName+"-setup")
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+	return a.Setup(ctx, cluster, o)
+}
+
+// Teardown implements beekeeper.TeardownAction. It is a no-op if the wrapped
+// action does not implement it.
+func (am *actionMiddleware) Teardown(ctx context.Context, cluster orchestration.Cluster, o any) (err error) {
+	a, ok := am.action.(TeardownAction)
+	if !ok {
+		return nil
+	}
+	span := createSpan(ctx, am.tracer, am.actionName+"-teardown")
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+	return a.Teardown(ctx, cluster, o)
+}
+
 func createSpan(ctx context.Context, tracer opentracing.Tracer, opName string) opentracing.Span {
 	if parentSpan := opentracing.SpanFromContext(ctx); parentSpan != nil {
 		return tracer.StartSpan(
@@ -0,0 +1,361 @@
+// Package chaos disrupts a running cluster while checks are executed against
+// it. It kills pods of nodes in chosen node groups at a configured rate, and
+// partitions node groups from the rest of the cluster with Kubernetes network
+// policies for a period. Every disruption is recorded as an event, logged and
+// traced, so that check failures can be matched to disruptions.
+package chaos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethersphere/beekeeper/pkg/k8s"
+	"github.com/ethersphere/beekeeper/pkg/logging"
+	"github.com/ethersphere/beekeeper/pkg/orchestration"
+	"github.com/ethersphere/beekeeper/pkg/random"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	v1 "k8s.io/api/core/v1"
+)
+
+// healTimeout is the time given to the removal of partitions after the
+// chaos is stopped
+const healTimeout = time.Minute
+
+// EventType represents the type of a chaos event
+type EventType string
+
+const (
+	EventPodKill   EventType = "pod-kill"
+	EventPartition EventType = "partition"
+	EventHeal      EventType = "heal"
+)
+
+// Event represents a disruption of the cluster
+type Event struct {
+	Type    EventType
+	Time    time.Time
+	Targets []string // names of the affected nodes
+	Err     error
+}
+
+// Options represents chaos options
+type Options struct {
+	PodKill   *PodKillOptions
+	Partition *PartitionOptions
+	Seed      int64 // seed of the random selection of pods, -1 for random
+}
+
+// PodKillOptions represents options of killing pods
+type PodKillOptions struct {
+	NodeGroups []string      // node groups of the nodes whose pods are killed, all node groups if empty
+	Interval   time.Duration // interval between pod kills
+	Count      int           // number of pods killed at once
+}
+
+// PartitionOptions represents options of network partitions
+type PartitionOptions struct {
+	NodeGroups []string      // node groups partitioned from the rest of the cluster
+	Interval   time.Duration // interval between partitions
+	Duration   time.Duration // duration of a partition
+}
+
+// Chaos disrupts the cluster
+type Chaos struct {
+	cluster   orchestration.Cluster
+	k8sClient *k8s.Client
+	tracer    opentracing.Tracer
+	logger    logging.Logger
+	opts      Options
+	rnd       *rand.Rand
+
+	mu     sync.Mutex
+	events []Event
+}
+
+// New returns new Chaos for the cluster
+func New(cluster orchestration.Cluster, k8sClient *k8s.Client, tracer opentracing.Tracer, logger logging.Logger, o Options) (*Chaos, error) {
+	if k8sClient == nil {
+		return nil, errors.New("kubernetes client is required for chaos")
+	}
+	if o.PodKill == nil && o.Partition == nil {
+		return nil, errors.New("no chaos actions configured")
+	}
+
+	nodeGroups := cluster.NodeGroups()
+	knownGroups := func(groups []string) error {
+		for _, g := range groups {
+			if _, ok := nodeGroups[g]; !ok {
+				return fmt.Errorf("node group %s not found in cluster", g)
+			}
+		}
+		return nil
+	}
+
+	if o.PodKill != nil {
+		if o.PodKill.Interval <= 0 {
+			return nil, errors.New("pod kill interval must be positive")
+		}
+		if o.PodKill.Count <= 0 {
+			o.PodKill.Count = 1
+		}
+		if err := knownGroups(o.PodKill.NodeGroups); err != nil {
+			return nil, fmt.Errorf("pod kill: %w", err)
+		}
+	}
+
+	if o.Partition != nil {
+		if len(o.Partition.NodeGroups) == 0 {
+			return nil, errors.New("partition node groups not provided")
+		}
+		if o.Partition.Interval <= 0 || o.Partition.Duration <= 0 {
+			return nil, errors.New("partition interval and duration must be positive")
+		}
+		if err := knownGroups(o.Partition.NodeGroups); err != nil {
+			return nil, fmt.Errorf("partition: %w", err)
+		}
+		if len(nodeGroups) <= len(o.Partition.NodeGroups) {
+			return nil, errors.New("partition requires node groups outside of the partition")
+		}
+	}
+
+	if o.Seed < 0 {
+		o.Seed = random.Int64()
+	}
+
+	if tracer == nil {
+		tracer = opentracing.NoopTracer{}
+	}
+
+	return &Chaos{
+		cluster:   cluster,
+		k8sClient: k8sClient,
+		tracer:    tracer,
+		logger:    logger,
+		opts:      o,
+		rnd:       random.PseudoGenerator(o.Seed),
+	}, nil
+}
+
+// Run disrupts the cluster until the context is canceled. Partitions are
+// removed before it returns, and it returns errors of all removals that
+// failed.
+func (c *Chaos) Run(ctx context.Context) error {
+	c.logger.Infof("chaos started with seed %d", c.opts.Seed)
+
+	var (
+		wg       sync.WaitGroup
+		healErrs []error
+	)
+
+	if c.opts.PodKill != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.every(ctx, c.opts.PodKill.Interval, c.killPods)
+		}()
+	}
+
+	if c.opts.Partition != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.every(ctx, c.opts.Partition.Interval, func(ctx context.Context) {
+				if err := c.partition(ctx); err != nil {
+					healErrs = append(healErrs, err)
+				}
+			})
+		}()
+	}
+
+	wg.Wait()
+
+	c.logger.Infof("chaos stopped after %d events", len(c.Events()))
+
+	return errors.Join(healErrs...)
+}
+
+// Events returns events recorded so far
+func (c *Chaos) Events() []Event {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return slices.Clone(c.events)
+}
+
+// every calls the action after each interval until the context is canceled
+func (c *Chaos) every(ctx context.Context, interval time.Duration, action func(ctx context.Context)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// a tick may be ready together with the cancellation
+			if ctx.Err() != nil {
+				return
+			}
+			action(ctx)
+		}
+	}
+}
+
+// killPods deletes pods of randomly selected nodes
+func (c *Chaos) killPods(ctx context.Context) {
+	nodes := c.nodes(c.opts.PodKill.NodeGroups)
+	c.rnd.Shuffle(len(nodes), func(i, j int) { nodes[i], nodes[j] = nodes[j], nodes[i] })
+	nodes = nodes[:min(c.opts.PodKill.Count, len(nodes))]
+	slices.Sort(nodes)
+
+	span := c.startSpan(EventPodKill, nodes)
+	defer span.Finish()
+
+	var errs []error
+	for _, n := range nodes {
+		pods, err := c.pods(ctx, []string{n})
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, p := range pods {
+			if _, err := c.k8sClient.Pods.Delete(ctx, p.Name, c.cluster.Namespace()); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	c.record(span, Event{Type: EventPodKill, Time: time.Now(), Targets: nodes, Err: errors.Join(errs...)})
+}
+
+// partition separates pods of nodes of the partition node groups from the
+// rest of the cluster for the partition duration, and returns an error if the
+// partition can not be removed
+func (c *Chaos) partition(ctx context.Context) error {
+	inside := c.nodes(c.opts.Partition.NodeGroups)
+	var outside []string
+	for name := range c.cluster.NodeGroups() {
+		if !slices.Contains(c.opts.Partition.NodeGroups, name) {
+			outside = append(outside, c.nodes([]string{name})...)
+		}
+	}
+	slices.Sort(outside)
+
+	span := c.startSpan(EventPartition, inside)
+	defer span.Finish()
+
+	// a selector of pods without names is rejected by kubernetes
+	if len(inside) == 0 || len(outside) == 0 {
+		c.record(span, Event{Type: EventPartition, Time: time.Now(), Targets: inside, Err: errors.New("no nodes on one side of the partition")})
+		return nil
+	}
+
+	insidePods, err := c.partitionPods(ctx, inside)
+	if err != nil {
+		c.record(span, Event{Type: EventPartition, Time: time.Now(), Targets: inside, Err: err})
+		return nil
+	}
+	outsidePods, err := c.partitionPods(ctx, outside)
+	if err != nil {
+		c.record(span, Event{Type: EventPartition, Time: time.Now(), Targets: inside, Err: err})
+		return nil
+	}
+
+	namespace := c.cluster.Namespace()
+	policies := partitionPolicies(insidePods, outsidePods, namespace)
+
+	var errs []error
+	for name, o := range policies {
+		if _, err := c.k8sClient.NetworkPolicy.Set(ctx, name, namespace, o); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	err = errors.Join(errs...)
+	c.record(span, Event{Type: EventPartition, Time: time.Now(), Targets: inside, Err: err})
+
+	if err == nil {
+		select {
+		case <-ctx.Done():
+		case <-time.After(c.opts.Partition.Duration):
+		}
+	}
+
+	// partitions are removed even if the chaos is stopped
+	healCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), healTimeout)
+	defer cancel()
+
+	errs = nil
+	for name := range policies {
+		if err := c.k8sClient.NetworkPolicy.Delete(healCtx, name, namespace); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	err = errors.Join(errs...)
+	c.record(span, Event{Type: EventHeal, Time: time.Now(), Targets: inside, Err: err})
+
+	return err
+}
+
+// nodes returns sorted names of nodes in the node groups, or in all node
+// groups if none are given
+func (c *Chaos) nodes(groups []string) (nodes []string) {
+	for name, ng := range c.cluster.NodeGroups() {
+		if len(groups) == 0 || slices.Contains(groups, name) {
+			nodes = append(nodes, ng.NodesSorted()...)
+		}
+	}
+	slices.Sort(nodes)
+	return nodes
+}
+
+// pods returns pods of the nodes sorted by name. Pods are selected by the API
+// service of each node, which is named after the node also in discovered
+// clusters, so that pods are found regardless of how the nodes were deployed.
+func (c *Chaos) pods(ctx context.Context, nodes []string) ([]v1.Pod, error) {
+	var result []v1.Pod
+	for _, n := range nodes {
+		pods, err := c.k8sClient.Service.FindPods(ctx, c.cluster.Namespace(), n)
+		if err != nil {
+			return nil, fmt.Errorf("find pods of node %s: %w", n, err)
+		}
+		if len(pods) == 0 {
+			return nil, fmt.Errorf("no pods of node %s found", n)
+		}
+		result = append(result, pods...)
+	}
+	slices.SortFunc(result, func(a, b v1.Pod) int { return strings.Compare(a.Name, b.Name) })
+	return result, nil
+}
+
+// startSpan starts the span of a chaos event
+func (c *Chaos) startSpan(t EventType, targets []string) opentracing.Span {
+	span := c.tracer.StartSpan("chaos-" + string(t))
+	span.SetTag("chaos.namespace", c.cluster.Namespace())
+	span.SetTag("chaos.targets", strings.Join(targets, ","))
+	return span
+}
+
+// record stores, logs and traces the event
+func (c *Chaos) record(span opentracing.Span, e Event) {
+	c.mu.Lock()
+	c.events = append(c.events, e)
+	c.mu.Unlock()
+
+	span.LogKV("event", string(e.Type), "targets", strings.Join(e.Targets, ","))
+
+	if e.Err != nil {
+		ext.Error.Set(span, true)
+		span.LogKV("error", e.Err.Error())
+		c.logger.Errorf("chaos %s of nodes %s failed: %v", e.Type, strings.Join(e.Targets, ", "), e.Err)
+		return
+	}
+
+	c.logger.Infof("chaos %s of nodes %s", e.Type, strings.Join(e.Targets, ", "))
+}
@@ -0,0 +1,327 @@
+package chaos_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/beekeeper/pkg/chaos"
+	"github.com/ethersphere/beekeeper/pkg/k8s"
+	"github.com/ethersphere/beekeeper/pkg/k8s/networkpolicy"
+	"github.com/ethersphere/beekeeper/pkg/k8s/pod"
+	"github.com/ethersphere/beekeeper/pkg/k8s/service"
+	"github.com/ethersphere/beekeeper/pkg/logging"
+	"github.com/ethersphere/beekeeper/pkg/orchestration"
+	"github.com/ethersphere/beekeeper/pkg/orchestration/fake"
+	"github.com/opentracing/opentracing-go/mocktracer"
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+const testNamespace = "test"
+
+// newChaosCluster returns a fake cluster with bee and light node groups, and
+// the fake clientset with API services and statefulset pods of its nodes.
+// Pods are not named after the nodes, as in discovered clusters.
+func newChaosCluster(t *testing.T) (*fake.Cluster, *k8sfake.Clientset, *k8s.Client) {
+	t.Helper()
+
+	cluster := fake.NewCluster("test", orchestration.ClusterOptions{Namespace: testNamespace}, nil)
+	t.Cleanup(cluster.Close)
+
+	if err := cluster.AddNodes(context.Background(), "bee", 3, orchestration.Config{FullNode: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := cluster.AddNodes(context.Background(), "light", 1, orchestration.Config{}); err != nil {
+		t.Fatal(err)
+	}
+
+	var objects []runtime.Object
+	for _, name := range cluster.NodeNames() {
+		objects = append(objects, newNodeObjects(name, "swarm-"+name, true)...)
+	}
+	clientset := k8sfake.NewSimpleClientset(objects...)
+
+	k8sClient := &k8s.Client{
+		Pods:          pod.NewClient(clientset, logging.New(io.Discard, 0)),
+		Service:       service.NewClient(clientset),
+		NetworkPolicy: networkpolicy.NewClient(clientset),
+	}
+
+	return cluster, clientset, k8sClient
+}
+
+// newNodeObjects returns the API service of the node and its pod, with the
+// pod name label if the pod is managed by a statefulset
+func newNodeObjects(node, podName string, statefulSet bool) []runtime.Object {
+	labels := map[string]string{"app.kubernetes.io/instance": node}
+	podLabels := maps.Clone(labels)
+	if statefulSet {
+		podLabels["statefulset.kubernetes.io/pod-name"] = podName
+	}
+
+	return []runtime.Object{
+		&v1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: node, Namespace: testNamespace},
+			Spec:       v1.ServiceSpec{Selector: labels},
+		},
+		&v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: podName, Namespace: testNamespace, Labels: podLabels}},
+	}
+}
+
+func runChaos(t *testing.T, c *chaos.Chaos, d time.Duration) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+
+	if err := c.Run(ctx); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+}
+
+func TestPodKill(t *testing.T) {
+	cluster, clientset, k8sClient := newChaosCluster(t)
+	tracer := mocktracer.New()
+
+	c, err := chaos.New(cluster, k8sClient, tracer, logging.New(io.Discard, 0), chaos.Options{
+		PodKill: &chaos.PodKillOptions{NodeGroups: []string{"bee"}, Interval: 10 * time.Millisecond, Count: 2},
+		Seed:    1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	runChaos(t, c, 55*time.Millisecond)
+
+	events := c.Events()
+	if len(events) == 0 {
+		t.Fatal("no events recorded")
+	}
+	for _, e := range events {
+		if e.Type != chaos.EventPodKill {
+			t.Errorf("got event type %s, want %s", e.Type, chaos.EventPodKill)
+		}
+		if len(e.Targets) != 2 {
+			t.Errorf("got targets %v, want 2 nodes", e.Targets)
+		}
+		if slices.Contains(e.Targets, "light-0") {
+			t.Errorf("pod of node light-0 outside of the node groups was killed")
+		}
+	}
+
+	pods, err := clientset.CoreV1().Pods(testNamespace).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pods.Items) > 2 {
+		t.Errorf("got %d remaining pods, want at most 2", len(pods.Items))
+	}
+
+	spans := tracer.FinishedSpans()
+	if len(spans) != len(events) {
+		t.Fatalf("got %d spans, want %d", len(spans), len(events))
+	}
+	if spans[0].OperationName != "chaos-pod-kill" {
+		t.Errorf("got span %s, want chaos-pod-kill", spans[0].OperationName)
+	}
+}
+
+func TestPartition(t *testing.T) {
+	cluster, clientset, k8sClient := newChaosCluster(t)
+	tracer := mocktracer.New()
+
+	created := make(map[string]*networkingv1.NetworkPolicy)
+	clientset.PrependReactor("create", "networkpolicies", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		np := action.(k8stesting.CreateAction).GetObject().(*networkingv1.NetworkPolicy)
+		created[np.Name] = np
+		return false, nil, nil
+	})
+
+	c, err := chaos.New(cluster, k8sClient, tracer, logging.New(io.Discard, 0), chaos.Options{
+		Partition: &chaos.PartitionOptions{NodeGroups: []string{"light"}, Interval: 10 * time.Millisecond, Duration: time.Hour},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the partition lasts until the chaos is stopped
+	runChaos(t, c, 50*time.Millisecond)
+
+	events := c.Events()
+	if len(events) != 2 || events[0].Type != chaos.EventPartition || events[1].Type != chaos.EventHeal {
+		t.Fatalf("got events %v, want partition and heal", events)
+	}
+	if !slices.Equal(events[0].Targets, []string{"light-0"}) {
+		t.Errorf("got targets %v, want [light-0]", events[0].Targets)
+	}
+
+	inside, ok := created[chaos.PartitionPolicyName+"-inside"]
+	if !ok {
+		t.Fatal("network policy of the partition was not created")
+	}
+	if got := inside.Spec.PodSelector.MatchExpressions[0].Values; !slices.Equal(got, []string{"swarm-light-0"}) {
+		t.Errorf("got selected pods %v, want [swarm-light-0]", got)
+	}
+	if got := inside.Spec.Ingress[0].From[0].PodSelector.MatchExpressions[0].Values; !slices.Equal(got, []string{"swarm-bee-0", "swarm-bee-1", "swarm-bee-2"}) {
+		t.Errorf("got denied pods %v, want pods of bee node group", got)
+	}
+	if _, ok := created[chaos.PartitionPolicyName+"-outside"]; !ok {
+		t.Error("network policy of the rest of the cluster was not created")
+	}
+
+	policies, err := clientset.NetworkingV1().NetworkPolicies(testNamespace).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(policies.Items) != 0 {
+		t.Errorf("got %d network policies after the chaos is stopped, want 0", len(policies.Items))
+	}
+
+	if spans := tracer.FinishedSpans(); len(spans) != 1 || spans[0].OperationName != "chaos-partition" {
+		t.Errorf("got spans %v, want one chaos-partition span", spans)
+	}
+}
+
+func TestPartitionWithoutStatefulSet(t *testing.T) {
+	cluster, clientset, k8sClient := newChaosCluster(t)
+
+	// the pod of the light node is not managed by a statefulset, so network
+	// policies can not select it by name
+	p, err := clientset.CoreV1().Pods(testNamespace).Get(context.Background(), "swarm-light-0", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	delete(p.Labels, "statefulset.kubernetes.io/pod-name")
+	if _, err := clientset.CoreV1().Pods(testNamespace).Update(context.Background(), p, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := chaos.New(cluster, k8sClient, nil, logging.New(io.Discard, 0), chaos.Options{
+		Partition: &chaos.PartitionOptions{NodeGroups: []string{"light"}, Interval: 10 * time.Millisecond, Duration: time.Hour},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	runChaos(t, c, 35*time.Millisecond)
+
+	events := c.Events()
+	if len(events) == 0 {
+		t.Fatal("no events recorded")
+	}
+	for _, e := range events {
+		if e.Type != chaos.EventPartition || e.Err == nil {
+			t.Errorf("got event %s with error %v, want failed partition", e.Type, e.Err)
+		}
+	}
+
+	policies, err := clientset.NetworkingV1().NetworkPolicies(testNamespace).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(policies.Items) != 0 {
+		t.Errorf("got %d network policies, want 0", len(policies.Items))
+	}
+}
+
+func TestPartitionHealErrors(t *testing.T) {
+	cluster, clientset, k8sClient := newChaosCluster(t)
+
+	var deletes atomic.Int32
+	clientset.PrependReactor("delete", "networkpolicies", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, fmt.Errorf("delete %d failed", deletes.Add(1))
+	})
+
+	c, err := chaos.New(cluster, k8sClient, nil, logging.New(io.Discard, 0), chaos.Options{
+		Partition: &chaos.PartitionOptions{NodeGroups: []string{"light"}, Interval: 10 * time.Millisecond, Duration: time.Millisecond},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 55*time.Millisecond)
+	defer cancel()
+
+	err = c.Run(ctx)
+	if err == nil {
+		t.Fatal("run: expected error of failed heals")
+	}
+
+	// every partition deletes two network policies
+	n := int(deletes.Load())
+	if n < 4 {
+		t.Fatalf("got %d deletes, want at least two partitions", n)
+	}
+	for i := 1; i <= n; i++ {
+		if want := fmt.Sprintf("delete %d failed", i); !strings.Contains(err.Error(), want) {
+			t.Errorf("run: error %q does not contain %q", err, want)
+		}
+	}
+}
+
+func TestPartitionEmptySide(t *testing.T) {
+	cluster, clientset, k8sClient := newChaosCluster(t)
+	if err := cluster.AddNodes(context.Background(), "empty", 0, orchestration.Config{}); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := chaos.New(cluster, k8sClient, nil, logging.New(io.Discard, 0), chaos.Options{
+		Partition: &chaos.PartitionOptions{NodeGroups: []string{"empty"}, Interval: 10 * time.Millisecond, Duration: time.Hour},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	runChaos(t, c, 35*time.Millisecond)
+
+	events := c.Events()
+	if len(events) == 0 {
+		t.Fatal("no events recorded")
+	}
+	for _, e := range events {
+		if e.Type != chaos.EventPartition || e.Err == nil {
+			t.Errorf("got event %s with error %v, want refused partition", e.Type, e.Err)
+		}
+	}
+
+	policies, err := clientset.NetworkingV1().NetworkPolicies(testNamespace).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(policies.Items) != 0 {
+		t.Errorf("got %d network policies, want 0", len(policies.Items))
+	}
+}
+
+func TestNewInvalidOptions(t *testing.T) {
+	cluster, _, k8sClient := newChaosCluster(t)
+
+	for _, tc := range []struct {
+		name string
+		opts chaos.Options
+	}{
+		{name: "no actions"},
+		{name: "pod kill without interval", opts: chaos.Options{PodKill: &chaos.PodKillOptions{}}},
+		{name: "pod kill of unknown node group", opts: chaos.Options{PodKill: &chaos.PodKillOptions{NodeGroups: []string{"missing"}, Interval: time.Second}}},
+		{name: "partition without node groups", opts: chaos.Options{Partition: &chaos.PartitionOptions{Interval: time.Second, Duration: time.Second}}},
+		{name: "partition without duration", opts: chaos.Options{Partition: &chaos.PartitionOptions{NodeGroups: []string{"light"}, Interval: time.Second}}},
+		{name: "partition of all node groups", opts: chaos.Options{Partition: &chaos.PartitionOptions{NodeGroups: []string{"bee", "light"}, Interval: time.Second, Duration: time.Second}}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := chaos.New(cluster, k8sClient, nil, logging.New(io.Discard, 0), tc.opts); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
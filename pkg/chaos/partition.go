@@ -0,0 +1,104 @@
+package chaos
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethersphere/beekeeper/pkg/k8s/networkpolicy"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	// PartitionPolicyName is the name prefix of network policies of partitions
+	PartitionPolicyName = "beekeeper-chaos-partition"
+
+	podNameLabel       = "statefulset.kubernetes.io/pod-name"
+	namespaceNameLabel = "kubernetes.io/metadata.name"
+	apiPortName        = "api"
+)
+
+// partitionPods returns names of pods of the nodes. Network policies select
+// pods by the pod name label of statefulsets, so pods that are not managed by
+// a statefulset can not be partitioned.
+func (c *Chaos) partitionPods(ctx context.Context, nodes []string) ([]string, error) {
+	pods, err := c.pods(ctx, nodes)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(pods))
+	for _, p := range pods {
+		if p.Labels[podNameLabel] != p.Name {
+			return nil, fmt.Errorf("pod %s has no %s label of a statefulset pod", p.Name, podNameLabel)
+		}
+		names = append(names, p.Name)
+	}
+	return names, nil
+}
+
+// partitionPolicies returns network policies that deny traffic between the
+// pods of nodes inside and outside of the partition. Both sides only accept
+// traffic from their own side, from pods that are not nodes of the cluster
+// and from other namespaces, and the API of the nodes stays reachable, so
+// that checks can still access them.
+func partitionPolicies(inside, outside []string, namespace string) map[string]networkpolicy.Options {
+	labels := map[string]string{
+		"app.kubernetes.io/managed-by": "beekeeper",
+		"app.kubernetes.io/component":  "chaos",
+	}
+
+	return map[string]networkpolicy.Options{
+		PartitionPolicyName + "-inside": {
+			Labels: labels,
+			Spec:   partitionPolicySpec(inside, outside, namespace),
+		},
+		PartitionPolicyName + "-outside": {
+			Labels: labels,
+			Spec:   partitionPolicySpec(outside, inside, namespace),
+		},
+	}
+}
+
+func partitionPolicySpec(side, other []string, namespace string) networkingv1.NetworkPolicySpec {
+	apiPort := intstr.FromString(apiPortName)
+	otherPods := podsSelector(metav1.LabelSelectorOpNotIn, other)
+
+	return networkingv1.NetworkPolicySpec{
+		PodSelector: podsSelector(metav1.LabelSelectorOpIn, side),
+		PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+		Ingress: []networkingv1.NetworkPolicyIngressRule{
+			{
+				From: []networkingv1.NetworkPolicyPeer{{
+					PodSelector: &otherPods,
+				}},
+			},
+			{
+				From: []networkingv1.NetworkPolicyPeer{{
+					NamespaceSelector: &metav1.LabelSelector{
+						MatchExpressions: []metav1.LabelSelectorRequirement{{
+							Key:      namespaceNameLabel,
+							Operator: metav1.LabelSelectorOpNotIn,
+							Values:   []string{namespace},
+						}},
+					},
+				}},
+			},
+			{
+				Ports: []networkingv1.NetworkPolicyPort{{Port: &apiPort}},
+			},
+		},
+	}
+}
+
+// podsSelector selects the pods by their names with the operator
+func podsSelector(op metav1.LabelSelectorOperator, pods []string) metav1.LabelSelector {
+	return metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{{
+			Key:      podNameLabel,
+			Operator: op,
+			Values:   pods,
+		}},
+	}
+}
@@ -0,0 +1,75 @@
+package check
+
+import (
+	"errors"
+	"fmt"
+)
+
+// errSkipped is wrapped by results of checks that were not executed because
+// one of their dependencies did not succeed.
+var errSkipped = errors.New("check skipped")
+
+// checkNode is a check in the execution graph together with the indexes of
+// the checks it depends on.
+type checkNode struct {
+	run  checkRun
+	deps []int
+}
+
+// buildGraph links checks by their declared dependencies. A dependency on a
+// check with a matrix is a dependency on all of its variants. Dependencies on
+// checks that are not part of the run are ignored. It returns an error if the
+// dependencies form a cycle.
+func buildGraph(runs []checkRun) ([]checkNode, error) {
+	names := make(map[string]bool, len(runs))
+	index := make(map[string][]int, len(runs))
+	for i, r := range runs {
+		if names[r.name] {
+			return nil, fmt.Errorf("check '%s' selected more than once", r.name)
+		}
+		names[r.name] = true
+		index[r.checkName()] = append(index[r.checkName()], i)
+	}
+
+	nodes := make([]checkNode, len(runs))
+	for i, r := range runs {
+		nodes[i].run = r
+		for _, dep := range r.dependsOn {
+			nodes[i].deps = append(nodes[i].deps, index[dep]...)
+		}
+	}
+
+	// detect cycles with a depth-first search
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	marks := make([]int, len(nodes))
+	var visit func(i int, path []string) error
+	visit = func(i int, path []string) error {
+		path = append(path, nodes[i].run.name)
+		switch marks[i] {
+		case visiting:
+			return fmt.Errorf("circular dependency detected: %v", path)
+		case visited:
+			return nil
+		}
+		marks[i] = visiting
+		for _, d := range nodes[i].deps {
+			if err := visit(d, path); err != nil {
+				return err
+			}
+		}
+		marks[i] = visited
+		return nil
+	}
+
+	for i := range nodes {
+		if err := visit(i, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return nodes, nil
+}
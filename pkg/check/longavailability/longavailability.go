@@ -16,8 +16,6 @@ import (
 type Options struct {
 	Refs         []string
 	RndSeed      int64
-	RetryCount   int
-	RetryWait    time.Duration
 	NextIterWait time.Duration
 }
 
@@ -25,8 +23,6 @@ type Options struct {
 func NewDefaultOptions() Options {
 	return Options{
 		RndSeed:      time.Now().UnixNano(),
-		RetryCount:   3,
-		RetryWait:    10 * time.Second,
 		NextIterWait: 6 * time.Hour,
 	}
 }
@@ -83,35 +79,25 @@ func (c *Check) Run(ctx context.Context, cluster orchestration.Cluster, o any) e
 
 			c.metrics.DownloadAttempts.WithLabelValues(labelValue).Inc()
 
-			for i := 0; i <= opts.RetryCount; i++ {
-				if i == opts.RetryCount {
-					c.logger.Errorf("node %s: download for %s failed after %d tries", node.Name(), addr, opts.RetryCount)
-					c.metrics.DownloadErrors.WithLabelValues(labelValue).Inc()
-					c.metrics.DownloadStatus.WithLabelValues(labelValue).Set(0)
-					break
-				}
-
-				c.logger.Infof("node %s: download attempt %d for %s", node.Name(), i+1, addr)
-				start := time.Now()
-				cache := false
-				size, _, err := node.Client().DownloadFile(ctx, addr, &api.DownloadOptions{Cache: &cache})
-				if err != nil {
-					c.metrics.FailedDownloadAttempts.WithLabelValues(labelValue).Inc()
-					c.logger.Errorf("node %s: download %s error: %v", node.Name(), addr, err)
-					c.logger.Infof("retrying in: %v", opts.RetryWait)
-					time.Sleep(opts.RetryWait)
-					continue
-				}
-				c.logger.Infof("download size %d", size)
-				c.metrics.DownloadSize.WithLabelValues(labelValue).Set(float64(size))
-
-				dur := time.Since(start)
-				c.metrics.DownloadDuration.WithLabelValues(labelValue).Observe(dur.Seconds())
-				c.logger.Infof("node %s: downloaded %s successfully in %v", node.Name(), addr, dur)
-				c.metrics.DownloadStatus.WithLabelValues(labelValue).Set(1)
-				c.metrics.Retrieved.WithLabelValues(labelValue).Inc()
-				break
+			c.logger.Infof("node %s: downloading %s", node.Name(), addr)
+			start := time.Now()
+			cache := false
+			size, _, err := node.Client().DownloadFile(ctx, addr, &api.DownloadOptions{Cache: &cache})
+			if err != nil {
+				c.metrics.FailedDownloadAttempts.WithLabelValues(labelValue).Inc()
+				c.metrics.DownloadErrors.WithLabelValues(labelValue).Inc()
+				c.metrics.DownloadStatus.WithLabelValues(labelValue).Set(0)
+				c.logger.Errorf("node %s: download %s error: %v", node.Name(), addr, err)
+				continue
 			}
+			c.logger.Infof("download size %d", size)
+			c.metrics.DownloadSize.WithLabelValues(labelValue).Set(float64(size))
+
+			dur := time.Since(start)
+			c.metrics.DownloadDuration.WithLabelValues(labelValue).Observe(dur.Seconds())
+			c.logger.Infof("node %s: downloaded %s successfully in %v", node.Name(), addr, dur)
+			c.metrics.DownloadStatus.WithLabelValues(labelValue).Set(1)
+			c.metrics.Retrieved.WithLabelValues(labelValue).Inc()
 		}
 
 		c.logger.Infof("iteration %d completed", it)
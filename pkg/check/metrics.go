@@ -0,0 +1,72 @@
+package check
+
+import (
+	m "github.com/ethersphere/beekeeper/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type metrics struct {
+	CheckOutcomes       *prometheus.CounterVec
+	CheckAttempts       *prometheus.CounterVec
+	Runs                *prometheus.CounterVec
+	LastRunDuration     prometheus.Gauge
+	LastRunFailedChecks prometheus.Gauge
+	LastRunTimestamp    prometheus.Gauge
+}
+
+func newMetrics() metrics {
+	subsystem := "check_runner"
+	return metrics{
+		CheckOutcomes: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: m.Namespace,
+				Subsystem: subsystem,
+				Name:      "check_outcomes_total",
+				Help:      "Number of executed checks by outcome (passed, flaky, failed, skipped).",
+			},
+			[]string{"check", "type", "outcome"},
+		),
+		CheckAttempts: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: m.Namespace,
+				Subsystem: subsystem,
+				Name:      "check_attempts_total",
+				Help:      "Number of check execution attempts including retries.",
+			},
+			[]string{"check", "type"},
+		),
+		Runs: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: m.Namespace,
+				Subsystem: subsystem,
+				Name:      "runs_total",
+				Help:      "Number of check runs by result (success, failure).",
+			},
+			[]string{"result"},
+		),
+		LastRunDuration: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: m.Namespace,
+				Subsystem: subsystem,
+				Name:      "last_run_duration_seconds",
+				Help:      "Duration of the last check run.",
+			},
+		),
+		LastRunFailedChecks: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: m.Namespace,
+				Subsystem: subsystem,
+				Name:      "last_run_failed_checks",
+				Help:      "Number of checks that failed in the last check run.",
+			},
+		),
+		LastRunTimestamp: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: m.Namespace,
+				Subsystem: subsystem,
+				Name:      "last_run_timestamp_seconds",
+				Help:      "Unix time at which the last check run finished.",
+			},
+		),
+	}
+}
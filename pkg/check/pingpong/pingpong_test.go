@@ -0,0 +1,27 @@
+package pingpong_test
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/ethersphere/beekeeper/pkg/check/pingpong"
+	"github.com/ethersphere/beekeeper/pkg/logging"
+	"github.com/ethersphere/beekeeper/pkg/orchestration"
+	"github.com/ethersphere/beekeeper/pkg/orchestration/fake"
+)
+
+func TestCheck(t *testing.T) {
+	ctx := context.Background()
+
+	cluster := fake.NewCluster("pingpong", orchestration.ClusterOptions{}, nil)
+	defer cluster.Close()
+	if err := cluster.AddNodes(ctx, "bee", 3, orchestration.Config{FullNode: true}); err != nil {
+		t.Fatalf("add nodes: %v", err)
+	}
+
+	check := pingpong.NewCheck(logging.New(io.Discard, 0))
+	if err := check.Run(ctx, cluster, pingpong.NewDefaultOptions()); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+}
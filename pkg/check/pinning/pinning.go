@@ -0,0 +1,380 @@
+// Package pinning checks that pinned content survives garbage collection.
+// Files and collections are uploaded with and without pinning, the reserve
+// and cache of the uploading nodes are filled with chunks to force eviction,
+// and pinned content must remain stored locally while unpinned content is
+// evicted. After unpinning, the formerly pinned content must be evictable
+// as well.
+//
+// The check depends on the same test setup as the gc check: it is meant to
+// run ONLY on the CI, against bee built with the diff patches from
+// .github/patches in the bee repo that shrink the reserve and the cache. A
+// stock bee node stores millions of chunks, which the check never uploads,
+// so its unpinned content is not evicted and the check fails. The patched
+// setup must satisfy:
+//
+//   - Reserve Capacity = 16 chunks
+//   - Cache Capacity = 10 chunks
+//   - FillChunks greater than the reserve and cache capacity together, so
+//     that every chunk stored before the fill can be evicted by it
+//   - FileSize small enough that the uploaded content fits in the reserve
+//     and cache, as unpinned content evicted before the fill fails the check
+package pinning
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/ethersphere/bee/v2/pkg/file/redundancy"
+	"github.com/ethersphere/bee/v2/pkg/storage"
+	"github.com/ethersphere/bee/v2/pkg/swarm"
+	"github.com/ethersphere/bee/v2/pkg/traversal"
+	"github.com/ethersphere/beekeeper/pkg/bee"
+	"github.com/ethersphere/beekeeper/pkg/bee/api"
+	"github.com/ethersphere/beekeeper/pkg/beekeeper"
+	"github.com/ethersphere/beekeeper/pkg/logging"
+	"github.com/ethersphere/beekeeper/pkg/orchestration"
+	"github.com/ethersphere/beekeeper/pkg/random"
+)
+
+// Options represents check options
+type Options struct {
+	NodeCount    int           // number of full nodes that upload content
+	FileSize     int64         // size of uploaded files in bytes
+	FillChunks   int           // number of chunks uploaded to every node to force eviction
+	GCTimeout    time.Duration // maximum time of waiting for unpinned content to be evicted
+	PollInterval time.Duration // interval of checking whether content was evicted
+	PostageTTL   time.Duration
+	PostageDepth uint64
+	PostageLabel string
+	Seed         int64
+}
+
+// NewDefaultOptions returns new default options
+func NewDefaultOptions() Options {
+	return Options{
+		NodeCount:    1,
+		FileSize:     32 * 1024,
+		FillChunks:   100,
+		GCTimeout:    2 * time.Minute,
+		PollInterval: 5 * time.Second,
+		PostageTTL:   24 * time.Hour,
+		PostageDepth: 21,
+		PostageLabel: "test-label",
+		Seed:         0,
+	}
+}
+
+// compile check whether Check implements interface
+var _ beekeeper.Action = (*Check)(nil)
+
+// Check instance.
+type Check struct {
+	logger logging.Logger
+}
+
+// NewCheck returns a new check instance.
+func NewCheck(logger logging.Logger) beekeeper.Action {
+	return &Check{
+		logger: logger,
+	}
+}
+
+// content is uploaded content and addresses of all its chunks
+type content struct {
+	name   string
+	ref    swarm.Address
+	chunks []swarm.Address
+	pinned bool
+}
+
+// uploader is a node that uploaded content
+type uploader struct {
+	client   *bee.Client
+	batchID  string
+	contents []*content
+}
+
+func (c *Check) Run(ctx context.Context, cluster orchestration.Cluster, opts any) (err error) {
+	o, ok := opts.(Options)
+	if !ok {
+		return fmt.Errorf("invalid options type")
+	}
+	if o.NodeCount <= 0 {
+		return errors.New("node count must be positive")
+	}
+
+	rnd := random.PseudoGenerator(o.Seed)
+
+	clients, err := cluster.NodesClients(ctx)
+	if err != nil {
+		return fmt.Errorf("get clients: %w", err)
+	}
+
+	nodes := cluster.FullNodeNames()
+	if len(nodes) < o.NodeCount {
+		return fmt.Errorf("cluster has %d full nodes, %d required", len(nodes), o.NodeCount)
+	}
+	slices.Sort(nodes)
+	rnd.Shuffle(len(nodes), func(i, j int) { nodes[i], nodes[j] = nodes[j], nodes[i] })
+
+	var uploaders []*uploader
+	for _, node := range nodes[:o.NodeCount] {
+		u, err := c.upload(ctx, clients[node], rnd, o)
+		if err != nil {
+			return fmt.Errorf("node %s: %w", node, err)
+		}
+		uploaders = append(uploaders, u)
+	}
+
+	for _, u := range uploaders {
+		if err := checkPins(ctx, u.client, u.contents); err != nil {
+			return fmt.Errorf("node %s: %w", u.client.Name(), err)
+		}
+		// unpinned content must be stored as well, as otherwise it would be
+		// reported as evicted without any eviction
+		if err := checkStored(ctx, u.client, u.contents); err != nil {
+			return fmt.Errorf("node %s: after upload: %w", u.client.Name(), err)
+		}
+	}
+
+	c.logger.Info("filling reserve and cache to evict unpinned content")
+	if err := c.fill(ctx, uploaders, rnd, o); err != nil {
+		return err
+	}
+
+	for _, u := range uploaders {
+		if err := c.waitEvicted(ctx, u.client, pinned(u.contents, false), o); err != nil {
+			return fmt.Errorf("node %s: %w", u.client.Name(), err)
+		}
+		if err := checkStored(ctx, u.client, pinned(u.contents, true)); err != nil {
+			return fmt.Errorf("node %s: after eviction: %w", u.client.Name(), err)
+		}
+		c.logger.Infof("node %s: pinned content survived eviction", u.client.Name())
+	}
+
+	for _, u := range uploaders {
+		for _, ct := range pinned(u.contents, true) {
+			if err := u.client.UnpinRootHash(ctx, ct.ref); err != nil {
+				return fmt.Errorf("node %s: unpin %s %s: %w", u.client.Name(), ct.name, ct.ref, err)
+			}
+			ct.pinned = false
+		}
+		if err := checkPins(ctx, u.client, u.contents); err != nil {
+			return fmt.Errorf("node %s: after unpinning: %w", u.client.Name(), err)
+		}
+	}
+
+	c.logger.Info("filling reserve and cache to evict unpinned content")
+	if err := c.fill(ctx, uploaders, rnd, o); err != nil {
+		return err
+	}
+
+	for _, u := range uploaders {
+		if err := c.waitEvicted(ctx, u.client, u.contents, o); err != nil {
+			return fmt.Errorf("node %s: after unpinning: %w", u.client.Name(), err)
+		}
+		c.logger.Infof("node %s: unpinned content was evicted", u.client.Name())
+	}
+
+	return nil
+}
+
+// upload uploads a pinned file, a pinned collection and an unpinned file to
+// the node and collects addresses of their chunks
+func (c *Check) upload(ctx context.Context, client *bee.Client, rnd *rand.Rand, o Options) (*uploader, error) {
+	batchID, err := client.GetOrCreateMutableBatch(ctx, o.PostageTTL, o.PostageDepth, o.PostageLabel)
+	if err != nil {
+		return nil, fmt.Errorf("create batch: %w", err)
+	}
+
+	u := &uploader{client: client, batchID: batchID}
+
+	for _, pin := range []bool{true, false} {
+		f := bee.NewRandomFile(rnd, fmt.Sprintf("pinned-%t.bin", pin), o.FileSize)
+		if err := client.UploadFile(ctx, &f, api.UploadOptions{BatchID: batchID, Pin: pin}); err != nil {
+			return nil, fmt.Errorf("upload file: %w", err)
+		}
+		u.contents = append(u.contents, &content{name: "file", ref: f.Address(), pinned: pin})
+	}
+
+	archive, err := collection(rnd, o.FileSize)
+	if err != nil {
+		return nil, fmt.Errorf("create collection: %w", err)
+	}
+	f := bee.NewBufferFile("", archive)
+	if err := client.UploadCollection(ctx, &f, api.UploadOptions{BatchID: batchID, Pin: true}); err != nil {
+		return nil, fmt.Errorf("upload collection: %w", err)
+	}
+	u.contents = append(u.contents, &content{name: "collection", ref: f.Address(), pinned: true})
+
+	for _, ct := range u.contents {
+		if ct.chunks, err = chunks(ctx, client, ct.ref); err != nil {
+			return nil, fmt.Errorf("%s %s: %w", ct.name, ct.ref, err)
+		}
+		c.logger.Infof("node %s: uploaded %s %s of %d chunks, pinned: %t", client.Name(), ct.name, ct.ref, len(ct.chunks), ct.pinned)
+	}
+
+	return u, nil
+}
+
+// collection returns a TAR archive of two random files
+func collection(rnd *rand.Rand, size int64) (*bytes.Buffer, error) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, name := range []string{"index.html", "data/file.bin"} {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o600, Size: size, Typeflag: tar.TypeReg}); err != nil {
+			return nil, err
+		}
+		if _, err := io.CopyN(tw, rnd, size); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	return &buf, nil
+}
+
+// chunks traverses the content with the reference, including its manifests,
+// and returns addresses of all its chunks
+func chunks(ctx context.Context, client *bee.Client, ref swarm.Address) ([]swarm.Address, error) {
+	getter := storage.GetterFunc(func(ctx context.Context, addr swarm.Address) (swarm.Chunk, error) {
+		data, err := client.DownloadChunk(ctx, addr, "", nil)
+		if err != nil {
+			return nil, err
+		}
+		return swarm.NewChunk(addr, data), nil
+	})
+	// recovered chunks of redundancy encoded content are not stored
+	putter := storage.PutterFunc(func(context.Context, swarm.Chunk) error { return nil })
+
+	var addrs []swarm.Address
+	err := traversal.New(getter, putter, redundancy.DefaultLevel).Traverse(ctx, ref, func(addr swarm.Address) error {
+		addrs = append(addrs, addr)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("traverse: %w", err)
+	}
+	return addrs, nil
+}
+
+// pinned returns the contents that are pinned, or that are not
+func pinned(contents []*content, pinned bool) []*content {
+	var result []*content
+	for _, ct := range contents {
+		if ct.pinned == pinned {
+			result = append(result, ct)
+		}
+	}
+	return result
+}
+
+// checkPins verifies that the node reports exactly the pinned contents as
+// pinned
+func checkPins(ctx context.Context, client *bee.Client, contents []*content) error {
+	pins, err := client.GetPins(ctx)
+	if err != nil {
+		return fmt.Errorf("get pins: %w", err)
+	}
+
+	for _, ct := range contents {
+		if listed := slices.ContainsFunc(pins, ct.ref.Equal); listed != ct.pinned {
+			return fmt.Errorf("%s %s: listed in pins: %t, pinned: %t", ct.name, ct.ref, listed, ct.pinned)
+		}
+
+		ref, err := client.GetPinnedRootHash(ctx, ct.ref)
+		switch {
+		case !ct.pinned && api.IsHTTPStatusErrorCode(err, http.StatusNotFound):
+		case err != nil:
+			return fmt.Errorf("%s %s: get pinned root hash: %w", ct.name, ct.ref, err)
+		case !ct.pinned:
+			return fmt.Errorf("%s %s: reported as pinned", ct.name, ct.ref)
+		case !ref.Equal(ct.ref):
+			return fmt.Errorf("%s %s: got pinned root hash %s", ct.name, ct.ref, ref)
+		}
+	}
+
+	return nil
+}
+
+// checkStored verifies that the node stores all chunks of the contents
+func checkStored(ctx context.Context, client *bee.Client, contents []*content) error {
+	for _, ct := range contents {
+		_, count, err := client.HasChunks(ctx, ct.chunks)
+		if err != nil {
+			return fmt.Errorf("%s %s: has chunks: %w", ct.name, ct.ref, err)
+		}
+		if count != len(ct.chunks) {
+			return fmt.Errorf("%s %s: %d of %d chunks are not stored", ct.name, ct.ref, len(ct.chunks)-count, len(ct.chunks))
+		}
+	}
+	return nil
+}
+
+// fill uploads chunks within the storage radius of the nodes, so that they
+// are stored in their reserves and force eviction
+func (c *Check) fill(ctx context.Context, uploaders []*uploader, rnd *rand.Rand, o Options) error {
+	for _, u := range uploaders {
+		overlay, err := u.client.Overlay(ctx)
+		if err != nil {
+			return fmt.Errorf("node %s: overlay: %w", u.client.Name(), err)
+		}
+		rs, err := u.client.ReserveState(ctx)
+		if err != nil {
+			return fmt.Errorf("node %s: reserve state: %w", u.client.Name(), err)
+		}
+
+		for _, ch := range bee.GenerateNRandomChunksAt(rnd, overlay, o.FillChunks, rs.StorageRadius) {
+			if _, err := u.client.UploadChunk(ctx, ch.Data(), api.UploadOptions{BatchID: u.batchID}); err != nil {
+				return fmt.Errorf("node %s: upload chunk: %w", u.client.Name(), err)
+			}
+		}
+		c.logger.Infof("node %s: uploaded %d chunks at storage radius %d", u.client.Name(), o.FillChunks, rs.StorageRadius)
+	}
+	return nil
+}
+
+// waitEvicted waits until the node no longer stores all chunks of each of
+// the contents
+func (c *Check) waitEvicted(ctx context.Context, client *bee.Client, contents []*content, o Options) error {
+	timeout := time.NewTimer(o.GCTimeout)
+	defer timeout.Stop()
+
+	ticker := time.NewTicker(o.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		var stored []string
+		for _, ct := range contents {
+			_, count, err := client.HasChunks(ctx, ct.chunks)
+			if err != nil {
+				return fmt.Errorf("%s %s: has chunks: %w", ct.name, ct.ref, err)
+			}
+			if count == len(ct.chunks) {
+				stored = append(stored, fmt.Sprintf("%s %s", ct.name, ct.ref))
+				continue
+			}
+			c.logger.Debugf("node %s: %s %s: %d of %d chunks evicted", client.Name(), ct.name, ct.ref, len(ct.chunks)-count, len(ct.chunks))
+		}
+		if len(stored) == 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timeout.C:
+			return fmt.Errorf("unpinned content not evicted within %s: %v", o.GCTimeout, stored)
+		case <-ticker.C:
+		}
+	}
+}
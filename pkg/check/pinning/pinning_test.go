@@ -0,0 +1,86 @@
+package pinning_test
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/beekeeper/pkg/check/pinning"
+	"github.com/ethersphere/beekeeper/pkg/logging"
+	"github.com/ethersphere/beekeeper/pkg/orchestration"
+	"github.com/ethersphere/beekeeper/pkg/orchestration/fake"
+)
+
+func TestCheck(t *testing.T) {
+	ctx := context.Background()
+
+	cluster := fake.NewCluster("pinning", orchestration.ClusterOptions{}, nil)
+	defer cluster.Close()
+	if err := cluster.AddNodes(ctx, "bee", 2, orchestration.Config{FullNode: true, CacheCapacity: 40}); err != nil {
+		t.Fatalf("add nodes: %v", err)
+	}
+
+	opts := pinning.NewDefaultOptions()
+	opts.NodeCount = 2
+	opts.FileSize = 16 * 1024
+	opts.FillChunks = 80
+	opts.GCTimeout = time.Second
+	opts.PollInterval = 10 * time.Millisecond
+	opts.Seed = 1
+
+	check := pinning.NewCheck(logging.New(io.Discard, 0))
+	if err := check.Run(ctx, cluster, opts); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+}
+
+func TestCheckNotEvicted(t *testing.T) {
+	ctx := context.Background()
+
+	// nodes without a cache capacity never evict chunks
+	cluster := fake.NewCluster("pinning", orchestration.ClusterOptions{}, nil)
+	defer cluster.Close()
+	if err := cluster.AddNodes(ctx, "bee", 2, orchestration.Config{FullNode: true}); err != nil {
+		t.Fatalf("add nodes: %v", err)
+	}
+
+	opts := pinning.NewDefaultOptions()
+	opts.FileSize = 16 * 1024
+	opts.FillChunks = 10
+	opts.GCTimeout = 50 * time.Millisecond
+	opts.PollInterval = 10 * time.Millisecond
+	opts.Seed = 1
+
+	check := pinning.NewCheck(logging.New(io.Discard, 0))
+	err := check.Run(ctx, cluster, opts)
+	if err == nil || !strings.Contains(err.Error(), "not evicted") {
+		t.Fatalf("run: got error %v, want unpinned content not evicted", err)
+	}
+}
+
+func TestCheckNotStored(t *testing.T) {
+	ctx := context.Background()
+
+	// unpinned content evicted already during the upload must not be
+	// reported as evicted by the fill
+	cluster := fake.NewCluster("pinning", orchestration.ClusterOptions{}, nil)
+	defer cluster.Close()
+	if err := cluster.AddNodes(ctx, "bee", 2, orchestration.Config{FullNode: true, CacheCapacity: 2}); err != nil {
+		t.Fatalf("add nodes: %v", err)
+	}
+
+	opts := pinning.NewDefaultOptions()
+	opts.FileSize = 16 * 1024
+	opts.FillChunks = 10
+	opts.GCTimeout = 50 * time.Millisecond
+	opts.PollInterval = 10 * time.Millisecond
+	opts.Seed = 1
+
+	check := pinning.NewCheck(logging.New(io.Discard, 0))
+	err := check.Run(ctx, cluster, opts)
+	if err == nil || !strings.Contains(err.Error(), "are not stored") {
+		t.Fatalf("run: got error %v, want unpinned content not stored", err)
+	}
+}
@@ -0,0 +1,155 @@
+package check
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/ethersphere/beekeeper/pkg/config"
+	"github.com/ethersphere/beekeeper/pkg/report"
+	"gopkg.in/yaml.v3"
+)
+
+// PlanFormat represents the output format of a check plan
+type PlanFormat string
+
+const (
+	PlanFormatYAML PlanFormat = "yaml"
+	PlanFormatJSON PlanFormat = "json"
+)
+
+// ParsePlanFormat returns the PlanFormat for the given name
+func ParsePlanFormat(s string) (PlanFormat, error) {
+	switch f := PlanFormat(s); f {
+	case PlanFormatYAML, PlanFormatJSON:
+		return f, nil
+	default:
+		return "", fmt.Errorf("unsupported plan format %q: must be '%s' or '%s'", s, PlanFormatYAML, PlanFormatJSON)
+	}
+}
+
+// PlannedCheck represents a check with its fully resolved options
+type PlannedCheck struct {
+	Name          string         `yaml:"name" json:"name"`
+	Type          string         `yaml:"type" json:"type"`
+	DependsOn     []string       `yaml:"depends-on,omitempty" json:"depends_on,omitempty"`
+	ParallelGroup string         `yaml:"parallel-group,omitempty" json:"parallel_group,omitempty"`
+	Retries       int            `yaml:"retries,omitempty" json:"retries,omitempty"`
+	Timeout       *time.Duration `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+	// RandomSeed is set when the seed is drawn when the check is run, as it
+	// is set neither by the check nor by --seed, and the seed in options is
+	// then -1
+	RandomSeed bool `yaml:"random-seed,omitempty" json:"random_seed,omitempty"`
+	Options    any  `yaml:"options" json:"options"`
+}
+
+// Plan resolves options of the given checks in the same way as Run does,
+// without executing them. All checks are validated and every error found is
+// returned.
+func (c *CheckRunner) Plan(checks []string) ([]PlannedCheck, error) {
+	var (
+		planned []PlannedCheck
+		runs    []checkRun
+		errs    []string
+	)
+
+	for _, checkName := range checks {
+		checkName = strings.TrimSpace(checkName)
+		checkConfig, ok := c.checks[checkName]
+		if !ok {
+			errs = append(errs, fmt.Sprintf("check '%s' doesn't exist", checkName))
+			continue
+		}
+
+		checkType, ok := config.Checks[checkConfig.Type]
+		if !ok {
+			errs = append(errs, fmt.Sprintf("check %s: type %s not implemented", checkName, checkConfig.Type))
+			continue
+		}
+
+		variants, err := checkConfig.Variants(checkName)
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+
+		for _, v := range variants {
+			o, err := checkType.NewOptions(c.globalConfig, v.Check)
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("creating check %s options: %v", v.Name, err))
+				continue
+			}
+
+			// the seed drawn for the plan would not be used by the run
+			randomSeed := c.globalConfig.Seed < 0 && !v.Check.HasOption("seed") && report.SeedFromOptions(o) != nil
+			if randomSeed {
+				o = withSeed(o, -1)
+			}
+
+			planned = append(planned, PlannedCheck{
+				Name:          v.Name,
+				Type:          checkConfig.Type,
+				DependsOn:     checkConfig.DependsOn,
+				ParallelGroup: checkConfig.ParallelGroup,
+				Retries:       checkConfig.Retries,
+				Timeout:       checkConfig.Timeout,
+				RandomSeed:    randomSeed,
+				Options:       o,
+			})
+			runs = append(runs, checkRun{name: v.Name, check: checkName, dependsOn: checkConfig.DependsOn})
+		}
+	}
+
+	if _, err := buildGraph(runs); err != nil {
+		errs = append(errs, fmt.Sprintf("building check graph: %v", err))
+	}
+
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("invalid checks:\n%s", strings.Join(errs, "\n"))
+	}
+
+	return planned, nil
+}
+
+// withSeed returns a copy of the options with the seed
+func withSeed(o any, seed int64) any {
+	v := reflect.ValueOf(o)
+	ptr := v.Kind() == reflect.Pointer
+	if ptr {
+		v = v.Elem()
+	}
+
+	c := reflect.New(v.Type())
+	c.Elem().Set(v)
+	c.Elem().FieldByName("Seed").SetInt(seed)
+
+	if ptr {
+		return c.Interface()
+	}
+	return c.Elem().Interface()
+}
+
+// WritePlan writes planned checks to w in the given format
+func WritePlan(w io.Writer, planned []PlannedCheck, f PlanFormat) error {
+	switch f {
+	case PlanFormatYAML:
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(planned); err != nil {
+			return fmt.Errorf("encoding plan: %w", err)
+		}
+		return enc.Close()
+	case PlanFormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(planned); err != nil {
+			return fmt.Errorf("encoding plan: %w", err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported plan format %q", f)
+	}
+}
@@ -0,0 +1,85 @@
+package check
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/ethersphere/beekeeper/pkg/config"
+	"github.com/ethersphere/beekeeper/pkg/report"
+	"gopkg.in/yaml.v3"
+)
+
+func TestPlan(t *testing.T) {
+	var checks map[string]config.Check
+	if err := yaml.Unmarshal([]byte(`
+ci-pushsync:
+  type: pushsync
+  options:
+    chunks-per-node: 3
+ci-pushsync-seed:
+  type: pushsync
+  options:
+    seed: 7
+ci-broken:
+  type: pushsync
+  options:
+    chunks-per-node: many
+ci-unknown:
+  type: unknown
+`), &checks); err != nil {
+		t.Fatal(err)
+	}
+
+	runner := NewCheckRunner(config.CheckGlobalConfig{Seed: 42}, checks, nil, nil, nil, nil)
+
+	planned, err := runner.Plan([]string{"ci-pushsync", "ci-pushsync-seed"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := WritePlan(&buf, planned, PlanFormatYAML); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []struct {
+		Name    string         `yaml:"name"`
+		Options map[string]any `yaml:"options"`
+	}
+	if err := yaml.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d planned checks, want 2", len(got))
+	}
+	if got[0].Options["chunkspernode"] != 3 || got[0].Options["seed"] != 42 {
+		t.Fatalf("unexpected options: %v", got[0].Options)
+	}
+	if got[1].Options["seed"] != 7 {
+		t.Fatalf("got seed %v, want 7", got[1].Options["seed"])
+	}
+
+	// without the global seed, the seed is drawn when the check is run
+	random := NewCheckRunner(config.CheckGlobalConfig{Seed: -1}, checks, nil, nil, nil, nil)
+	randomPlanned, err := random.Plan([]string{"ci-pushsync", "ci-pushsync-seed"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !randomPlanned[0].RandomSeed || *report.SeedFromOptions(randomPlanned[0].Options) != -1 {
+		t.Fatalf("got random seed %t, options %+v, want random seed -1", randomPlanned[0].RandomSeed, randomPlanned[0].Options)
+	}
+	if randomPlanned[1].RandomSeed || *report.SeedFromOptions(randomPlanned[1].Options) != 7 {
+		t.Fatalf("got random seed %t, options %+v, want seed 7", randomPlanned[1].RandomSeed, randomPlanned[1].Options)
+	}
+
+	_, err = runner.Plan([]string{"ci-pushsync", "ci-broken", "ci-unknown"})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	for _, want := range []string{"ci-broken", "ci-unknown"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("error %q does not mention %s", err, want)
+		}
+	}
+}
@@ -62,13 +62,7 @@ func checkLightChunks(ctx context.Context, cluster orchestration.Cluster, o Opti
 			var ref swarm.Address
 
 			t0 := time.Now()
-			for range 3 {
-				ref, err = uploader.UploadChunk(ctx, chunk.Data(), api.UploadOptions{BatchID: batchID})
-				if err == nil {
-					break
-				}
-				time.Sleep(o.RetryDelay)
-			}
+			ref, err = uploader.UploadChunk(ctx, chunk.Data(), api.UploadOptions{BatchID: batchID})
 			if err != nil {
 				return fmt.Errorf("node %s: %w", nodeName, err)
 			}
@@ -88,13 +82,9 @@ func checkLightChunks(ctx context.Context, cluster orchestration.Cluster, o Opti
 			}
 			l.Infof("closest node %s overlay %s", closestName, closestAddress)
 
-			var synced bool
-			for range 3 {
-				synced, _ = clients[closestName].HasChunk(ctx, ref)
-				if synced {
-					break
-				}
-				time.Sleep(o.RetryDelay)
+			synced, err := clients[closestName].HasChunk(ctx, ref)
+			if err != nil {
+				return fmt.Errorf("node %s: %w", nodeName, err)
 			}
 			if !synced {
 				m.NotSyncedCounter.WithLabelValues(overlays[nodeName].String()).Inc()
@@ -21,8 +21,7 @@ type Options struct {
 	PostageTTL        time.Duration
 	PostageDepth      uint64
 	PostageLabel      string
-	Retries           int           // number of reties on problems
-	RetryDelay        time.Duration // retry delay duration
+	RetryDelay        time.Duration // delay before checking that the uploaded chunk is synced
 	Seed              int64
 	UploadNodeCount   int
 	ExcludeNodeGroups []string
@@ -37,7 +36,6 @@ func NewDefaultOptions() Options {
 		PostageTTL:        24 * time.Hour,
 		PostageDepth:      16,
 		PostageLabel:      "test-label",
-		Retries:           5,
 		RetryDelay:        1 * time.Second,
 		Seed:              random.Int64(),
 		UploadNodeCount:   1,
@@ -128,32 +126,18 @@ func (c *Check) defaultCheck(ctx context.Context, cluster orchestration.Cluster,
 			}
 			c.logger.Infof("closest node %s overlay %s", closestName, closestAddress)
 
-			checkRetryCount := 0
-
-			for {
-				checkRetryCount++
-				if checkRetryCount > o.Retries {
-					return fmt.Errorf("exceeded number of retries")
-				}
-
-				time.Sleep(o.RetryDelay)
-				node := clients[closestName]
-				synced, err := node.HasChunk(ctx, addr)
-				if err != nil {
-					return fmt.Errorf("node %s: %w", nodeName, err)
-				}
-				if !synced {
-					c.metrics.NotSyncedCounter.WithLabelValues(overlays[nodeName].String()).Inc()
-					c.logger.Infof("node %s overlay %s chunk %s not found on the closest node. retrying...", closestName, overlays[closestName], addr.String())
-					continue
-				}
-
-				c.metrics.SyncedCounter.WithLabelValues(overlays[nodeName].String()).Inc()
-				c.logger.Infof("node %s overlay %s chunk %s found on the closest node.", closestName, overlays[closestName], addr.String())
-
-				// check succeeded
-				break
+			time.Sleep(o.RetryDelay)
+			synced, err := clients[closestName].HasChunk(ctx, addr)
+			if err != nil {
+				return fmt.Errorf("node %s: %w", nodeName, err)
 			}
+			if !synced {
+				c.metrics.NotSyncedCounter.WithLabelValues(overlays[nodeName].String()).Inc()
+				return fmt.Errorf("node %s overlay %s chunk %s not found on the closest node", closestName, overlays[closestName], addr.String())
+			}
+
+			c.metrics.SyncedCounter.WithLabelValues(overlays[nodeName].String()).Inc()
+			c.logger.Infof("node %s overlay %s chunk %s found on the closest node.", closestName, overlays[closestName], addr.String())
 		}
 	}
 
@@ -0,0 +1,38 @@
+package pushsync_test
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/beekeeper/pkg/check/pushsync"
+	"github.com/ethersphere/beekeeper/pkg/logging"
+	"github.com/ethersphere/beekeeper/pkg/orchestration"
+	"github.com/ethersphere/beekeeper/pkg/orchestration/fake"
+)
+
+func TestCheck(t *testing.T) {
+	for _, mode := range []string{"default", "chunks"} {
+		t.Run(mode, func(t *testing.T) {
+			ctx := context.Background()
+
+			cluster := fake.NewCluster("pushsync", orchestration.ClusterOptions{}, nil)
+			defer cluster.Close()
+			if err := cluster.AddNodes(ctx, "bee", 4, orchestration.Config{FullNode: true}); err != nil {
+				t.Fatalf("add nodes: %v", err)
+			}
+
+			opts := pushsync.NewDefaultOptions()
+			opts.Mode = mode
+			opts.ChunksPerNode = 2
+			opts.RetryDelay = 10 * time.Millisecond
+			opts.Seed = 1
+
+			check := pushsync.NewCheck(logging.New(io.Discard, 0))
+			if err := check.Run(ctx, cluster, opts); err != nil {
+				t.Fatalf("run: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package redistribution
+
+import (
+	"io"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethersphere/beekeeper/pkg/bee/api"
+	"github.com/ethersphere/beekeeper/pkg/bigint"
+	"github.com/ethersphere/beekeeper/pkg/logging"
+)
+
+func TestCheckParticipation(t *testing.T) {
+	start := api.RedistributionState{Phase: "commit", Round: 10, Reward: bigint.Wrap(new(big.Int))}
+
+	for _, tc := range []struct {
+		name string
+		end  api.RedistributionState
+		err  string
+	}{
+		{
+			name: "played",
+			end:  api.RedistributionState{Phase: "reveal", Round: 13, LastSelectedRound: 12, LastPlayedRound: 12},
+		},
+		{
+			name: "selected for the next round",
+			end:  api.RedistributionState{Phase: "claim", Round: 13, LastSelectedRound: 14, LastPlayedRound: 12},
+		},
+		{
+			name: "selected in the commit phase",
+			end:  api.RedistributionState{Phase: "commit", Round: 13, LastSelectedRound: 13, LastPlayedRound: 12},
+		},
+		{
+			name: "not committed in the selected round",
+			end:  api.RedistributionState{Phase: "reveal", Round: 13, LastSelectedRound: 13, LastPlayedRound: 12},
+			err:  "selected in round 13, but last played in round 12",
+		},
+		{
+			name: "not played in a past selected round",
+			end:  api.RedistributionState{Phase: "claim", Round: 13, LastSelectedRound: 12, LastPlayedRound: 11},
+			err:  "selected in round 12, but last played in round 11",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c := &Check{logger: logging.New(io.Discard, 0)}
+
+			err := c.checkParticipation([]string{"bee-0"},
+				map[string]api.RedistributionState{"bee-0": start},
+				map[string]api.RedistributionState{"bee-0": tc.end},
+			)
+			switch {
+			case tc.err == "" && err != nil:
+				t.Fatalf("check participation: %v", err)
+			case tc.err != "" && (err == nil || !strings.Contains(err.Error(), tc.err)):
+				t.Fatalf("check participation: got error %v, want %q", err, tc.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,332 @@
+// Package redistribution checks the storage incentives redistribution game.
+// Full nodes are staked and observed for a number of rounds. Afterwards the
+// reserve commitments of nodes in the same neighborhood are sampled with the
+// same anchor and must agree, nodes must have played when their neighborhood
+// was selected, and winners must have received rewards.
+package redistribution
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ethersphere/bee/v2/pkg/swarm"
+	"github.com/ethersphere/beekeeper/pkg/bee"
+	"github.com/ethersphere/beekeeper/pkg/bee/api"
+	"github.com/ethersphere/beekeeper/pkg/beekeeper"
+	"github.com/ethersphere/beekeeper/pkg/logging"
+	"github.com/ethersphere/beekeeper/pkg/orchestration"
+	"github.com/ethersphere/beekeeper/pkg/random"
+)
+
+// Options represents check options
+type Options struct {
+	StakeAmount  *big.Int      // stake of every full node, nodes with less stake deposit the difference
+	Rounds       uint64        // number of rounds observed after staking
+	PollInterval time.Duration // interval of polling the redistribution state of nodes
+	Seed         int64         // seed of the anchors of reserve samples
+}
+
+// NewDefaultOptions returns new default options
+func NewDefaultOptions() Options {
+	return Options{
+		StakeAmount:  big.NewInt(100000000000000000),
+		Rounds:       3,
+		PollInterval: 30 * time.Second,
+		Seed:         0,
+	}
+}
+
+// compile check whether Check implements interface
+var _ beekeeper.Action = (*Check)(nil)
+
+// Check instance.
+type Check struct {
+	logger logging.Logger
+}
+
+// NewCheck returns a new check instance.
+func NewCheck(logger logging.Logger) beekeeper.Action {
+	return &Check{
+		logger: logger,
+	}
+}
+
+func (c *Check) Run(ctx context.Context, cluster orchestration.Cluster, opts any) (err error) {
+	o, ok := opts.(Options)
+	if !ok {
+		return fmt.Errorf("invalid options type")
+	}
+	if o.Rounds == 0 {
+		return errors.New("number of rounds must be positive")
+	}
+
+	clients, err := cluster.NodesClients(ctx)
+	if err != nil {
+		return fmt.Errorf("get clients: %w", err)
+	}
+
+	nodes := cluster.FullNodeNames()
+	if len(nodes) == 0 {
+		return errors.New("no full nodes in the cluster")
+	}
+	slices.Sort(nodes)
+
+	for _, node := range nodes {
+		if err := c.stake(ctx, clients[node], o.StakeAmount); err != nil {
+			return fmt.Errorf("node %s: %w", node, err)
+		}
+	}
+
+	start, err := states(ctx, clients, nodes)
+	if err != nil {
+		return err
+	}
+
+	end, err := c.waitRounds(ctx, clients, nodes, start, o)
+	if err != nil {
+		return err
+	}
+
+	if err := c.checkReserveCommitments(ctx, clients, nodes, o.Seed); err != nil {
+		return err
+	}
+
+	return c.checkParticipation(nodes, start, end)
+}
+
+// stake deposits the difference between the amount and the node's stake
+func (c *Check) stake(ctx context.Context, client *bee.Client, amount *big.Int) error {
+	staked, err := client.GetStake(ctx)
+	if err != nil {
+		return fmt.Errorf("get stake amount: %w", err)
+	}
+
+	if staked.Cmp(amount) >= 0 {
+		c.logger.Infof("node %s: staked %d", client.Name(), staked)
+		return nil
+	}
+
+	deposit := new(big.Int).Sub(amount, staked)
+	if _, err := client.DepositStake(ctx, deposit); err != nil {
+		return fmt.Errorf("deposit stake %d: %w", deposit, err)
+	}
+	c.logger.Infof("node %s: deposited stake %d", client.Name(), deposit)
+
+	return nil
+}
+
+// states returns redistribution states of the nodes
+func states(ctx context.Context, clients map[string]*bee.Client, nodes []string) (map[string]api.RedistributionState, error) {
+	states := make(map[string]api.RedistributionState, len(nodes))
+	for _, node := range nodes {
+		state, err := clients[node].RedistributionState(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("node %s: redistribution state: %w", node, err)
+		}
+		states[node] = state
+	}
+	return states, nil
+}
+
+// waitRounds waits until every node reports that the number of rounds has
+// passed since the start states, and returns their last states
+func (c *Check) waitRounds(ctx context.Context, clients map[string]*bee.Client, nodes []string, start map[string]api.RedistributionState, o Options) (map[string]api.RedistributionState, error) {
+	c.logger.Infof("waiting for %d rounds to pass", o.Rounds)
+
+	ticker := time.NewTicker(o.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("waiting for %d rounds: %w", o.Rounds, ctx.Err())
+		case <-ticker.C:
+		}
+
+		current, err := states(ctx, clients, nodes)
+		if err != nil {
+			return nil, err
+		}
+
+		passed := true
+		for _, node := range nodes {
+			if current[node].Round < start[node].Round+o.Rounds {
+				passed = false
+				break
+			}
+		}
+		if passed {
+			return current, nil
+		}
+
+		c.logger.Debugf("round %d, phase %s", current[nodes[0]].Round, current[nodes[0]].Phase)
+	}
+}
+
+// checkReserveCommitments samples reserves of nodes in the same neighborhood
+// with the same anchor and verifies that their reserve commitment hashes
+// agree
+func (c *Check) checkReserveCommitments(ctx context.Context, clients map[string]*bee.Client, nodes []string, seed int64) error {
+	neighborhoods, err := neighborhoods(ctx, clients, nodes)
+	if err != nil {
+		return err
+	}
+
+	rnd := random.PseudoGenerator(seed)
+	anchor := make([]byte, swarm.HashSize)
+
+	compared := 0
+	for _, n := range neighborhoods {
+		if len(n.nodes) < 2 {
+			continue
+		}
+		_, _ = rnd.Read(anchor)
+
+		var first api.RCHashResponse
+		for i, node := range n.nodes {
+			resp, err := clients[node].RCHash(ctx, n.depth, anchor, anchor)
+			if err != nil {
+				return fmt.Errorf("node %s: reserve commitment hash: %w", node, err)
+			}
+			c.logger.Infof("neighborhood %s: node %s reserve commitment hash %s", n.prefix, node, resp.Hash)
+
+			if i == 0 {
+				first = resp
+				continue
+			}
+			if !resp.Hash.Equal(first.Hash) {
+				return fmt.Errorf("neighborhood %s: node %s reserve commitment hash %s does not match node %s hash %s", n.prefix, node, resp.Hash, n.nodes[0], first.Hash)
+			}
+		}
+		compared++
+	}
+
+	if compared == 0 {
+		c.logger.Warning("no neighborhood with more than one node, reserve commitments not compared")
+	}
+
+	return nil
+}
+
+// neighborhood represents nodes that share the first depth bits of their
+// overlays
+type neighborhood struct {
+	prefix string
+	depth  uint8
+	nodes  []string
+}
+
+// neighborhoods groups the nodes into neighborhoods by their storage radius
+// and overlay, ordered by prefix
+func neighborhoods(ctx context.Context, clients map[string]*bee.Client, nodes []string) ([]neighborhood, error) {
+	byPrefix := make(map[string]*neighborhood)
+	for _, node := range nodes {
+		client := clients[node]
+
+		overlay, err := client.Overlay(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("node %s: overlay: %w", node, err)
+		}
+		rs, err := client.ReserveState(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("node %s: reserve state: %w", node, err)
+		}
+
+		prefix := bitPrefix(overlay, rs.StorageRadius)
+		n, ok := byPrefix[prefix]
+		if !ok {
+			n = &neighborhood{prefix: prefix, depth: rs.StorageRadius}
+			byPrefix[prefix] = n
+		}
+		n.nodes = append(n.nodes, node)
+	}
+
+	result := make([]neighborhood, 0, len(byPrefix))
+	for _, n := range byPrefix {
+		result = append(result, *n)
+	}
+	slices.SortFunc(result, func(a, b neighborhood) int { return strings.Compare(a.prefix, b.prefix) })
+
+	return result, nil
+}
+
+// bitPrefix returns the first depth bits of the overlay, prefixed by the
+// depth so that neighborhoods of nodes with different radius do not mix
+func bitPrefix(overlay swarm.Address, depth uint8) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d/", depth)
+	b := overlay.Bytes()
+	for i := range int(depth) {
+		if b[i/8]&(0x80>>(i%8)) != 0 {
+			sb.WriteByte('1')
+		} else {
+			sb.WriteByte('0')
+		}
+	}
+	return sb.String()
+}
+
+// checkParticipation verifies that nodes played in the rounds in which their
+// neighborhood was selected, that at least one node played, that no node
+// was frozen, and that winners received rewards
+func (c *Check) checkParticipation(nodes []string, start, end map[string]api.RedistributionState) error {
+	var (
+		errs    []error
+		played  int
+		winners []string
+	)
+
+	for _, node := range nodes {
+		s, e := start[node], end[node]
+
+		if e.IsFrozen {
+			errs = append(errs, fmt.Errorf("node %s: frozen since round %d", node, e.LastFrozenRound))
+		}
+
+		if e.LastSelectedRound > s.Round && committed(e) && e.LastPlayedRound < e.LastSelectedRound {
+			errs = append(errs, fmt.Errorf("node %s: selected in round %d, but last played in round %d", node, e.LastSelectedRound, e.LastPlayedRound))
+		}
+
+		if e.LastPlayedRound > s.Round {
+			played++
+		}
+
+		if e.LastWonRound > s.Round {
+			winners = append(winners, node)
+			if reward(e).Cmp(reward(s)) <= 0 {
+				errs = append(errs, fmt.Errorf("node %s: won round %d, but reward did not increase from %d", node, e.LastWonRound, reward(s)))
+			}
+		}
+
+		c.logger.Infof("node %s: last played round %d, last won round %d, reward %d", node, e.LastPlayedRound, e.LastWonRound, reward(e))
+	}
+
+	if played == 0 {
+		errs = append(errs, errors.New("no node played in the observed rounds"))
+	}
+
+	c.logger.Infof("%d of %d nodes played, winners: %s", played, len(nodes), strings.Join(winners, ", "))
+
+	return errors.Join(errs...)
+}
+
+// committed returns whether the commit phase of the last round in which the
+// node was selected is over. Bee reports the round following the current one
+// as selected already while sampling in the claim phase, before the node can
+// play in it.
+func committed(s api.RedistributionState) bool {
+	return s.LastSelectedRound < s.Round || s.LastSelectedRound == s.Round && s.Phase != "commit"
+}
+
+// reward returns the reward of the state, zero if not reported
+func reward(s api.RedistributionState) *big.Int {
+	if s.Reward == nil || s.Reward.Int == nil {
+		return new(big.Int)
+	}
+	return s.Reward.Int
+}
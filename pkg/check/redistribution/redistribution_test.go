@@ -0,0 +1,77 @@
+package redistribution_test
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/v2/pkg/cac"
+	"github.com/ethersphere/beekeeper/pkg/bee/api"
+	"github.com/ethersphere/beekeeper/pkg/check/redistribution"
+	"github.com/ethersphere/beekeeper/pkg/logging"
+	"github.com/ethersphere/beekeeper/pkg/orchestration"
+	"github.com/ethersphere/beekeeper/pkg/orchestration/fake"
+)
+
+func TestCheck(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cluster := fake.NewCluster("redistribution", orchestration.ClusterOptions{}, nil)
+	defer cluster.Close()
+	if err := cluster.AddNodes(ctx, "bee", 3, orchestration.Config{FullNode: true}); err != nil {
+		t.Fatalf("add nodes: %v", err)
+	}
+
+	opts := redistribution.NewDefaultOptions()
+	opts.PollInterval = 5 * time.Millisecond
+	opts.Seed = 1
+
+	check := redistribution.NewCheck(logging.New(io.Discard, 0))
+	if err := check.Run(ctx, cluster, opts); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+}
+
+func TestCheckReserveMismatch(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cluster := fake.NewCluster("redistribution", orchestration.ClusterOptions{}, nil)
+	defer cluster.Close()
+	if err := cluster.AddNodes(ctx, "bee", 3, orchestration.Config{FullNode: true}); err != nil {
+		t.Fatalf("add nodes: %v", err)
+	}
+
+	// a pushed chunk is stored only by the closest nodes, so the reserve of
+	// the remaining node differs
+	clients, err := cluster.NodesClients(ctx)
+	if err != nil {
+		t.Fatalf("clients: %v", err)
+	}
+	client := clients[cluster.FullNodeNames()[0]]
+	batchID, err := client.CreatePostageBatch(ctx, 1000, 17, "redistribution", false)
+	if err != nil {
+		t.Fatalf("create batch: %v", err)
+	}
+	ch, err := cac.New([]byte("redistribution"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.UploadChunk(ctx, ch.Data(), api.UploadOptions{BatchID: batchID}); err != nil {
+		t.Fatalf("upload chunk: %v", err)
+	}
+
+	opts := redistribution.NewDefaultOptions()
+	opts.Rounds = 1
+	opts.PollInterval = 5 * time.Millisecond
+	opts.Seed = 1
+
+	check := redistribution.NewCheck(logging.New(io.Discard, 0))
+	err = check.Run(ctx, cluster, opts)
+	if err == nil || !strings.Contains(err.Error(), "does not match") {
+		t.Fatalf("run: got error %v, want reserve commitment mismatch", err)
+	}
+}
@@ -0,0 +1,32 @@
+package retrieval_test
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/ethersphere/beekeeper/pkg/check/retrieval"
+	"github.com/ethersphere/beekeeper/pkg/logging"
+	"github.com/ethersphere/beekeeper/pkg/orchestration"
+	"github.com/ethersphere/beekeeper/pkg/orchestration/fake"
+)
+
+func TestCheck(t *testing.T) {
+	ctx := context.Background()
+
+	cluster := fake.NewCluster("retrieval", orchestration.ClusterOptions{}, nil)
+	defer cluster.Close()
+	if err := cluster.AddNodes(ctx, "bee", 4, orchestration.Config{FullNode: true}); err != nil {
+		t.Fatalf("add nodes: %v", err)
+	}
+
+	opts := retrieval.NewDefaultOptions()
+	opts.ChunksPerNode = 3
+	opts.UploadNodeCount = 2
+	opts.Seed = 1
+
+	check := retrieval.NewCheck(logging.New(io.Discard, 0))
+	if err := check.Run(ctx, cluster, opts); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+}
@@ -2,48 +2,127 @@ package check
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/ethersphere/beekeeper/pkg/artifacts"
 	"github.com/ethersphere/beekeeper/pkg/beekeeper"
 	"github.com/ethersphere/beekeeper/pkg/config"
+	"github.com/ethersphere/beekeeper/pkg/history"
 	"github.com/ethersphere/beekeeper/pkg/logging"
-	"github.com/ethersphere/beekeeper/pkg/metrics"
+	m "github.com/ethersphere/beekeeper/pkg/metrics"
 	"github.com/ethersphere/beekeeper/pkg/orchestration"
+	"github.com/ethersphere/beekeeper/pkg/report"
+	"github.com/ethersphere/beekeeper/pkg/status"
 	"github.com/opentracing/opentracing-go"
-	"github.com/prometheus/client_golang/prometheus/push"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// beeVersionsTimeout limits the duration of fetching Bee versions of
+	// nodes for the run history.
+	beeVersionsTimeout = 30 * time.Second
+	// artifactsTimeout limits the duration of collecting diagnostics of a
+	// failed check.
+	artifactsTimeout = 2 * time.Minute
 )
 
 type CheckRunner struct {
-	globalConfig  config.CheckGlobalConfig
-	checks        map[string]config.Check
-	cluster       orchestration.Cluster
-	metricsPusher *push.Pusher
-	tracer        opentracing.Tracer
-	logger        logging.Logger
+	globalConfig    config.CheckGlobalConfig
+	checks          map[string]config.Check
+	cluster         orchestration.Cluster
+	metricsRegistry prometheus.Registerer
+	tracer          opentracing.Tracer
+	logger          logging.Logger
+	maxParallel     int
+	skipTeardown    bool
+	history         *history.Store
+	tracker         *status.Tracker
+	artifacts       *artifacts.Collector
+	actions         map[string]beekeeper.Action // by check variant, reused across runs
+	metrics         metrics
+	report          *report.Report // set after checks are executed
+}
+
+// CheckRunnerOption holds optional parameters for the CheckRunner.
+type CheckRunnerOption func(*CheckRunner)
+
+// WithMaxParallel sets the maximum number of checks that are executed
+// concurrently. Values lower than 1 are ignored.
+func WithMaxParallel(maxParallel int) CheckRunnerOption {
+	return func(c *CheckRunner) {
+		if maxParallel > 0 {
+			c.maxParallel = maxParallel
+		}
+	}
+}
+
+// WithSkipTeardown disables calling teardown hooks of checks, keeping the
+// state they created in the cluster for debugging.
+func WithSkipTeardown(skipTeardown bool) CheckRunnerOption {
+	return func(c *CheckRunner) {
+		c.skipTeardown = skipTeardown
+	}
+}
+
+// WithHistory stores the result of every run in the history store.
+func WithHistory(store *history.Store) CheckRunnerOption {
+	return func(c *CheckRunner) {
+		c.history = store
+	}
+}
+
+// WithArtifacts collects a diagnostics bundle of the cluster whenever a check
+// fails.
+func WithArtifacts(collector *artifacts.Collector) CheckRunnerOption {
+	return func(c *CheckRunner) {
+		c.artifacts = collector
+	}
+}
+
+// WithTracker reports running checks and their results to the tracker.
+func WithTracker(tracker *status.Tracker) CheckRunnerOption {
+	return func(c *CheckRunner) {
+		c.tracker = tracker
+	}
 }
 
 func NewCheckRunner(
 	globalConfig config.CheckGlobalConfig,
 	checks map[string]config.Check,
 	cluster orchestration.Cluster,
-	metricsPusher *push.Pusher,
+	metricsRegistry prometheus.Registerer,
 	tracer opentracing.Tracer,
 	logger logging.Logger,
+	opts ...CheckRunnerOption,
 ) *CheckRunner {
 	if logger == nil {
 		logger = logging.New(io.Discard, 0)
 	}
-	return &CheckRunner{
-		globalConfig:  globalConfig,
-		checks:        checks,
-		cluster:       cluster,
-		metricsPusher: metricsPusher,
-		tracer:        tracer,
-		logger:        logger,
+	c := &CheckRunner{
+		globalConfig:    globalConfig,
+		checks:          checks,
+		cluster:         cluster,
+		metricsRegistry: metricsRegistry,
+		tracer:          tracer,
+		logger:          logger,
+		maxParallel:     1,
+		actions:         make(map[string]beekeeper.Action),
+		metrics:         newMetrics(),
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
+	if metricsRegistry != nil {
+		if err := m.RegisterCollectors(metricsRegistry, m.PrometheusCollectorsFromFields(c.metrics)...); err != nil {
+			c.logger.Warningf("registering check runner metrics: %v", err)
+		}
+	}
+	return c
 }
 
 func (c *CheckRunner) Run(ctx context.Context, checks []string) error {
@@ -68,81 +147,414 @@ func (c *CheckRunner) Run(ctx context.Context, checks []string) error {
 			return fmt.Errorf("check %s not implemented", checkConfig.Type)
 		}
 
-		// create check options
-		o, err := checkType.NewOptions(c.globalConfig, checkConfig)
-		if err != nil {
-			return fmt.Errorf("creating check %s options: %w", checkName, err)
+		// validate dependencies
+		for _, dep := range checkConfig.DependsOn {
+			if _, ok := c.checks[dep]; !ok {
+				return fmt.Errorf("check '%s' depends on check '%s' which doesn't exist", checkName, dep)
+			}
+			if !slices.ContainsFunc(checks, func(s string) bool { return strings.TrimSpace(s) == dep }) {
+				c.logger.Warningf("check '%s' depends on check '%s' which is not selected, ignoring dependency", checkName, dep)
+			}
 		}
 
-		// create check action
-		chk := checkType.NewAction(c.logger)
-		if r, ok := chk.(metrics.Reporter); ok && c.metricsPusher != nil {
-			metrics.RegisterCollectors(c.metricsPusher, r.Report()...)
+		// expand matrix into variants
+		variants, err := checkConfig.Variants(checkName)
+		if err != nil {
+			return err
 		}
-		chk = beekeeper.NewActionMiddleware(c.tracer, chk, checkName)
 
-		// append to validated checks
-		validatedChecks = append(validatedChecks, checkRun{
-			name:     checkName,
-			typeName: checkConfig.Type,
-			action:   chk,
-			options:  o,
-			timeout:  checkConfig.Timeout,
-		})
+		for _, v := range variants {
+			// create check options
+			o, err := checkType.NewOptions(c.globalConfig, v.Check)
+			if err != nil {
+				return fmt.Errorf("creating check %s options: %w", v.Name, err)
+			}
+
+			// create check action
+			chk := beekeeper.NewActionMiddleware(c.tracer, c.action(v.Name, checkConfig.Type, checkType), v.Name)
+
+			// append to validated checks
+			validatedChecks = append(validatedChecks, checkRun{
+				name:          v.Name,
+				check:         checkName,
+				typeName:      checkConfig.Type,
+				action:        chk,
+				options:       o,
+				timeout:       checkConfig.Timeout,
+				retries:       checkConfig.Retries,
+				retryDelay:    checkConfig.RetryDelay,
+				dependsOn:     checkConfig.DependsOn,
+				parallelGroup: checkConfig.ParallelGroup,
+				skipTeardown:  c.skipTeardown,
+			})
+		}
 	}
 
-	checkResults := make([]checkResult, 0, len(validatedChecks))
-	hasFailures := false
+	graph, err := buildGraph(validatedChecks)
+	if err != nil {
+		return fmt.Errorf("building check graph: %w", err)
+	}
 
-	// run checks
-	for _, check := range validatedChecks {
-		c.logger.WithFields(map[string]any{
-			"type":    check.typeName,
-			"options": fmt.Sprintf("%+v", check.options),
-		}).Infof("running check: %s", check.name)
+	start := time.Now()
+	checkResults := c.runGraph(ctx, graph)
 
-		err := check.Run(ctx, c.cluster)
-		if err != nil {
-			hasFailures = true
-			c.logger.WithFields(map[string]any{
-				"type":  check.typeName,
-				"error": err,
-			}).Errorf("'%s' check failed", check.name)
-		} else {
-			c.logger.WithField("type", check.typeName).Infof("'%s' check completed successfully", check.name)
-		}
+	c.report = &report.Report{
+		Name:      "check",
+		Cluster:   clusterName(c.cluster),
+		Seed:      c.globalConfig.Seed,
+		Timestamp: start,
+		Duration:  time.Since(start),
+	}
+	for _, r := range checkResults {
+		c.report.Results = append(c.report.Results, r.reportResult())
+	}
 
-		// append check result
-		checkResults = append(checkResults, checkResult{
-			check:     check.name,
-			err:       err,
-			timestamp: time.Now(),
-		})
+	if c.history != nil {
+		c.saveHistory(ctx)
 	}
 
-	if hasFailures {
+	failed, _, _ := c.report.Counts()
+	c.metrics.LastRunDuration.Set(c.report.Duration.Seconds())
+	c.metrics.LastRunFailedChecks.Set(float64(failed))
+	c.metrics.LastRunTimestamp.SetToCurrentTime()
+
+	if slices.ContainsFunc(checkResults, func(r checkResult) bool { return r.err != nil }) {
+		c.metrics.Runs.WithLabelValues("failure").Inc()
 		return formatErrorReport(checkResults)
 	}
 
+	c.metrics.Runs.WithLabelValues("success").Inc()
+
 	c.logger.WithField("total_checks", len(checkResults)).Info("All checks completed successfully")
 	return nil
 }
 
+// action returns the action of the check variant. Actions are created once
+// per variant, so that variants of a matrix do not share metrics, and their
+// collectors are registered with the check label of the variant once, even
+// if Run is called repeatedly.
+func (c *CheckRunner) action(name, typeName string, checkType config.CheckType) beekeeper.Action {
+	if a, ok := c.actions[name]; ok {
+		return a
+	}
+
+	a := checkType.NewAction(c.logger)
+	if r, ok := a.(m.Reporter); ok && c.metricsRegistry != nil {
+		registry := prometheus.WrapRegistererWith(prometheus.Labels{"check": name}, c.metricsRegistry)
+		if err := m.RegisterCollectors(registry, r.Report()...); err != nil {
+			c.logger.Warningf("registering %s check metrics of %s: %v", typeName, name, err)
+		}
+	}
+	c.actions[name] = a
+
+	return a
+}
+
+// Report returns the report of the last Run. It is nil if no checks were
+// executed.
+func (c *CheckRunner) Report() *report.Report {
+	return c.report
+}
+
+// saveHistory stores the report of the last run together with Bee versions of
+// the cluster nodes. Failures are logged, as they must not fail the run.
+func (c *CheckRunner) saveHistory(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), beeVersionsTimeout)
+	defer cancel()
+
+	run := history.NewRun(c.report, c.beeVersions(ctx))
+	if err := c.history.Add(&run); err != nil {
+		c.logger.Errorf("storing run history: %v", err)
+		return
+	}
+
+	c.logger.Infof("run stored in history with id %d", run.ID)
+}
+
+// beeVersions returns Bee versions of the cluster nodes by node name. Nodes
+// whose version can not be fetched are omitted.
+func (c *CheckRunner) beeVersions(ctx context.Context) map[string]string {
+	if c.cluster == nil {
+		return nil
+	}
+
+	clients, err := c.cluster.NodesClients(ctx)
+	if err != nil {
+		c.logger.Warningf("fetching bee versions: %v", err)
+		return nil
+	}
+
+	versions := make(map[string]string, len(clients))
+	for name, client := range clients {
+		version, err := client.Version(ctx)
+		if err != nil {
+			c.logger.Warningf("fetching bee version of node %s: %v", name, err)
+			continue
+		}
+		versions[name] = version
+	}
+
+	return versions
+}
+
+// runGraph executes checks respecting their dependencies, running at most
+// maxParallel checks at once and never two checks from the same parallel group
+// at the same time. Checks whose dependencies did not succeed are skipped.
+// Results are returned in the order of the given nodes.
+func (c *CheckRunner) runGraph(ctx context.Context, nodes []checkNode) []checkResult {
+	const (
+		statePending = iota
+		stateRunning
+		stateDone
+	)
+
+	results := make([]checkResult, len(nodes))
+	state := make([]int, len(nodes))
+	busyGroups := make(map[string]bool)
+	doneC := make(chan int)
+	running, done := 0, 0
+
+	for done < len(nodes) {
+		for scheduled := true; scheduled; {
+			scheduled = false
+			for i, n := range nodes {
+				if state[i] != statePending {
+					continue
+				}
+
+				ready, failedDep := true, ""
+				for _, d := range n.deps {
+					if state[d] != stateDone {
+						ready = false
+					} else if results[d].err != nil && failedDep == "" {
+						failedDep = nodes[d].run.name
+					}
+				}
+
+				if failedDep != "" {
+					c.logger.WithField("type", n.run.typeName).Warningf("skipping '%s' check as its dependency '%s' did not succeed", n.run.name, failedDep)
+					results[i] = checkResult{
+						check:     n.run.name,
+						typeName:  n.run.typeName,
+						options:   n.run.options,
+						err:       fmt.Errorf("%w: dependency '%s' did not succeed", errSkipped, failedDep),
+						timestamp: time.Now(),
+					}
+					c.metrics.CheckOutcomes.WithLabelValues(n.run.name, n.run.typeName, string(report.StatusSkipped)).Inc()
+					if c.tracker != nil {
+						c.tracker.Start(status.KindCheck, n.run.name, n.run.typeName)(results[i].reportResult())
+					}
+					state[i] = stateDone
+					done++
+					scheduled = true
+					continue
+				}
+
+				if !ready || running >= c.maxParallel || (n.run.parallelGroup != "" && busyGroups[n.run.parallelGroup]) {
+					continue
+				}
+
+				state[i] = stateRunning
+				running++
+				if n.run.parallelGroup != "" {
+					busyGroups[n.run.parallelGroup] = true
+				}
+
+				go func(i int, check checkRun) {
+					results[i] = c.runCheck(ctx, check)
+					doneC <- i
+				}(i, n.run)
+			}
+		}
+
+		if running == 0 {
+			break
+		}
+
+		i := <-doneC
+		state[i] = stateDone
+		running--
+		done++
+		if g := nodes[i].run.parallelGroup; g != "" {
+			delete(busyGroups, g)
+		}
+	}
+
+	return results
+}
+
+// runCheck executes a single check and logs its outcome.
+func (c *CheckRunner) runCheck(ctx context.Context, check checkRun) (result checkResult) {
+	c.logger.WithFields(map[string]any{
+		"type":    check.typeName,
+		"options": fmt.Sprintf("%+v", check.options),
+	}).Infof("running check: %s", check.name)
+
+	if c.tracker != nil {
+		finish := c.tracker.Start(status.KindCheck, check.name, check.typeName)
+		defer func() { finish(result.reportResult()) }()
+	}
+
+	start := time.Now()
+	attempts, err := check.Run(ctx, c.cluster, c.logger)
+	duration := time.Since(start)
+	switch {
+	case err != nil:
+		c.logger.WithFields(map[string]any{
+			"type":     check.typeName,
+			"attempts": attempts,
+			"error":    err,
+		}).Errorf("'%s' check failed", check.name)
+	case attempts > 1:
+		c.logger.WithFields(map[string]any{
+			"type":     check.typeName,
+			"attempts": attempts,
+		}).Warningf("'%s' check completed successfully after retrying, marking it as flaky", check.name)
+	default:
+		c.logger.WithField("type", check.typeName).Infof("'%s' check completed successfully", check.name)
+	}
+
+	result = checkResult{
+		check:     check.name,
+		typeName:  check.typeName,
+		options:   check.options,
+		err:       err,
+		attempts:  attempts,
+		duration:  duration,
+		timestamp: time.Now(),
+	}
+
+	if err != nil && c.artifacts != nil {
+		result.artifact = c.collectArtifacts(ctx, check.name, err)
+	}
+
+	c.metrics.CheckAttempts.WithLabelValues(check.name, check.typeName).Add(float64(attempts))
+	c.metrics.CheckOutcomes.WithLabelValues(check.name, check.typeName, string(result.status())).Inc()
+
+	return result
+}
+
+// collectArtifacts collects the diagnostics bundle of the failed check and
+// returns its path. Failures are only logged, not to hide the check error.
+func (c *CheckRunner) collectArtifacts(ctx context.Context, name string, cause error) string {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), artifactsTimeout)
+	defer cancel()
+
+	path, err := c.artifacts.Collect(ctx, name, cause)
+	if err != nil {
+		c.logger.Errorf("collecting artifacts of '%s' check: %v", name, err)
+		return ""
+	}
+
+	c.logger.Infof("artifacts of '%s' check written to %s", name, path)
+	return path
+}
+
+func clusterName(cluster orchestration.Cluster) string {
+	if cluster == nil {
+		return ""
+	}
+	return cluster.Name()
+}
+
 type checkRun struct {
-	name     string
-	typeName string
-	action   beekeeper.Action
-	options  any
-	timeout  *time.Duration
+	name          string
+	check         string // configured check name, differs from name for matrix variants
+	typeName      string
+	action        beekeeper.Action
+	options       any
+	timeout       *time.Duration
+	retries       int
+	retryDelay    time.Duration
+	dependsOn     []string
+	parallelGroup string
+	skipTeardown  bool
+}
+
+// checkName returns the name of the configured check the run belongs to
+func (c *checkRun) checkName() string {
+	if c.check != "" {
+		return c.check
+	}
+	return c.name
+}
+
+// Run executes the check action, retrying it with a fresh context up to the
+// configured number of retries. If the action implements setup and teardown
+// hooks, setup is called once before the first attempt and teardown once
+// after the last one, even if the check failed or timed out. It returns the
+// number of attempts made and the error of the last attempt.
+func (c *checkRun) Run(ctx context.Context, cluster orchestration.Cluster, logger logging.Logger) (attempts int, err error) {
+	defer c.teardown(ctx, cluster, logger)
+
+	if a, ok := beekeeper.AsSetupAction(c.action); ok {
+		if err := runWithTimeout(ctx, c.timeout, func(ctx context.Context) error {
+			return a.Setup(ctx, cluster, c.options)
+		}); err != nil {
+			return 1, fmt.Errorf("setup: %w", err)
+		}
+	}
+
+	for {
+		attempts++
+		if err = runWithTimeout(ctx, c.timeout, func(ctx context.Context) error {
+			return c.action.Run(ctx, cluster, c.options)
+		}); err == nil || attempts > c.retries || ctx.Err() != nil {
+			return attempts, err
+		}
+
+		logger.WithFields(map[string]any{
+			"type":    c.typeName,
+			"attempt": attempts,
+			"error":   err,
+		}).Warningf("'%s' check failed, retrying in %s", c.name, c.retryDelay)
+
+		select {
+		case <-ctx.Done():
+			return attempts, err
+		case <-time.After(c.retryDelay):
+		}
+	}
+}
+
+// teardown calls the teardown hook of the action, if it has one. It runs with
+// a context that is not canceled with the parent, so that state is cleaned up
+// after a timeout as well. Teardown errors are logged and do not fail the check.
+func (c *checkRun) teardown(ctx context.Context, cluster orchestration.Cluster, logger logging.Logger) {
+	a, ok := beekeeper.AsTeardownAction(c.action)
+	if !ok {
+		return
+	}
+
+	if c.skipTeardown {
+		logger.WithField("type", c.typeName).Infof("skipping teardown of '%s' check", c.name)
+		return
+	}
+
+	timeout := beekeeper.DefaultTeardownTimeout
+	if c.timeout != nil {
+		timeout = *c.timeout
+	}
+
+	if err := runWithTimeout(context.WithoutCancel(ctx), &timeout, func(ctx context.Context) error {
+		return a.Teardown(ctx, cluster, c.options)
+	}); err != nil {
+		logger.WithFields(map[string]any{
+			"type":  c.typeName,
+			"error": err,
+		}).Errorf("'%s' check teardown failed", c.name)
+	}
 }
 
-func (c *checkRun) Run(ctx context.Context, cluster orchestration.Cluster) error {
-	checkCtx, cancelCheck := createChildContext(ctx, c.timeout)
+// runWithTimeout calls fn with a child context that expires after the
+// optional timeout and returns as soon as the context is done.
+func runWithTimeout(ctx context.Context, timeout *time.Duration, fn func(ctx context.Context) error) error {
+	checkCtx, cancelCheck := createChildContext(ctx, timeout)
 	defer cancelCheck()
 
 	ch := make(chan error, 1)
 	go func() {
-		ch <- c.action.Run(checkCtx, cluster, c.options)
+		ch <- fn(checkCtx)
 		close(ch)
 	}()
 
@@ -167,12 +579,49 @@ func createChildContext(ctx context.Context, timeout *time.Duration) (context.Co
 
 type checkResult struct {
 	check     string
+	typeName  string
+	options   any
 	err       error
+	attempts  int
+	duration  time.Duration
 	timestamp time.Time
+	artifact  string
+}
+
+// status classifies the check result. A check that succeeded only after
+// retrying is considered flaky.
+func (e checkResult) status() report.Status {
+	switch {
+	case e.skipped():
+		return report.StatusSkipped
+	case e.err != nil:
+		return report.StatusFailed
+	case e.attempts > 1:
+		return report.StatusFlaky
+	default:
+		return report.StatusPassed
+	}
+}
+
+// reportResult converts the check result to a report.Result.
+func (e checkResult) reportResult() report.Result {
+	return report.Result{
+		Name:      e.check,
+		Type:      e.typeName,
+		Status:    e.status(),
+		Attempts:  e.attempts,
+		Duration:  e.duration,
+		Error:     e.err,
+		Options:   e.options,
+		Seed:      report.SeedFromOptions(e.options),
+		Artifact:  e.artifact,
+		Timestamp: e.timestamp,
+	}
 }
 
 func formatErrorReport(results []checkResult) error {
 	var failedChecks []string
+	var skippedChecks []string
 	var failedDetails []string
 
 	// if there is only one error, return it directly
@@ -181,7 +630,11 @@ func formatErrorReport(results []checkResult) error {
 	}
 
 	for _, result := range results {
-		if result.err != nil {
+		switch {
+		case result.skipped():
+			skippedChecks = append(skippedChecks, result.check)
+			failedDetails = append(failedDetails, result.DetailString())
+		case result.err != nil:
 			failedChecks = append(failedChecks, result.check)
 			failedDetails = append(failedDetails, result.DetailString())
 		}
@@ -190,6 +643,17 @@ func formatErrorReport(results []checkResult) error {
 	totalChecks := len(results)
 	failedCount := len(failedChecks)
 
+	if len(skippedChecks) > 0 {
+		return fmt.Errorf("CHECK_FAILED | %d/%d checks failed | %d/%d checks skipped | Checks: %s | Skipped: %s | %s",
+			failedCount,
+			totalChecks,
+			len(skippedChecks),
+			totalChecks,
+			strings.Join(failedChecks, ","),
+			strings.Join(skippedChecks, ","),
+			strings.Join(failedDetails, " | "))
+	}
+
 	return fmt.Errorf("CHECK_FAILED | %d/%d checks failed | Checks: %s | %s",
 		failedCount,
 		totalChecks,
@@ -197,15 +661,30 @@ func formatErrorReport(results []checkResult) error {
 		strings.Join(failedDetails, " | "))
 }
 
+// skipped reports whether the check was not executed because of a failed dependency.
+func (e checkResult) skipped() bool {
+	return errors.Is(e.err, errSkipped)
+}
+
 func (e checkResult) String() string {
+	if e.skipped() {
+		return fmt.Sprintf("%s: skipped", e.check)
+	}
 	if e.err != nil {
 		return fmt.Sprintf("%s: %v", e.check, e.err)
 	}
+	if e.attempts > 1 {
+		return fmt.Sprintf("%s: flaky", e.check)
+	}
 	return fmt.Sprintf("%s: success", e.check)
 }
 
 func (e checkResult) DetailString() string {
 	timestamp := e.timestamp.Format("2006-01-02T15:04:05")
+	if e.skipped() {
+		return fmt.Sprintf(`{"check":"%s","time":"%s","status":"skipped","error":"%v"}`,
+			e.check, timestamp, e.err)
+	}
 	if e.err != nil {
 		return fmt.Sprintf(`{"check":"%s","time":"%s","error":"%v"}`,
 			e.check, timestamp, e.err)
@@ -0,0 +1,407 @@
+package check
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/beekeeper/pkg/beekeeper"
+	"github.com/ethersphere/beekeeper/pkg/config"
+	"github.com/ethersphere/beekeeper/pkg/logging"
+	"github.com/ethersphere/beekeeper/pkg/orchestration"
+	"github.com/ethersphere/beekeeper/pkg/report"
+	"github.com/opentracing/opentracing-go"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+type testAction struct {
+	err      error
+	delay    time.Duration
+	running  *atomic.Int32
+	maxSeen  *atomic.Int32
+	mu       *sync.Mutex
+	executed *[]string
+	name     string
+}
+
+func (a *testAction) Run(ctx context.Context, _ orchestration.Cluster, _ any) error {
+	n := a.running.Add(1)
+	defer a.running.Add(-1)
+	for {
+		m := a.maxSeen.Load()
+		if n <= m || a.maxSeen.CompareAndSwap(m, n) {
+			break
+		}
+	}
+
+	a.mu.Lock()
+	*a.executed = append(*a.executed, a.name)
+	a.mu.Unlock()
+
+	select {
+	case <-time.After(a.delay):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return a.err
+}
+
+type testChecks struct {
+	running  atomic.Int32
+	maxSeen  atomic.Int32
+	mu       sync.Mutex
+	executed []string
+}
+
+func (tc *testChecks) run(name string, err error, group string, deps ...string) checkRun {
+	return checkRun{
+		name: name,
+		action: &testAction{
+			err:      err,
+			delay:    20 * time.Millisecond,
+			running:  &tc.running,
+			maxSeen:  &tc.maxSeen,
+			mu:       &tc.mu,
+			executed: &tc.executed,
+			name:     name,
+		},
+		dependsOn:     deps,
+		parallelGroup: group,
+	}
+}
+
+func TestBuildGraph(t *testing.T) {
+	tc := new(testChecks)
+
+	t.Run("ignores unselected dependencies", func(t *testing.T) {
+		nodes, err := buildGraph([]checkRun{
+			tc.run("a", nil, ""),
+			tc.run("b", nil, "", "a", "missing"),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(nodes[1].deps) != 1 || nodes[1].deps[0] != 0 {
+			t.Fatalf("got deps %v, want [0]", nodes[1].deps)
+		}
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		_, err := buildGraph([]checkRun{
+			tc.run("a", nil, "", "c"),
+			tc.run("b", nil, "", "a"),
+			tc.run("c", nil, "", "b"),
+		})
+		if err == nil || !strings.Contains(err.Error(), "circular dependency") {
+			t.Fatalf("got error %v, want circular dependency", err)
+		}
+	})
+
+	t.Run("depends on matrix variants", func(t *testing.T) {
+		a1, a2 := tc.run("a[size=1]", nil, ""), tc.run("a[size=2]", nil, "")
+		a1.check, a2.check = "a", "a"
+		nodes, err := buildGraph([]checkRun{a1, a2, tc.run("b", nil, "", "a")})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(nodes[2].deps) != 2 || nodes[2].deps[0] != 0 || nodes[2].deps[1] != 1 {
+			t.Fatalf("got deps %v, want [0 1]", nodes[2].deps)
+		}
+	})
+
+	t.Run("duplicate", func(t *testing.T) {
+		_, err := buildGraph([]checkRun{
+			tc.run("a", nil, ""),
+			tc.run("a", nil, ""),
+		})
+		if err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
+
+func TestRunGraph(t *testing.T) {
+	errFailed := errors.New("failed")
+
+	t.Run("skips dependents of failed checks", func(t *testing.T) {
+		tc := new(testChecks)
+		nodes, err := buildGraph([]checkRun{
+			tc.run("a", errFailed, ""),
+			tc.run("b", nil, "", "a"),
+			tc.run("c", nil, "", "b"),
+			tc.run("d", nil, ""),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		runner := NewCheckRunner(config.CheckGlobalConfig{}, nil, nil, nil, nil, nil, WithMaxParallel(4))
+		results := runner.runGraph(context.Background(), nodes)
+
+		if !errors.Is(results[0].err, errFailed) {
+			t.Fatalf("a: got %v, want %v", results[0].err, errFailed)
+		}
+		for _, i := range []int{1, 2} {
+			if !results[i].skipped() {
+				t.Fatalf("%s: expected to be skipped, got %v", results[i].check, results[i].err)
+			}
+		}
+		if results[3].err != nil {
+			t.Fatalf("d: unexpected error %v", results[3].err)
+		}
+		if len(tc.executed) != 2 {
+			t.Fatalf("got executed %v, want [a d]", tc.executed)
+		}
+
+		report := formatErrorReport(results).Error()
+		if !strings.Contains(report, "1/4 checks failed") || !strings.Contains(report, "Skipped: b,c") {
+			t.Fatalf("unexpected report: %s", report)
+		}
+	})
+
+	t.Run("respects dependency order", func(t *testing.T) {
+		tc := new(testChecks)
+		nodes, err := buildGraph([]checkRun{
+			tc.run("c", nil, "", "b"),
+			tc.run("b", nil, "", "a"),
+			tc.run("a", nil, ""),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		runner := NewCheckRunner(config.CheckGlobalConfig{}, nil, nil, nil, nil, nil, WithMaxParallel(4))
+		for _, r := range runner.runGraph(context.Background(), nodes) {
+			if r.err != nil {
+				t.Fatalf("%s: unexpected error %v", r.check, r.err)
+			}
+		}
+		if got := strings.Join(tc.executed, ","); got != "a,b,c" {
+			t.Fatalf("got order %s, want a,b,c", got)
+		}
+	})
+
+	for _, tt := range []struct {
+		name        string
+		maxParallel int
+		groups      []string
+		wantMax     int32
+	}{
+		{name: "sequential by default", maxParallel: 0, groups: []string{"", "", ""}, wantMax: 1},
+		{name: "max parallel", maxParallel: 2, groups: []string{"", "", ""}, wantMax: 2},
+		{name: "parallel group", maxParallel: 3, groups: []string{"g", "g", "g"}, wantMax: 1},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			tc := new(testChecks)
+			var runs []checkRun
+			for i, g := range tt.groups {
+				runs = append(runs, tc.run(string(rune('a'+i)), nil, g))
+			}
+			nodes, err := buildGraph(runs)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			runner := NewCheckRunner(config.CheckGlobalConfig{}, nil, nil, nil, nil, nil, WithMaxParallel(tt.maxParallel))
+			runner.runGraph(context.Background(), nodes)
+
+			if got := tc.maxSeen.Load(); got != tt.wantMax {
+				t.Fatalf("got max concurrency %d, want %d", got, tt.wantMax)
+			}
+		})
+	}
+}
+
+type flakyAction struct {
+	failures int
+	calls    int
+}
+
+func (a *flakyAction) Run(context.Context, orchestration.Cluster, any) error {
+	a.calls++
+	if a.calls <= a.failures {
+		return errors.New("transient")
+	}
+	return nil
+}
+
+func TestCheckRunRetries(t *testing.T) {
+	logger := logging.New(io.Discard, 0)
+
+	for _, tt := range []struct {
+		name         string
+		failures     int
+		retries      int
+		wantAttempts int
+		wantStatus   report.Status
+	}{
+		{name: "passed", failures: 0, retries: 2, wantAttempts: 1, wantStatus: report.StatusPassed},
+		{name: "flaky", failures: 2, retries: 2, wantAttempts: 3, wantStatus: report.StatusFlaky},
+		{name: "failed", failures: 3, retries: 2, wantAttempts: 3, wantStatus: report.StatusFailed},
+		{name: "no retries", failures: 1, retries: 0, wantAttempts: 1, wantStatus: report.StatusFailed},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			run := checkRun{
+				name:       tt.name,
+				action:     &flakyAction{failures: tt.failures},
+				retries:    tt.retries,
+				retryDelay: time.Millisecond,
+			}
+
+			attempts, err := run.Run(context.Background(), nil, logger)
+			if attempts != tt.wantAttempts {
+				t.Fatalf("got %d attempts, want %d", attempts, tt.wantAttempts)
+			}
+
+			result := checkResult{check: tt.name, err: err, attempts: attempts}
+			if got := result.status(); got != tt.wantStatus {
+				t.Fatalf("got status %s, want %s", got, tt.wantStatus)
+			}
+		})
+	}
+}
+
+type lifecycleAction struct {
+	runErr   error
+	block    bool
+	setupErr error
+	mu       sync.Mutex
+	phases   []string
+}
+
+func (a *lifecycleAction) record(phase string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.phases = append(a.phases, phase)
+}
+
+func (a *lifecycleAction) Setup(context.Context, orchestration.Cluster, any) error {
+	a.record("setup")
+	return a.setupErr
+}
+
+func (a *lifecycleAction) Run(ctx context.Context, _ orchestration.Cluster, _ any) error {
+	a.record("run")
+	if a.block {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	return a.runErr
+}
+
+func (a *lifecycleAction) Teardown(ctx context.Context, _ orchestration.Cluster, _ any) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	a.record("teardown")
+	return nil
+}
+
+func TestCheckRunLifecycle(t *testing.T) {
+	logger := logging.New(io.Discard, 0)
+	timeout := 10 * time.Millisecond
+
+	for _, tt := range []struct {
+		name         string
+		action       *lifecycleAction
+		retries      int
+		skipTeardown bool
+		wantErr      bool
+		wantPhases   string
+	}{
+		{name: "success", action: &lifecycleAction{}, wantPhases: "setup,run,teardown"},
+		{name: "failure", action: &lifecycleAction{runErr: errors.New("failed")}, retries: 1, wantErr: true, wantPhases: "setup,run,run,teardown"},
+		{name: "setup failure", action: &lifecycleAction{setupErr: errors.New("failed")}, wantErr: true, wantPhases: "setup,teardown"},
+		{name: "timeout", action: &lifecycleAction{block: true}, wantErr: true, wantPhases: "setup,run,teardown"},
+		{name: "skip teardown", action: &lifecycleAction{}, skipTeardown: true, wantPhases: "setup,run"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			run := checkRun{
+				name:         tt.name,
+				action:       tt.action,
+				retries:      tt.retries,
+				timeout:      &timeout,
+				skipTeardown: tt.skipTeardown,
+			}
+
+			_, err := run.Run(context.Background(), nil, logger)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+			tt.action.mu.Lock()
+			defer tt.action.mu.Unlock()
+			if got := strings.Join(tt.action.phases, ","); got != tt.wantPhases {
+				t.Fatalf("got phases %s, want %s", got, tt.wantPhases)
+			}
+		})
+	}
+}
+
+func TestCheckRunMiddlewareHooks(t *testing.T) {
+	tracer := opentracing.NoopTracer{}
+
+	// the middleware reports only the hooks of the wrapped action
+	plain := beekeeper.NewActionMiddleware(tracer, &flakyAction{}, "plain")
+	if _, ok := beekeeper.AsSetupAction(plain); ok {
+		t.Fatal("got setup hook of an action without setup")
+	}
+	if _, ok := beekeeper.AsTeardownAction(plain); ok {
+		t.Fatal("got teardown hook of an action without teardown")
+	}
+
+	action := &lifecycleAction{}
+	run := checkRun{
+		name:   "wrapped",
+		action: beekeeper.NewActionMiddleware(tracer, action, "wrapped"),
+	}
+	if _, err := run.Run(context.Background(), nil, logging.New(io.Discard, 0)); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if got := strings.Join(action.phases, ","); got != "setup,run,teardown" {
+		t.Fatalf("got phases %s, want setup,run,teardown", got)
+	}
+}
+
+type reportingAction struct {
+	flakyAction
+	runs prometheus.Counter
+}
+
+func (a *reportingAction) Report() []prometheus.Collector {
+	return []prometheus.Collector{a.runs}
+}
+
+func TestActionPerVariant(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	runner := NewCheckRunner(config.CheckGlobalConfig{}, nil, nil, registry, opentracing.NoopTracer{}, logging.New(io.Discard, 0))
+
+	checkType := config.CheckType{NewAction: func(logging.Logger) beekeeper.Action {
+		return &reportingAction{runs: prometheus.NewCounter(prometheus.CounterOpts{Name: "runs_total", Help: "Runs."})}
+	}}
+
+	small := runner.action("upload/size=small", "upload", checkType)
+	large := runner.action("upload/size=large", "upload", checkType)
+	if small == large {
+		t.Fatal("variants share the action")
+	}
+	if runner.action("upload/size=small", "upload", checkType) != small {
+		t.Fatal("action of the variant is created again")
+	}
+
+	small.(*reportingAction).runs.Inc()
+
+	if err := testutil.GatherAndCompare(registry, strings.NewReader(`
+# HELP runs_total Runs.
+# TYPE runs_total counter
+runs_total{check="upload/size=large"} 0
+runs_total{check="upload/size=small"} 1
+`), "runs_total"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -166,7 +166,6 @@ func (c *Check) run(ctx context.Context, cluster orchestration.Cluster, o Option
 					txData     []byte
 					rxData     []byte
 					address    swarm.Address
-					uploaded   bool
 					downloaded bool
 				)
 
@@ -176,38 +175,17 @@ func (c *Check) run(ctx context.Context, cluster orchestration.Cluster, o Option
 					continue
 				}
 
-				var (
-					txCtx    context.Context
-					txCancel context.CancelFunc = func() {}
-				)
-
-				for range 3 {
-					txCancel()
-
-					uploaded = false
-
-					select {
-					case <-ctx.Done():
+				txCtx, txCancel := context.WithTimeout(ctx, o.UploadTimeout)
+				c.metrics.UploadAttempts.WithLabelValues(sizeLabel, uploader.Name(), rLevelLabel).Inc()
+				address, txDuration, err = test.Upload(txCtx, uploader, txData, batchID, rLevel)
+				txCancel()
+				if err != nil {
+					c.metrics.UploadErrors.WithLabelValues(sizeLabel, uploader.Name(), rLevelLabel).Inc()
+					c.logger.Errorf("upload failed for size %d: %v", contentSize, err)
+					c.logger.Infof("skipping download for size %d due to upload failure, continuing in: %v", contentSize, o.TxOnErrWait)
+					if !sleep(ctx, o.TxOnErrWait) {
 						return nil
-					case <-time.After(o.TxOnErrWait):
 					}
-
-					txCtx, txCancel = context.WithTimeout(ctx, o.UploadTimeout)
-
-					c.metrics.UploadAttempts.WithLabelValues(sizeLabel, uploader.Name(), rLevelLabel).Inc()
-					address, txDuration, err = test.Upload(txCtx, uploader, txData, batchID, rLevel)
-					if err != nil {
-						c.metrics.UploadErrors.WithLabelValues(sizeLabel, uploader.Name(), rLevelLabel).Inc()
-						c.logger.Errorf("upload failed for size %d: %v", contentSize, err)
-						c.logger.Infof("retrying in: %v", o.TxOnErrWait)
-					} else {
-						uploaded = true
-						break
-					}
-				}
-				txCancel()
-				if !uploaded {
-					c.logger.Infof("skipping download for size %d due to upload failure", contentSize)
 					continue
 				}
 
@@ -220,67 +198,37 @@ func (c *Check) run(ctx context.Context, cluster orchestration.Cluster, o Option
 					c.metrics.UploadThroughput.WithLabelValues(sizeLabel, uploader.Name(), rLevelLabel).Set(uploadThroughput)
 				}
 
-				time.Sleep(o.NodesSyncWait)
-
-				var (
-					rxCtx    context.Context
-					rxCancel context.CancelFunc = func() {}
-				)
-
-				for range 3 {
-					rxCancel()
-
-					select {
-					case <-ctx.Done():
-						return nil
-					case <-time.After(o.RxOnErrWait):
-					}
+				if !sleep(ctx, o.NodesSyncWait) {
+					return nil
+				}
 
-					c.metrics.DownloadAttempts.WithLabelValues(sizeLabel, downloader.Name(), rLevelLabel).Inc()
+				c.metrics.DownloadAttempts.WithLabelValues(sizeLabel, downloader.Name(), rLevelLabel).Inc()
 
-					rxCtx, rxCancel = context.WithTimeout(ctx, o.DownloadTimeout)
-					rxData, rxDuration, err = test.Download(rxCtx, downloader, address, rLevel)
-					if err != nil {
-						c.metrics.DownloadErrors.WithLabelValues(sizeLabel, downloader.Name(), rLevelLabel).Inc()
-						c.logger.Errorf("download failed for size %d: %v", contentSize, err)
-						c.logger.Infof("retrying in: %v", o.RxOnErrWait)
-						continue
-					}
-
-					if bytes.Equal(rxData, txData) {
-						c.metrics.DownloadDuration.WithLabelValues(sizeLabel, downloader.Name(), rLevelLabel).Observe(rxDuration.Seconds())
-						c.metrics.DownloadSuccess.WithLabelValues(sizeLabel, downloader.Name(), rLevelLabel).Inc()
-						c.metrics.DownloadedBytes.WithLabelValues(downloader.Name(), rLevelLabel).Add(float64(contentSize))
-
-						if rxDuration.Seconds() > 0 {
-							downloadThroughput := float64(contentSize) / rxDuration.Seconds()
-							c.metrics.DownloadThroughput.WithLabelValues(sizeLabel, downloader.Name(), rLevelLabel).Set(downloadThroughput)
-						}
-						downloaded = true
-						break
+				rxCtx, rxCancel := context.WithTimeout(ctx, o.DownloadTimeout)
+				rxData, rxDuration, err = test.Download(rxCtx, downloader, address, rLevel)
+				rxCancel()
+				switch {
+				case err != nil:
+					c.metrics.DownloadErrors.WithLabelValues(sizeLabel, downloader.Name(), rLevelLabel).Inc()
+					c.logger.Errorf("download failed for size %d: %v", contentSize, err)
+				case bytes.Equal(rxData, txData):
+					c.metrics.DownloadDuration.WithLabelValues(sizeLabel, downloader.Name(), rLevelLabel).Observe(rxDuration.Seconds())
+					c.metrics.DownloadSuccess.WithLabelValues(sizeLabel, downloader.Name(), rLevelLabel).Inc()
+					c.metrics.DownloadedBytes.WithLabelValues(downloader.Name(), rLevelLabel).Add(float64(contentSize))
+
+					if rxDuration.Seconds() > 0 {
+						downloadThroughput := float64(contentSize) / rxDuration.Seconds()
+						c.metrics.DownloadThroughput.WithLabelValues(sizeLabel, downloader.Name(), rLevelLabel).Set(downloadThroughput)
 					}
-
+					downloaded = true
+				default:
 					c.logger.Infof("data mismatch for size %d: uploaded and downloaded data differ", contentSize)
 					c.metrics.DownloadMismatch.WithLabelValues(sizeLabel, downloader.Name(), rLevelLabel).Inc()
-
-					rxLen, txLen := len(rxData), len(txData)
-					if rxLen != txLen {
-						c.logger.Errorf("length mismatch for size %d: downloaded %d bytes, uploaded %d bytes", contentSize, rxLen, txLen)
-						continue
-					}
-
-					var diff int
-					for i := range txData {
-						if txData[i] != rxData[i] {
-							diff++
-						}
-					}
-					c.logger.Infof("data mismatch for size %d: found %d different bytes, ~%.2f%%", contentSize, diff, float64(diff)/float64(txLen)*100)
+					logMismatch(c.logger, contentSize, txData, rxData)
 				}
-				rxCancel()
 
 				if !downloaded {
-					c.logger.Errorf("all download attempts failed for size %d, fetching downloader topology", contentSize)
+					c.logger.Errorf("download failed for size %d, fetching downloader topology", contentSize)
 					top, topErr := downloader.Topology(ctx)
 					if topErr != nil {
 						c.logger.Errorf("failed to get downloader topology: %v", topErr)
@@ -288,8 +236,10 @@ func (c *Check) run(ctx context.Context, cluster orchestration.Cluster, o Option
 						c.logger.Infof("downloader %s topology: depth=%d, connected=%d, population=%d, reachability=%s, bins=%s",
 							downloader.Name(), top.Depth, top.Connected, top.Population, top.Reachability, top.Bins.String())
 					}
+					if !sleep(ctx, o.RxOnErrWait) {
+						return nil
+					}
 				}
-
 				c.logger.Infof("completed testing file size: %d bytes", contentSize)
 			}
 			rLevelIdx++
@@ -308,6 +258,34 @@ func (c *Check) Report() []prometheus.Collector {
 	return c.metrics.Report()
 }
 
+// logMismatch logs how the downloaded data differs from the uploaded one
+func logMismatch(logger logging.Logger, contentSize int64, txData, rxData []byte) {
+	rxLen, txLen := len(rxData), len(txData)
+	if rxLen != txLen {
+		logger.Errorf("length mismatch for size %d: downloaded %d bytes, uploaded %d bytes", contentSize, rxLen, txLen)
+		return
+	}
+
+	var diff int
+	for i := range txData {
+		if txData[i] != rxData[i] {
+			diff++
+		}
+	}
+	logger.Infof("data mismatch for size %d: found %d different bytes, ~%.2f%%", contentSize, diff, float64(diff)/float64(txLen)*100)
+}
+
+// sleep waits for the duration and returns false if the context is done
+// before
+func sleep(ctx context.Context, d time.Duration) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(d):
+		return true
+	}
+}
+
 func redundancyLevelLabel(rLevel *redundancy.Level) string {
 	if rLevel == nil {
 		return "not_set"
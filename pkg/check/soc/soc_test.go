@@ -0,0 +1,31 @@
+package soc_test
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/beekeeper/pkg/check/soc"
+	"github.com/ethersphere/beekeeper/pkg/logging"
+	"github.com/ethersphere/beekeeper/pkg/orchestration"
+	"github.com/ethersphere/beekeeper/pkg/orchestration/fake"
+)
+
+func TestCheck(t *testing.T) {
+	ctx := context.Background()
+
+	cluster := fake.NewCluster("soc", orchestration.ClusterOptions{}, nil)
+	defer cluster.Close()
+	if err := cluster.AddNodes(ctx, "bee", 2, orchestration.Config{FullNode: true}); err != nil {
+		t.Fatalf("add nodes: %v", err)
+	}
+
+	opts := soc.NewDefaultOptions()
+	opts.RequestTimeout = 10 * time.Second
+
+	check := soc.NewCheck(logging.New(io.Discard, 0))
+	if err := check.Run(ctx, cluster, opts); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"time"
+
+	"github.com/ethersphere/beekeeper/pkg/chaos"
+)
+
+// Chaos represents chaos configuration, disruptions of the cluster that run
+// alongside checks
+type Chaos struct {
+	PodKill   *ChaosPodKill   `yaml:"pod-kill"`
+	Partition *ChaosPartition `yaml:"partition"`
+}
+
+// ChaosPodKill represents configuration of killing pods of nodes
+type ChaosPodKill struct {
+	NodeGroups []string      `yaml:"node-groups"`
+	Interval   time.Duration `yaml:"interval"`
+	Count      int           `yaml:"count"`
+}
+
+// ChaosPartition represents configuration of partitioning node groups from
+// the rest of the cluster
+type ChaosPartition struct {
+	NodeGroups []string      `yaml:"node-groups"`
+	Interval   time.Duration `yaml:"interval"`
+	Duration   time.Duration `yaml:"duration"`
+}
+
+// Export exports Chaos to chaos.Options
+func (c Chaos) Export(seed int64) (o chaos.Options) {
+	o.Seed = seed
+
+	if c.PodKill != nil {
+		o.PodKill = &chaos.PodKillOptions{
+			NodeGroups: c.PodKill.NodeGroups,
+			Interval:   c.PodKill.Interval,
+			Count:      c.PodKill.Count,
+		}
+	}
+
+	if c.Partition != nil {
+		o.Partition = &chaos.PartitionOptions{
+			NodeGroups: c.Partition.NodeGroups,
+			Interval:   c.Partition.Interval,
+			Duration:   c.Partition.Duration,
+		}
+	}
+
+	return o
+}
@@ -0,0 +1,66 @@
+package config_test
+
+import (
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/beekeeper/pkg/chaos"
+	"github.com/ethersphere/beekeeper/pkg/config"
+	"github.com/ethersphere/beekeeper/pkg/logging"
+)
+
+const chaosConfig = `
+chaos:
+  churn:
+    pod-kill:
+      node-groups: [bee]
+      interval: 2m
+      count: 2
+    partition:
+      node-groups: [light]
+      interval: 10m
+      duration: 1m
+  kill-only:
+    pod-kill:
+      interval: 30s
+`
+
+func TestChaosExport(t *testing.T) {
+	cfg, err := config.Read(logging.New(io.Discard, 0), []config.YamlFile{{Name: "chaos.yaml", Content: []byte(chaosConfig)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name string
+		want chaos.Options
+	}{
+		{
+			name: "churn",
+			want: chaos.Options{
+				PodKill:   &chaos.PodKillOptions{NodeGroups: []string{"bee"}, Interval: 2 * time.Minute, Count: 2},
+				Partition: &chaos.PartitionOptions{NodeGroups: []string{"light"}, Interval: 10 * time.Minute, Duration: time.Minute},
+				Seed:      7,
+			},
+		},
+		{
+			name: "kill-only",
+			want: chaos.Options{
+				PodKill: &chaos.PodKillOptions{Interval: 30 * time.Second},
+				Seed:    7,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c, ok := cfg.Chaos[tc.name]
+			if !ok {
+				t.Fatalf("chaos %s not found", tc.name)
+			}
+			if got := c.Export(7); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
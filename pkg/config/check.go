@@ -25,10 +25,12 @@ import (
 	"github.com/ethersphere/beekeeper/pkg/check/networkavailability"
 	"github.com/ethersphere/beekeeper/pkg/check/peercount"
 	"github.com/ethersphere/beekeeper/pkg/check/pingpong"
+	"github.com/ethersphere/beekeeper/pkg/check/pinning"
 	"github.com/ethersphere/beekeeper/pkg/check/postage"
 	"github.com/ethersphere/beekeeper/pkg/check/pss"
 	"github.com/ethersphere/beekeeper/pkg/check/pullsync"
 	"github.com/ethersphere/beekeeper/pkg/check/pushsync"
+	"github.com/ethersphere/beekeeper/pkg/check/redistribution"
 	"github.com/ethersphere/beekeeper/pkg/check/redundancy"
 	"github.com/ethersphere/beekeeper/pkg/check/retrieval"
 	"github.com/ethersphere/beekeeper/pkg/check/settlements"
@@ -43,9 +45,29 @@ import (
 
 // Check represents check configuration
 type Check struct {
-	Options yaml.Node      `yaml:"options"`
-	Timeout *time.Duration `yaml:"timeout"`
-	Type    string         `yaml:"type"`
+	DependsOn     []string       `yaml:"depends-on"`
+	Matrix        Matrix         `yaml:"matrix"`
+	Options       yaml.Node      `yaml:"options"`
+	ParallelGroup string         `yaml:"parallel-group"`
+	Retries       int            `yaml:"retries"`
+	RetryDelay    time.Duration  `yaml:"retry-delay"`
+	Tags          []string       `yaml:"tags"`
+	Timeout       *time.Duration `yaml:"timeout"`
+	Type          string         `yaml:"type"`
+}
+
+// HasOption returns whether the option with the name is set in the check's
+// options
+func (c Check) HasOption(name string) bool {
+	if c.Options.Kind != yaml.MappingNode {
+		return false
+	}
+	for i := 0; i+1 < len(c.Options.Content); i += 2 {
+		if c.Options.Content[i].Value == name {
+			return true
+		}
+	}
+	return false
 }
 
 // CheckType is used for linking beekeeper actions with check and it's proper options
@@ -357,7 +379,6 @@ var Checks = map[string]CheckType{
 				PostageTTL        *time.Duration `yaml:"postage-ttl"`
 				PostageDepth      *uint64        `yaml:"postage-depth"`
 				PostageLabel      *string        `yaml:"postage-label"`
-				Retries           *int           `yaml:"retries"`
 				RetryDelay        *time.Duration `yaml:"retry-delay"`
 				Seed              *int64         `yaml:"seed"`
 				UploadNodeCount   *int           `yaml:"upload-node-count"`
@@ -517,6 +538,32 @@ var Checks = map[string]CheckType{
 			return opts, nil
 		},
 	},
+	"pinning": {
+		NewAction: pinning.NewCheck,
+		NewOptions: func(checkGlobalConfig CheckGlobalConfig, check Check) (any, error) {
+			checkOpts := new(struct {
+				NodeCount    *int           `yaml:"node-count"`
+				FileSize     *int64         `yaml:"file-size"`
+				FillChunks   *int           `yaml:"fill-chunks"`
+				GCTimeout    *time.Duration `yaml:"gc-timeout"`
+				PollInterval *time.Duration `yaml:"poll-interval"`
+				PostageTTL   *time.Duration `yaml:"postage-ttl"`
+				PostageDepth *uint64        `yaml:"postage-depth"`
+				PostageLabel *string        `yaml:"postage-label"`
+				Seed         *int64         `yaml:"seed"`
+			})
+			if err := check.Options.Decode(checkOpts); err != nil {
+				return nil, fmt.Errorf("decoding check %s options: %w", check.Type, err)
+			}
+			opts := pinning.NewDefaultOptions()
+
+			if err := applyCheckConfig(checkGlobalConfig, checkOpts, &opts); err != nil {
+				return nil, fmt.Errorf("applying options: %w", err)
+			}
+
+			return opts, nil
+		},
+	},
 	"postage": {
 		NewAction: postage.NewCheck,
 		NewOptions: func(checkGlobalConfig CheckGlobalConfig, check Check) (any, error) {
@@ -558,13 +605,34 @@ var Checks = map[string]CheckType{
 			return opts, nil
 		},
 	},
+	"redistribution": {
+		NewAction: redistribution.NewCheck,
+		NewOptions: func(checkGlobalConfig CheckGlobalConfig, check Check) (any, error) {
+			checkOpts := new(struct {
+				StakeAmount  *big.Int       `yaml:"stake-amount"`
+				Rounds       *uint64        `yaml:"rounds"`
+				PollInterval *time.Duration `yaml:"poll-interval"`
+				Seed         *int64         `yaml:"seed"`
+			})
+			if err := check.Options.Decode(checkOpts); err != nil {
+				return nil, fmt.Errorf("decoding check %s options: %w", check.Type, err)
+			}
+			opts := redistribution.NewDefaultOptions()
+			if err := applyCheckConfig(checkGlobalConfig, checkOpts, &opts); err != nil {
+				return nil, fmt.Errorf("applying options: %w", err)
+			}
+			// pointers to big integers are not assignable by applyCheckConfig
+			if checkOpts.StakeAmount != nil {
+				opts.StakeAmount = checkOpts.StakeAmount
+			}
+			return opts, nil
+		},
+	},
 	"longavailability": {
 		NewAction: longavailability.NewCheck,
 		NewOptions: func(checkGlobalConfig CheckGlobalConfig, check Check) (any, error) {
 			checkOpts := new(struct {
 				RndSeed      *int64         `yaml:"rnd-seed"`
-				RetryCount   *int64         `yaml:"retry-count"`
-				RetryWait    *time.Duration `yaml:"retry-wait"`
 				Refs         *[]string      `yaml:"refs"`
 				NextIterWait *time.Duration `yaml:"next-iter-wait"`
 			})
@@ -787,9 +855,7 @@ func applyCheckConfig(global CheckGlobalConfig, local, opts any) (err error) {
 				}
 			}
 		default:
-			if lv.Field(i).IsNil() {
-				fmt.Printf("field %s not set, using default value\n", fieldName)
-			} else {
+			if !lv.Field(i).IsNil() { // fields not set keep the default value
 				fieldType := lt.Field(i).Type
 				fieldValue := lv.FieldByName(fieldName).Elem()
 				ft, ok := ot.FieldByName(fieldName)
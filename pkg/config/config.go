@@ -16,6 +16,8 @@ type Config struct {
 	BeeConfigs  map[string]BeeConfig  `yaml:"bee-configs"`
 	Checks      map[string]Check      `yaml:"checks"`
 	Simulations map[string]Simulation `yaml:"simulations"`
+	Suites      map[string]Suite      `yaml:"suites"`
+	Chaos       map[string]Chaos      `yaml:"chaos"`
 }
 
 type YamlFile struct {
@@ -126,6 +128,8 @@ func Read(log logging.Logger, yamlFiles []YamlFile) (*Config, error) {
 		BeeConfigs:  make(map[string]BeeConfig),
 		Checks:      make(map[string]Check),
 		Simulations: make(map[string]Simulation),
+		Suites:      make(map[string]Suite),
+		Chaos:       make(map[string]Chaos),
 	}
 
 	for _, file := range yamlFiles {
@@ -191,6 +195,26 @@ func Read(log logging.Logger, yamlFiles []YamlFile) (*Config, error) {
 				log.Warningf("simulation '%s' in file '%s' already exits in configuration", k, file.Name)
 			}
 		}
+
+		// join Suites
+		for k, v := range tmp.Suites {
+			_, ok := c.Suites[k]
+			if !ok {
+				c.Suites[k] = v
+			} else {
+				log.Warningf("suite '%s' in file '%s' already exits in configuration", k, file.Name)
+			}
+		}
+
+		// join Chaos
+		for k, v := range tmp.Chaos {
+			_, ok := c.Chaos[k]
+			if !ok {
+				c.Chaos[k] = v
+			} else {
+				log.Warningf("chaos '%s' in file '%s' already exits in configuration", k, file.Name)
+			}
+		}
 	}
 
 	// merge for inheritance
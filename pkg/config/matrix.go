@@ -0,0 +1,172 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Matrix maps option names to the list of values to run a check or
+// simulation with. Every combination of values is run as a separate variant.
+type Matrix map[string][]yaml.Node
+
+// CheckVariant represents a check configuration for one combination of
+// matrix values
+type CheckVariant struct {
+	Name  string
+	Check Check
+}
+
+// Variants expands the matrix of the check with the given name into the
+// cartesian product of option sets. A check without a matrix is returned as
+// its only variant.
+func (c Check) Variants(name string) ([]CheckVariant, error) {
+	variants, err := c.Matrix.expand(name, c.Options)
+	if err != nil {
+		return nil, fmt.Errorf("check %s: %w", name, err)
+	}
+
+	checks := make([]CheckVariant, 0, len(variants))
+	for _, v := range variants {
+		check := c
+		check.Options = v.options
+		check.Matrix = nil
+		checks = append(checks, CheckVariant{Name: v.name, Check: check})
+	}
+
+	return checks, nil
+}
+
+// SimulationVariant represents a simulation configuration for one combination
+// of matrix values
+type SimulationVariant struct {
+	Name       string
+	Simulation Simulation
+}
+
+// Variants expands the matrix of the simulation with the given name into the
+// cartesian product of option sets. A simulation without a matrix is returned
+// as its only variant.
+func (s Simulation) Variants(name string) ([]SimulationVariant, error) {
+	variants, err := s.Matrix.expand(name, s.Options)
+	if err != nil {
+		return nil, fmt.Errorf("simulation %s: %w", name, err)
+	}
+
+	simulations := make([]SimulationVariant, 0, len(variants))
+	for _, v := range variants {
+		simulation := s
+		simulation.Options = v.options
+		simulation.Matrix = nil
+		simulations = append(simulations, SimulationVariant{Name: v.name, Simulation: simulation})
+	}
+
+	return simulations, nil
+}
+
+type variant struct {
+	name    string
+	options yaml.Node
+}
+
+// expand returns options with matrix values set for every combination of
+// them. Matrix options are ordered by name, and the values of the last one
+// change first. Variant names have the values appended to the given name,
+// for example load[content-size=1000,uploader-count=4].
+func (m Matrix) expand(name string, options yaml.Node) ([]variant, error) {
+	if len(m) == 0 {
+		return []variant{{name: name, options: options}}, nil
+	}
+
+	if options.Kind != 0 && options.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("options must be a mapping to apply the matrix")
+	}
+
+	keys := make([]string, 0, len(m))
+	for k, values := range m {
+		if len(values) == 0 {
+			return nil, fmt.Errorf("matrix option %s has no values", k)
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	labels := make(map[string][]string, len(keys))
+	for _, k := range keys {
+		for _, v := range m[k] {
+			label, err := matrixLabel(v)
+			if err != nil {
+				return nil, fmt.Errorf("matrix option %s: %w", k, err)
+			}
+			labels[k] = append(labels[k], label)
+		}
+	}
+
+	var variants []variant
+	idx := make([]int, len(keys))
+	for {
+		opts := options
+		if opts.Kind == 0 {
+			opts = yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		}
+		nameParts := make([]string, len(keys))
+		for i, k := range keys {
+			v := m[k][idx[i]]
+			opts = setOption(opts, k, &v)
+			nameParts[i] = k + "=" + labels[k][idx[i]]
+		}
+		variants = append(variants, variant{
+			name:    name + "[" + strings.Join(nameParts, ",") + "]",
+			options: opts,
+		})
+
+		// advance indexes like an odometer
+		i := len(keys) - 1
+		for ; i >= 0; i-- {
+			idx[i]++
+			if idx[i] < len(m[keys[i]]) {
+				break
+			}
+			idx[i] = 0
+		}
+		if i < 0 {
+			return variants, nil
+		}
+	}
+}
+
+// setOption returns a copy of the mapping node with the value of key
+// replaced or added. Nodes of the original mapping are not modified.
+func setOption(mapping yaml.Node, key string, value *yaml.Node) yaml.Node {
+	content := make([]*yaml.Node, 0, len(mapping.Content)+2)
+	replaced := false
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		k, v := mapping.Content[i], mapping.Content[i+1]
+		if k.Value == key {
+			v, replaced = value, true
+		}
+		content = append(content, k, v)
+	}
+	if !replaced {
+		content = append(content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+	}
+
+	mapping.Content = content
+	return mapping
+}
+
+// matrixLabel formats the matrix value for use in variant names
+func matrixLabel(n yaml.Node) (string, error) {
+	if n.Kind == yaml.ScalarNode {
+		return n.Value, nil
+	}
+
+	n.Style = yaml.FlowStyle
+	b, err := yaml.Marshal(&n)
+	if err != nil {
+		return "", fmt.Errorf("formatting value: %w", err)
+	}
+	return strings.TrimSpace(string(b)), nil
+}
@@ -0,0 +1,100 @@
+package config_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/ethersphere/beekeeper/pkg/config"
+	"gopkg.in/yaml.v3"
+)
+
+func TestCheckVariants(t *testing.T) {
+	var checks map[string]config.Check
+	if err := yaml.Unmarshal([]byte(`
+load:
+  type: load
+  matrix:
+    uploader-count: [1, 4]
+    content-size: [1000, 5000]
+  options:
+    content-size: 10
+    duration: 1m
+plain:
+  type: pingpong
+list:
+  type: load
+  matrix:
+    uploader-groups: [[bee, light]]
+empty:
+  type: load
+  matrix:
+    content-size: []
+`), &checks); err != nil {
+		t.Fatal(err)
+	}
+
+	variants, err := checks["load"].Variants("load")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, v := range variants {
+		names = append(names, v.Name)
+	}
+	want := []string{
+		"load[content-size=1000,uploader-count=1]",
+		"load[content-size=1000,uploader-count=4]",
+		"load[content-size=5000,uploader-count=1]",
+		"load[content-size=5000,uploader-count=4]",
+	}
+	if !slices.Equal(names, want) {
+		t.Fatalf("got variants %v, want %v", names, want)
+	}
+
+	var opts struct {
+		ContentSize   int    `yaml:"content-size"`
+		UploaderCount int    `yaml:"uploader-count"`
+		Duration      string `yaml:"duration"`
+	}
+	if err := variants[1].Check.Options.Decode(&opts); err != nil {
+		t.Fatal(err)
+	}
+	if opts.ContentSize != 1000 || opts.UploaderCount != 4 || opts.Duration != "1m" {
+		t.Fatalf("unexpected options %+v", opts)
+	}
+	if variants[1].Check.Matrix != nil || variants[1].Check.Type != "load" {
+		t.Fatalf("unexpected check %+v", variants[1].Check)
+	}
+
+	// options of the configured check are not modified
+	var orig map[string]any
+	load := checks["load"]
+	if err := load.Options.Decode(&orig); err != nil {
+		t.Fatal(err)
+	}
+	if orig["content-size"] != 10 || len(orig) != 2 {
+		t.Fatalf("configured options modified: %v", orig)
+	}
+
+	variants, err = checks["plain"].Variants("plain")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(variants) != 1 || variants[0].Name != "plain" {
+		t.Fatalf("got variants %+v, want plain", variants)
+	}
+
+	variants, err = checks["list"].Variants("list")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if variants[0].Name != "list[uploader-groups=[bee, light]]" {
+		t.Fatalf("got name %s", variants[0].Name)
+	}
+
+	if _, err := checks["empty"].Variants("empty"); err == nil || !strings.Contains(err.Error(), "no values") {
+		t.Fatalf("got error %v, want no values", err)
+	}
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"reflect"
+	"time"
 
 	"github.com/ethersphere/beekeeper/pkg/orchestration"
 )
@@ -18,6 +19,7 @@ type NodeGroup struct {
 	IngressAnnotations        *map[string]string `yaml:"ingress-annotations"`
 	IngressClass              *string            `yaml:"ingress-class"`
 	Labels                    *map[string]string `yaml:"labels"`
+	NetworkProfile            *NetworkProfile    `yaml:"network-profile"`
 	NodeSelector              *map[string]string `yaml:"node-selector"`
 	P2PWSSNodePort            *int32             `yaml:"p2p-wss-node-port"`
 	PersistenceEnabled        *bool              `yaml:"persistence-enabled"`
@@ -32,6 +34,15 @@ type NodeGroup struct {
 	UpdateStrategy            *string            `yaml:"update-strategy"`
 }
 
+// NetworkProfile represents shaping of network traffic of nodes in the node
+// group
+type NetworkProfile struct {
+	Latency time.Duration `yaml:"latency"`
+	Jitter  time.Duration `yaml:"jitter"`
+	Loss    float64       `yaml:"loss"`
+	Rate    string        `yaml:"rate"`
+}
+
 func (b NodeGroup) GetParentName() string {
 	if b.Inherit != nil {
 		return b.ParentName
@@ -58,5 +69,16 @@ func (n *NodeGroup) Export() (o orchestration.NodeGroupOptions) {
 		}
 	}
 
-	return remoteVal.Interface().(orchestration.NodeGroupOptions)
+	o = remoteVal.Interface().(orchestration.NodeGroupOptions)
+
+	if n.NetworkProfile != nil {
+		o.NetworkProfile = &orchestration.NetworkProfile{
+			Latency: n.NetworkProfile.Latency,
+			Jitter:  n.NetworkProfile.Jitter,
+			Loss:    n.NetworkProfile.Loss,
+			Rate:    n.NetworkProfile.Rate,
+		}
+	}
+
+	return o
 }
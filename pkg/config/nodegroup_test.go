@@ -0,0 +1,49 @@
+package config_test
+
+import (
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/beekeeper/pkg/config"
+	"github.com/ethersphere/beekeeper/pkg/logging"
+	"github.com/ethersphere/beekeeper/pkg/orchestration"
+)
+
+const nodeGroupsConfig = `
+node-groups:
+  default:
+    image: ethersphere/bee:latest
+  slow-link:
+    _inherit: default
+    network-profile:
+      latency: 150ms
+      jitter: 30ms
+      loss: 0.5
+      rate: 5mbit
+`
+
+func TestNodeGroupExportNetworkProfile(t *testing.T) {
+	cfg, err := config.Read(logging.New(io.Discard, 0), []config.YamlFile{{Name: "node-groups.yaml", Content: []byte(nodeGroupsConfig)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ng := cfg.NodeGroups["slow-link"]
+	o := ng.Export()
+
+	if o.Image != "ethersphere/bee:latest" {
+		t.Errorf("got image %s, want inherited image", o.Image)
+	}
+
+	want := &orchestration.NetworkProfile{Latency: 150 * time.Millisecond, Jitter: 30 * time.Millisecond, Loss: 0.5, Rate: "5mbit"}
+	if !reflect.DeepEqual(o.NetworkProfile, want) {
+		t.Errorf("got network profile %+v, want %+v", o.NetworkProfile, want)
+	}
+
+	ng = cfg.NodeGroups["default"]
+	if o := ng.Export(); o.NetworkProfile != nil {
+		t.Errorf("got network profile %+v, want none", o.NetworkProfile)
+	}
+}
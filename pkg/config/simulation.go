@@ -16,6 +16,7 @@ import (
 
 // Simulation represents simulation configuration
 type Simulation struct {
+	Matrix  Matrix         `yaml:"matrix"`
 	Options yaml.Node      `yaml:"options"`
 	Timeout *time.Duration `yaml:"timeout"`
 	Type    string         `yaml:"type"`
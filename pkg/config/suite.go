@@ -0,0 +1,117 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Suite represents a named list of checks
+type Suite struct {
+	// parent to inherit checks from
+	*Inherit `yaml:",inline"`
+	// suite configuration
+	Checks []string `yaml:"checks"`
+}
+
+func (s Suite) GetParentName() string {
+	if s.Inherit != nil {
+		return s.ParentName
+	}
+	return ""
+}
+
+// SuiteChecks returns checks of the suite with the given name. Checks of the
+// parent suite are listed first, followed by the suite's own checks, without
+// duplicates.
+func (c *Config) SuiteChecks(name string) ([]string, error) {
+	var (
+		checks  []string
+		visited = map[string]bool{}
+	)
+
+	var resolve func(name string) error
+	resolve = func(name string) error {
+		if visited[name] {
+			return fmt.Errorf("circular inheritance detected with suite %s", name)
+		}
+		visited[name] = true
+
+		s, ok := c.Suites[name]
+		if !ok {
+			return fmt.Errorf("suite %s doesn't exist", name)
+		}
+
+		if parent := s.GetParentName(); parent != "" {
+			if err := resolve(parent); err != nil {
+				return err
+			}
+		}
+
+		for _, check := range s.Checks {
+			if _, ok := c.Checks[check]; !ok {
+				return fmt.Errorf("suite %s: check %s doesn't exist", name, check)
+			}
+			if !slices.Contains(checks, check) {
+				checks = append(checks, check)
+			}
+		}
+
+		return nil
+	}
+
+	if err := resolve(name); err != nil {
+		return nil, err
+	}
+
+	return checks, nil
+}
+
+// CheckNames returns names of all configured checks sorted by name
+func (c *Config) CheckNames() (names []string) {
+	for name := range c.Checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// FilterChecksByTags returns the checks that match the tag expression. Tags
+// prefixed with "!" exclude checks that have them. If there is at least one
+// tag without the prefix, only checks that have at least one of those tags
+// are returned.
+func (c *Config) FilterChecksByTags(names, tags []string) ([]string, error) {
+	var include, exclude []string
+	for _, t := range tags {
+		t = strings.TrimSpace(t)
+		switch {
+		case t == "", t == "!":
+			continue
+		case strings.HasPrefix(t, "!"):
+			exclude = append(exclude, t[1:])
+		default:
+			include = append(include, t)
+		}
+	}
+
+	var filtered []string
+	for _, name := range names {
+		check, ok := c.Checks[name]
+		if !ok {
+			return nil, fmt.Errorf("check %s doesn't exist", name)
+		}
+
+		if slices.ContainsFunc(exclude, func(t string) bool { return slices.Contains(check.Tags, t) }) {
+			continue
+		}
+
+		if len(include) > 0 && !slices.ContainsFunc(include, func(t string) bool { return slices.Contains(check.Tags, t) }) {
+			continue
+		}
+
+		filtered = append(filtered, name)
+	}
+
+	return filtered, nil
+}
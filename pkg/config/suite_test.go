@@ -0,0 +1,109 @@
+package config_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/ethersphere/beekeeper/pkg/config"
+	"github.com/ethersphere/beekeeper/pkg/logging"
+)
+
+const suitesConfig = `
+checks:
+  ci-pingpong:
+    type: pingpong
+    tags: [smoke]
+  ci-pushsync:
+    type: pushsync
+    tags: [smoke, sync]
+  ci-retrieval:
+    type: retrieval
+    tags: [sync, slow]
+  ci-gc:
+    type: gc
+    tags: [slow]
+suites:
+  smoke:
+    checks: [ci-pingpong, ci-pushsync]
+  nightly:
+    _inherit: smoke
+    checks: [ci-pushsync, ci-retrieval, ci-gc]
+  loop-a:
+    _inherit: loop-b
+  loop-b:
+    _inherit: loop-a
+  broken:
+    checks: [ci-missing]
+`
+
+func readSuitesConfig(t *testing.T) *config.Config {
+	t.Helper()
+	cfg, err := config.Read(logging.New(io.Discard, 0), []config.YamlFile{{Name: "suites.yaml", Content: []byte(suitesConfig)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cfg
+}
+
+func TestSuiteChecks(t *testing.T) {
+	cfg := readSuitesConfig(t)
+
+	for _, tc := range []struct {
+		suite   string
+		want    string
+		wantErr string
+	}{
+		{suite: "smoke", want: "ci-pingpong,ci-pushsync"},
+		{suite: "nightly", want: "ci-pingpong,ci-pushsync,ci-retrieval,ci-gc"},
+		{suite: "loop-a", wantErr: "circular inheritance"},
+		{suite: "broken", wantErr: "check ci-missing doesn't exist"},
+		{suite: "missing", wantErr: "suite missing doesn't exist"},
+	} {
+		t.Run(tc.suite, func(t *testing.T) {
+			got, err := cfg.SuiteChecks(tc.suite)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("got error %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Join(got, ",") != tc.want {
+				t.Fatalf("got %v, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFilterChecksByTags(t *testing.T) {
+	cfg := readSuitesConfig(t)
+
+	for _, tc := range []struct {
+		name  string
+		names []string
+		tags  []string
+		want  string
+	}{
+		{name: "include", names: cfg.CheckNames(), tags: []string{"smoke"}, want: "ci-pingpong,ci-pushsync"},
+		{name: "exclude", names: cfg.CheckNames(), tags: []string{"!slow"}, want: "ci-pingpong,ci-pushsync"},
+		{name: "include and exclude", names: cfg.CheckNames(), tags: []string{"sync", "!slow"}, want: "ci-pushsync"},
+		{name: "keeps order", names: []string{"ci-retrieval", "ci-pushsync"}, tags: []string{"sync"}, want: "ci-retrieval,ci-pushsync"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := cfg.FilterChecksByTags(tc.names, tc.tags)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Join(got, ",") != tc.want {
+				t.Fatalf("got %v, want %s", got, tc.want)
+			}
+		})
+	}
+
+	if _, err := cfg.FilterChecksByTags([]string{"ci-missing"}, []string{"smoke"}); err == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -0,0 +1,44 @@
+package node_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethersphere/beekeeper/pkg/bee/api/beetest"
+	fundernode "github.com/ethersphere/beekeeper/pkg/funder/node"
+	"github.com/ethersphere/beekeeper/pkg/node"
+)
+
+// nodeProvider provides a fixed list of nodes
+type nodeProvider node.NodeList
+
+func (p nodeProvider) GetNodes(ctx context.Context) (node.NodeList, error) {
+	return node.NodeList(p), nil
+}
+
+func (p nodeProvider) Namespace() string {
+	return "test"
+}
+
+func TestList(t *testing.T) {
+	s0, c0 := beetest.New(t)
+	s1, c1 := beetest.New(t)
+	nodes := nodeProvider{*node.NewNode(c0, "bee-0"), *node.NewNode(c1, "bee-1")}
+
+	infos, err := fundernode.NewClient(nodes, nil).List(context.Background(), "test")
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(infos) != 2 {
+		t.Fatalf("nodes: got %d, want 2", len(infos))
+	}
+	for i, s := range []*beetest.Server{s0, s1} {
+		if infos[i].Name != nodes[i].Name() || infos[i].Address != s.URL().String() {
+			t.Fatalf("node %d: got %s at %s, want %s at %s", i, infos[i].Name, infos[i].Address, nodes[i].Name(), s.URL())
+		}
+	}
+
+	if _, err := fundernode.NewClient(&node.NotSet{}, nil).List(context.Background(), "test"); err == nil {
+		t.Fatal("list without nodes: got no error")
+	}
+}
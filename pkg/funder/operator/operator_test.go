@@ -0,0 +1,30 @@
+package operator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethersphere/beekeeper/pkg/bee/api/beetest"
+)
+
+func TestGetAddresses(t *testing.T) {
+	s, _ := beetest.New(t)
+	c := NewClient(&ClientConfig{})
+	ctx := context.Background()
+
+	addresses, err := c.getAddresses(ctx, s.URL().String())
+	if err != nil {
+		t.Fatalf("addresses: %v", err)
+	}
+	if addresses.Ethereum != s.EthereumAddress() {
+		t.Fatalf("ethereum address: got %s, want %s", addresses.Ethereum, s.EthereumAddress())
+	}
+	if !addresses.Overlay.Equal(s.Overlay()) {
+		t.Fatalf("overlay: got %s, want %s", addresses.Overlay, s.Overlay())
+	}
+
+	s.Close()
+	if _, err := c.getAddresses(ctx, s.URL().String()); err == nil {
+		t.Fatal("addresses of closed node: got no error")
+	}
+}
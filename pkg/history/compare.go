@@ -0,0 +1,79 @@
+package history
+
+import (
+	"sort"
+	"time"
+
+	"github.com/ethersphere/beekeeper/pkg/report"
+)
+
+// Comparison represents the difference of a check between two runs
+type Comparison struct {
+	Name           string
+	Base           *Result // nil if the check is not in the base run
+	Target         *Result // nil if the check is not in the target run
+	DurationChange float64 // percentage of the duration change
+	Regressed      bool
+	Reason         string // reason of the regression
+}
+
+// Compare compares results of checks in the base and target runs. A check
+// regressed if it failed in the target run but not in the base run, or if
+// both runs passed it and its duration increased by more than threshold
+// percent. Comparisons are sorted by check name.
+func Compare(base, target Run, threshold float64) []Comparison {
+	names := make(map[string]struct{})
+	for _, r := range base.Results {
+		names[r.Name] = struct{}{}
+	}
+	for _, r := range target.Results {
+		names[r.Name] = struct{}{}
+	}
+
+	comparisons := make([]Comparison, 0, len(names))
+	for name := range names {
+		c := Comparison{Name: name}
+		if r, ok := base.Result(name); ok {
+			c.Base = &r
+		}
+		if r, ok := target.Result(name); ok {
+			c.Target = &r
+		}
+
+		if c.Base != nil && c.Target != nil {
+			c.DurationChange = durationChange(c.Base.Duration, c.Target.Duration)
+
+			switch {
+			case failed(c.Target.Status) && !failed(c.Base.Status):
+				c.Regressed = true
+				c.Reason = "status changed from " + string(c.Base.Status) + " to " + string(c.Target.Status)
+			case passed(c.Base.Status) && passed(c.Target.Status) && c.DurationChange > threshold:
+				c.Regressed = true
+				c.Reason = "duration increased by more than threshold"
+			}
+		}
+
+		comparisons = append(comparisons, c)
+	}
+
+	sort.Slice(comparisons, func(i, j int) bool {
+		return comparisons[i].Name < comparisons[j].Name
+	})
+
+	return comparisons
+}
+
+func durationChange(base, target time.Duration) float64 {
+	if base <= 0 {
+		return 0
+	}
+	return float64(target-base) / float64(base) * 100
+}
+
+func failed(s report.Status) bool {
+	return s == report.StatusFailed
+}
+
+func passed(s report.Status) bool {
+	return s == report.StatusPassed || s == report.StatusFlaky
+}
@@ -0,0 +1,172 @@
+// Package history stores results of beekeeper runs in a local database and
+// compares them to find regressions.
+package history
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethersphere/beekeeper/pkg/report"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	// ErrNotFound is returned when a run does not exist in the store
+	ErrNotFound = errors.New("run not found")
+
+	runsBucket = []byte("runs")
+)
+
+// Result represents the outcome of a single check in a run
+type Result struct {
+	Name     string        `json:"name"`
+	Type     string        `json:"type,omitempty"`
+	Status   report.Status `json:"status"`
+	Attempts int           `json:"attempts,omitempty"`
+	Duration time.Duration `json:"duration"`
+	Seed     *int64        `json:"seed,omitempty"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// Run represents a stored beekeeper run
+type Run struct {
+	ID          uint64            `json:"id"`
+	Command     string            `json:"command"`
+	Cluster     string            `json:"cluster"`
+	Seed        int64             `json:"seed"`
+	Timestamp   time.Time         `json:"timestamp"`
+	Duration    time.Duration     `json:"duration"`
+	BeeVersions map[string]string `json:"bee_versions,omitempty"` // node name to Bee version
+	Results     []Result          `json:"results"`
+}
+
+// NewRun creates a Run from the report and Bee versions of the cluster nodes
+func NewRun(r *report.Report, beeVersions map[string]string) Run {
+	run := Run{
+		Command:     r.Name,
+		Cluster:     r.Cluster,
+		Seed:        r.Seed,
+		Timestamp:   r.Timestamp,
+		Duration:    r.Duration,
+		BeeVersions: beeVersions,
+		Results:     make([]Result, 0, len(r.Results)),
+	}
+
+	for _, res := range r.Results {
+		result := Result{
+			Name:     res.Name,
+			Type:     res.Type,
+			Status:   res.Status,
+			Attempts: res.Attempts,
+			Duration: res.Duration,
+			Seed:     res.Seed,
+		}
+		if res.Error != nil {
+			result.Error = res.Error.Error()
+		}
+		run.Results = append(run.Results, result)
+	}
+
+	return run
+}
+
+// Result returns the result of the check with the given name
+func (r *Run) Result(name string) (Result, bool) {
+	for _, res := range r.Results {
+		if res.Name == name {
+			return res, true
+		}
+	}
+	return Result{}, false
+}
+
+// Store persists runs in a bbolt database file
+type Store struct {
+	db *bolt.DB
+}
+
+// Open opens the database file at path, creating it if it does not exist
+func Open(path string) (*Store, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("open history database %s: %w", path, err)
+	}
+
+	if err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(runsBucket)
+		return err
+	}); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("create runs bucket: %w", err)
+	}
+
+	return &Store{db: db}, nil
+}
+
+// Close closes the database
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Add stores the run and sets its ID
+func (s *Store) Add(run *Run) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(runsBucket)
+
+		id, err := b.NextSequence()
+		if err != nil {
+			return fmt.Errorf("next run id: %w", err)
+		}
+		run.ID = id
+
+		v, err := json.Marshal(run)
+		if err != nil {
+			return fmt.Errorf("marshal run: %w", err)
+		}
+
+		return b.Put(key(id), v)
+	})
+}
+
+// Get returns the run with the given ID
+func (s *Store) Get(id uint64) (run Run, err error) {
+	err = s.db.View(func(tx *bolt.Tx) error {
+		v := tx.Bucket(runsBucket).Get(key(id))
+		if v == nil {
+			return fmt.Errorf("run %d: %w", id, ErrNotFound)
+		}
+		return json.Unmarshal(v, &run)
+	})
+	return run, err
+}
+
+// Runs returns the most recent runs, newest first. If limit is not positive,
+// all runs are returned.
+func (s *Store) Runs(limit int) (runs []Run, err error) {
+	err = s.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(runsBucket).Cursor()
+		for k, v := c.Last(); k != nil; k, v = c.Prev() {
+			if limit > 0 && len(runs) >= limit {
+				break
+			}
+			var run Run
+			if err := json.Unmarshal(v, &run); err != nil {
+				return fmt.Errorf("unmarshal run %d: %w", binary.BigEndian.Uint64(k), err)
+			}
+			runs = append(runs, run)
+		}
+		return nil
+	})
+	return runs, err
+}
+
+// key returns the database key for the run ID. Keys are big endian encoded,
+// so runs are ordered by their IDs.
+func key(id uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, id)
+	return b
+}
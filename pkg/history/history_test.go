@@ -0,0 +1,99 @@
+package history_test
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/beekeeper/pkg/history"
+	"github.com/ethersphere/beekeeper/pkg/report"
+)
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.db")
+
+	store, err := history.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := range 3 {
+		run := history.NewRun(&report.Report{
+			Name:    "check",
+			Cluster: "local",
+			Results: []report.Result{
+				{Name: "ci-pingpong", Status: report.StatusFailed, Duration: time.Duration(i) * time.Second, Error: errors.New("failed")},
+			},
+		}, map[string]string{"bee-1": "2.5.0"})
+		if err := store.Add(&run); err != nil {
+			t.Fatal(err)
+		}
+		if run.ID != uint64(i+1) {
+			t.Fatalf("got id %d, want %d", run.ID, i+1)
+		}
+	}
+
+	if err := store.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// runs must persist after reopening
+	store, err = history.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	runs, err := store.Runs(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 2 || runs[0].ID != 3 || runs[1].ID != 2 {
+		t.Fatalf("got runs %+v, want runs 3 and 2", runs)
+	}
+
+	run, err := store.Get(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run.Cluster != "local" || run.BeeVersions["bee-1"] != "2.5.0" || run.Results[0].Duration != time.Second || run.Results[0].Error != "failed" {
+		t.Fatalf("unexpected run: %+v", run)
+	}
+
+	if _, err := store.Get(4); !errors.Is(err, history.ErrNotFound) {
+		t.Fatalf("got error %v, want %v", err, history.ErrNotFound)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	base := history.Run{Results: []history.Result{
+		{Name: "a", Status: report.StatusPassed, Duration: 10 * time.Second},
+		{Name: "b", Status: report.StatusPassed, Duration: 10 * time.Second},
+		{Name: "c", Status: report.StatusPassed, Duration: 10 * time.Second},
+		{Name: "d", Status: report.StatusFailed, Duration: 10 * time.Second},
+		{Name: "e", Status: report.StatusPassed, Duration: 10 * time.Second},
+	}}
+	target := history.Run{Results: []history.Result{
+		{Name: "a", Status: report.StatusPassed, Duration: 11 * time.Second},
+		{Name: "b", Status: report.StatusFlaky, Duration: 15 * time.Second},
+		{Name: "c", Status: report.StatusFailed, Duration: 5 * time.Second},
+		{Name: "d", Status: report.StatusFailed, Duration: 30 * time.Second},
+		{Name: "f", Status: report.StatusPassed, Duration: 10 * time.Second},
+	}}
+
+	want := map[string]bool{"a": false, "b": true, "c": true, "d": false, "e": false, "f": false}
+
+	comparisons := history.Compare(base, target, 20)
+	if len(comparisons) != len(want) {
+		t.Fatalf("got %d comparisons, want %d", len(comparisons), len(want))
+	}
+	for _, c := range comparisons {
+		if c.Regressed != want[c.Name] {
+			t.Errorf("%s: got regressed %v, want %v (%s)", c.Name, c.Regressed, want[c.Name], c.Reason)
+		}
+	}
+	if c := comparisons[1]; c.Name != "b" || c.DurationChange != 50 {
+		t.Fatalf("got %s change %v, want b change 50", c.Name, c.DurationChange)
+	}
+}
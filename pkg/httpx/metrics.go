@@ -0,0 +1,188 @@
+package httpx
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	m "github.com/ethersphere/beekeeper/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// OtherRoute is the route label of requests to paths that are not Bee API
+// routes
+const OtherRoute = "other"
+
+// apiVersionPrefix is the version prefix of paths of the Bee API, which is
+// not part of the route label
+const apiVersionPrefix = "/v1"
+
+// routes are templates of Bee API paths used as the route label, so that
+// references, addresses and amounts do not create new series
+var routes = []string{
+	"/accounting",
+	"/addresses",
+	"/balances",
+	"/balances/{peer}",
+	"/bytes",
+	"/bytes/{ref}",
+	"/bzz",
+	"/bzz/{ref}",
+	"/bzz/{ref}/{path...}",
+	"/chainstate",
+	"/chequebook/balance",
+	"/chequebook/cashout/{peer}",
+	"/chunks",
+	"/chunks/{ref}",
+	"/connect/{multiaddr...}",
+	"/debugstore",
+	"/feeds/{owner}/{topic}",
+	"/grantee",
+	"/grantee/{ref}",
+	"/health",
+	"/peers",
+	"/peers/{peer}",
+	"/pingpong/{peer}",
+	"/pins",
+	"/pins/{ref}",
+	"/pss/send/{topic}/{targets}",
+	"/rchash/{depth}/{anchor1}/{anchor2}",
+	"/readiness",
+	"/redistributionstate",
+	"/reservestate",
+	"/settlements",
+	"/settlements/{peer}",
+	"/soc/{owner}/{id}",
+	"/stake",
+	"/stake/withdrawable",
+	"/stake/{amount}",
+	"/stamps",
+	"/stamps/dilute/{id}/{depth}",
+	"/stamps/topup/{id}/{amount}",
+	"/stamps/{id}",
+	"/stamps/{amount}/{depth}",
+	"/status",
+	"/stewardship/{ref}",
+	"/tags",
+	"/tags/{id}",
+	"/topology",
+	"/transactions",
+	"/transactions/{hash}",
+	"/wallet",
+	"/wallet/withdraw/{coin}",
+}
+
+// routeMux matches request paths to routes
+var routeMux = func() *http.ServeMux {
+	mux := http.NewServeMux()
+	for _, r := range routes {
+		mux.HandleFunc(r, http.NotFound)
+	}
+	return mux
+}()
+
+// Route returns the templated Bee API route of the path, for example
+// "/bytes/{ref}" for "/v1/bytes/<reference>", or OtherRoute if the path does
+// not match any route.
+func Route(path string) string {
+	if p, ok := strings.CutPrefix(path, apiVersionPrefix); ok && (p == "" || p[0] == '/') {
+		path = p
+	}
+
+	_, pattern := routeMux.Handler(&http.Request{Method: http.MethodGet, URL: &url.URL{Path: path}})
+	if pattern == "" {
+		return OtherRoute
+	}
+	return pattern
+}
+
+// Metrics represents metrics of requests to the Bee API
+type Metrics struct {
+	// all metrics fields must be exported
+	// to be able to return them by Report()
+	// using reflection
+	RequestDuration *prometheus.HistogramVec
+	ResponseCount   *prometheus.CounterVec
+	RetryCount      *prometheus.CounterVec
+}
+
+// NewMetrics returns new metrics of requests to the Bee API
+func NewMetrics() *Metrics {
+	subsystem := "bee_api"
+
+	labels := []string{"route", "method", "node", "node_group"}
+
+	return &Metrics{
+		RequestDuration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: m.Namespace,
+				Subsystem: subsystem,
+				Name:      "request_duration_seconds",
+				Help:      "Duration of Bee API requests until the response headers are received.",
+				Buckets:   prometheus.ExponentialBuckets(0.005, 2, 14),
+			},
+			labels,
+		),
+		ResponseCount: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: m.Namespace,
+				Subsystem: subsystem,
+				Name:      "response_count",
+				Help:      "Number of Bee API responses by status code, with status error for requests without response.",
+			},
+			append(labels, "status"),
+		),
+		RetryCount: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: m.Namespace,
+				Subsystem: subsystem,
+				Name:      "retry_count",
+				Help:      "Number of Bee API requests retried by the retry policy of the client.",
+			},
+			labels,
+		),
+	}
+}
+
+// Retried records a retry of the request to the API of the node
+func (ms *Metrics) Retried(req *http.Request, node, nodeGroup string) {
+	ms.RetryCount.WithLabelValues(Route(req.URL.Path), req.Method, node, nodeGroup).Inc()
+}
+
+// Report returns collectors of the metrics
+func (ms *Metrics) Report() []prometheus.Collector {
+	return m.PrometheusCollectorsFromFields(ms)
+}
+
+// MetricsRoundTripper records metrics of requests to the API of a node
+type MetricsRoundTripper struct {
+	Next      http.RoundTripper
+	Metrics   *Metrics
+	Node      string
+	NodeGroup string
+}
+
+func (mrt *MetricsRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	r, err := mrt.Next.RoundTrip(req)
+	duration := time.Since(start)
+
+	labels := prometheus.Labels{
+		"route":      Route(req.URL.Path),
+		"method":     req.Method,
+		"node":       mrt.Node,
+		"node_group": mrt.NodeGroup,
+	}
+	mrt.Metrics.RequestDuration.With(labels).Observe(duration.Seconds())
+
+	status := "error"
+	if err == nil {
+		status = strconv.Itoa(r.StatusCode)
+	}
+	labels["status"] = status
+	mrt.Metrics.ResponseCount.With(labels).Inc()
+
+	return r, err
+}
@@ -0,0 +1,93 @@
+package httpx_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethersphere/beekeeper/pkg/httpx"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestRoute(t *testing.T) {
+	for _, tc := range []struct {
+		path string
+		want string
+	}{
+		{path: "/health", want: "/health"},
+		{path: "/v1/bytes", want: "/bytes"},
+		{path: "/v1/bytes/36b7efd913ca4cf880b8eeac5093fa27b0825906c600685b6abdd6566e6cfe8f", want: "/bytes/{ref}"},
+		{path: "/v1/bzz/36b7efd913ca4cf880b8eeac5093fa27b0825906c600685b6abdd6566e6cfe8f/dir/index.html", want: "/bzz/{ref}/{path...}"},
+		{path: "/connect/ip4/127.0.0.1/tcp/1634/p2p/QmHash", want: "/connect/{multiaddr...}"},
+		{path: "/stake/withdrawable", want: "/stake/withdrawable"},
+		{path: "/stake/1000", want: "/stake/{amount}"},
+		{path: "/stamps/1000/17", want: "/stamps/{amount}/{depth}"},
+		{path: "/stamps/topup/abcd/1000", want: "/stamps/topup/{id}/{amount}"},
+		{path: "/v1", want: httpx.OtherRoute},
+		{path: "/unknown/path", want: httpx.OtherRoute},
+	} {
+		if got := httpx.Route(tc.path); got != tc.want {
+			t.Errorf("route of %s: got %s, want %s", tc.path, got, tc.want)
+		}
+	}
+}
+
+func TestMetricsRoundTripper(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/health" {
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer s.Close()
+
+	m := httpx.NewMetrics()
+	c := &http.Client{Transport: &httpx.MetricsRoundTripper{
+		Next:      http.DefaultTransport,
+		Metrics:   m,
+		Node:      "bee-0",
+		NodeGroup: "bee",
+	}}
+
+	for _, path := range []string{"/health", "/health", "/v1/bytes/abcd"} {
+		r, err := c.Get(s.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r.Body.Close()
+	}
+
+	if got := testutil.ToFloat64(m.ResponseCount.WithLabelValues("/health", http.MethodGet, "bee-0", "bee", "200")); got != 2 {
+		t.Errorf("got %v responses of /health, want 2", got)
+	}
+	if got := testutil.ToFloat64(m.ResponseCount.WithLabelValues("/bytes/{ref}", http.MethodGet, "bee-0", "bee", "404")); got != 1 {
+		t.Errorf("got %v responses of /bytes/{ref}, want 1", got)
+	}
+	if got := testutil.CollectAndCount(m.RequestDuration); got != 2 {
+		t.Errorf("got %d duration series, want 2", got)
+	}
+
+	c.Transport.(*httpx.MetricsRoundTripper).Next = failingRoundTripper{}
+	if _, err := c.Get(s.URL + "/health"); err == nil {
+		t.Fatal("expected error")
+	}
+	if got := testutil.ToFloat64(m.ResponseCount.WithLabelValues("/health", http.MethodGet, "bee-0", "bee", "error")); got != 1 {
+		t.Errorf("got %v failed requests, want 1", got)
+	}
+
+	m.Retried(httptest.NewRequest(http.MethodPost, "/v1/bytes", nil), "bee-0", "bee")
+	if got := testutil.ToFloat64(m.RetryCount.WithLabelValues("/bytes", http.MethodPost, "bee-0", "bee")); got != 1 {
+		t.Errorf("got %v retries of /bytes, want 1", got)
+	}
+
+	if got := len(m.Report()); got != 3 {
+		t.Errorf("got %d collectors, want 3", got)
+	}
+}
+
+type failingRoundTripper struct{}
+
+func (failingRoundTripper) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, errors.New("connection failed")
+}
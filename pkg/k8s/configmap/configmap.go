@@ -60,6 +60,15 @@ func (c *Client) Set(ctx context.Context, name, namespace string, o Options) (cm
 	return cm, err
 }
 
+// Get returns ConfigMap
+func (c *Client) Get(ctx context.Context, name, namespace string) (*v1.ConfigMap, error) {
+	cm, err := c.clientset.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("getting configmap %s in namespace %s: %w", name, namespace, err)
+	}
+	return cm, nil
+}
+
 // Delete deletes ConfigMap
 func (c *Client) Delete(ctx context.Context, name, namespace string) (err error) {
 	err = c.clientset.CoreV1().ConfigMaps(namespace).Delete(ctx, name, metav1.DeleteOptions{})
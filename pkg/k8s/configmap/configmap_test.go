@@ -166,3 +166,61 @@ func TestDelete(t *testing.T) {
 		})
 	}
 }
+
+func TestGet(t *testing.T) {
+	t.Parallel()
+	testTable := []struct {
+		name      string
+		objName   string
+		clientset kubernetes.Interface
+		want      map[string]string
+		errorMsg  error
+	}{
+		{
+			name:    "get_config_map",
+			objName: "test_config_map",
+			clientset: fake.NewSimpleClientset(&v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test_config_map",
+					Namespace: "test",
+				},
+				Data: map[string]string{"key": "value"},
+			}),
+			want: map[string]string{"key": "value"},
+		},
+		{
+			name:      "get_not_found",
+			objName:   "test_config_map",
+			clientset: fake.NewSimpleClientset(),
+			errorMsg:  fmt.Errorf(`getting configmap test_config_map in namespace test: configmaps "test_config_map" not found`),
+		},
+		{
+			name:      "get_error",
+			objName:   "test_config_map",
+			clientset: k8stest.NewErrorClientset("get", "configmaps", errors.New("mock error: cannot get configmap")),
+			errorMsg:  fmt.Errorf("getting configmap test_config_map in namespace test: mock error: cannot get configmap"),
+		},
+	}
+
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			client := configmap.NewClient(test.clientset)
+			got, err := client.Get(t.Context(), test.objName, "test")
+			if test.errorMsg == nil {
+				if err != nil {
+					t.Fatalf("error not expected, got: %s", err.Error())
+				}
+				if !reflect.DeepEqual(got.Data, test.want) {
+					t.Errorf("response expected: %v, got: %v", test.want, got.Data)
+				}
+			} else {
+				if err == nil {
+					t.Fatalf("error not happened, expected: %s", test.errorMsg.Error())
+				}
+				if err.Error() != test.errorMsg.Error() {
+					t.Errorf("error expected: %s, got: %s", test.errorMsg.Error(), err.Error())
+				}
+			}
+		})
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/ethersphere/beekeeper/pkg/k8s/customresource/ingressroute"
 	"github.com/ethersphere/beekeeper/pkg/k8s/ingress"
 	"github.com/ethersphere/beekeeper/pkg/k8s/namespace"
+	"github.com/ethersphere/beekeeper/pkg/k8s/networkpolicy"
 	"github.com/ethersphere/beekeeper/pkg/k8s/persistentvolumeclaim"
 	"github.com/ethersphere/beekeeper/pkg/k8s/pod"
 	"github.com/ethersphere/beekeeper/pkg/k8s/secret"
@@ -41,6 +42,7 @@ type Client struct {
 	ConfigMap      *configmap.Client
 	Ingress        *ingress.Client
 	Namespace      *namespace.Client
+	NetworkPolicy  *networkpolicy.Client
 	Pods           *pod.Client
 	PVC            *persistentvolumeclaim.Client
 	Secret         *secret.Client
@@ -130,6 +132,7 @@ func (c *Client) setK8sClient(clientset kubernetes.Interface, apiClientset ingre
 	c.ConfigMap = configmap.NewClient(clientset)
 	c.Ingress = ingress.NewClient(clientset, c.logger)
 	c.Namespace = namespace.NewClient(clientset)
+	c.NetworkPolicy = networkpolicy.NewClient(clientset)
 	c.Pods = pod.NewClient(clientset, c.logger)
 	c.PVC = persistentvolumeclaim.NewClient(clientset)
 	c.Secret = secret.NewClient(clientset)
@@ -0,0 +1,70 @@
+package networkpolicy
+
+import (
+	"context"
+	"fmt"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Client manages communication with the Kubernetes NetworkPolicy.
+type Client struct {
+	clientset kubernetes.Interface
+}
+
+// NewClient constructs a new Client.
+func NewClient(clientset kubernetes.Interface) *Client {
+	return &Client{
+		clientset: clientset,
+	}
+}
+
+// Options holds optional parameters for the Client.
+type Options struct {
+	Annotations map[string]string
+	Labels      map[string]string
+	Spec        networkingv1.NetworkPolicySpec
+}
+
+// Set updates NetworkPolicy or creates it if it does not exist
+func (c *Client) Set(ctx context.Context, name, namespace string, o Options) (np *networkingv1.NetworkPolicy, err error) {
+	spec := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   namespace,
+			Annotations: o.Annotations,
+			Labels:      o.Labels,
+		},
+		Spec: o.Spec,
+	}
+
+	np, err = c.clientset.NetworkingV1().NetworkPolicies(namespace).Update(ctx, spec, metav1.UpdateOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			np, err = c.clientset.NetworkingV1().NetworkPolicies(namespace).Create(ctx, spec, metav1.CreateOptions{})
+			if err != nil {
+				return nil, fmt.Errorf("creating network policy %s in namespace %s: %w", name, namespace, err)
+			}
+		} else {
+			return nil, fmt.Errorf("updating network policy %s in namespace %s: %w", name, namespace, err)
+		}
+	}
+
+	return np, err
+}
+
+// Delete deletes NetworkPolicy
+func (c *Client) Delete(ctx context.Context, name, namespace string) (err error) {
+	err = c.clientset.NetworkingV1().NetworkPolicies(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("deleting network policy %s in namespace %s: %w", name, namespace, err)
+	}
+
+	return err
+}
@@ -0,0 +1,162 @@
+package networkpolicy_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/ethersphere/beekeeper/pkg/k8s/internal/k8stest"
+	"github.com/ethersphere/beekeeper/pkg/k8s/networkpolicy"
+)
+
+func TestSet(t *testing.T) {
+	t.Parallel()
+	testTable := []struct {
+		name       string
+		policyName string
+		options    networkpolicy.Options
+		clientset  kubernetes.Interface
+		errorMsg   error
+	}{
+		{
+			name:       "create_network_policy",
+			policyName: "test_network_policy",
+			clientset:  fake.NewSimpleClientset(),
+			options: networkpolicy.Options{
+				Annotations: map[string]string{"annotation_1": "annotation_value_1"},
+				Labels:      map[string]string{"label_1": "label_value_1"},
+				Spec: networkingv1.NetworkPolicySpec{
+					PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "bee"}},
+					PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+				},
+			},
+		},
+		{
+			name:       "update_network_policy",
+			policyName: "test_network_policy",
+			clientset: fake.NewSimpleClientset(&networkingv1.NetworkPolicy{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test_network_policy",
+					Namespace: "test",
+					Labels:    map[string]string{"label_1": "label_value_1"},
+				},
+			}),
+			options: networkpolicy.Options{
+				Labels: map[string]string{"label_1": "label_value_updated"},
+				Spec: networkingv1.NetworkPolicySpec{
+					PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress},
+				},
+			},
+		},
+		{
+			name:       "create_error",
+			policyName: "test_network_policy",
+			// No object seeded, so Update returns NotFound and Set falls through
+			// to Create, which the reactor fails.
+			clientset: k8stest.NewErrorClientset("create", "networkpolicies", errors.New("mock error: cannot create network policy")),
+			errorMsg:  fmt.Errorf("creating network policy test_network_policy in namespace test: mock error: cannot create network policy"),
+		},
+		{
+			name:       "update_error",
+			policyName: "test_network_policy",
+			clientset:  k8stest.NewErrorClientset("update", "networkpolicies", errors.New("mock error: cannot update network policy")),
+			errorMsg:   fmt.Errorf("updating network policy test_network_policy in namespace test: mock error: cannot update network policy"),
+		},
+	}
+
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			client := networkpolicy.NewClient(test.clientset)
+			response, err := client.Set(t.Context(), test.policyName, "test", test.options)
+			if test.errorMsg == nil {
+				if err != nil {
+					t.Errorf("error not expected, got: %s", err.Error())
+				}
+				if response == nil {
+					t.Fatalf("response is expected")
+				}
+
+				expected := &networkingv1.NetworkPolicy{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        test.policyName,
+						Namespace:   "test",
+						Annotations: test.options.Annotations,
+						Labels:      test.options.Labels,
+					},
+					Spec: test.options.Spec,
+				}
+
+				if !reflect.DeepEqual(response, expected) {
+					t.Errorf("response expected: %v, got: %v", expected, response)
+				}
+			} else {
+				if err == nil {
+					t.Fatalf("error not happened, expected: %s", test.errorMsg.Error())
+				}
+				if err.Error() != test.errorMsg.Error() {
+					t.Errorf("error expected: %s, got: %s", test.errorMsg.Error(), err.Error())
+				}
+				if response != nil {
+					t.Errorf("response not expected")
+				}
+			}
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	t.Parallel()
+	testTable := []struct {
+		name       string
+		policyName string
+		clientset  kubernetes.Interface
+		errorMsg   error
+	}{
+		{
+			name:       "delete_network_policy",
+			policyName: "test_network_policy",
+			clientset: fake.NewSimpleClientset(&networkingv1.NetworkPolicy{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test_network_policy",
+					Namespace: "test",
+				},
+			}),
+		},
+		{
+			name:       "delete_not_found",
+			policyName: "test_network_policy_not_found",
+			clientset:  fake.NewSimpleClientset(),
+		},
+		{
+			name:       "delete_error",
+			policyName: "test_network_policy",
+			clientset:  k8stest.NewErrorClientset("delete", "networkpolicies", errors.New("mock error: cannot delete network policy")),
+			errorMsg:   fmt.Errorf("deleting network policy test_network_policy in namespace test: mock error: cannot delete network policy"),
+		},
+	}
+
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			client := networkpolicy.NewClient(test.clientset)
+			err := client.Delete(t.Context(), test.policyName, "test")
+			if test.errorMsg == nil {
+				if err != nil {
+					t.Errorf("error not expected, got: %s", err.Error())
+				}
+			} else {
+				if err == nil {
+					t.Fatalf("error not happened, expected: %s", test.errorMsg.Error())
+				}
+				if err.Error() != test.errorMsg.Error() {
+					t.Errorf("error expected: %s, got: %s", test.errorMsg.Error(), err.Error())
+				}
+			}
+		})
+	}
+}
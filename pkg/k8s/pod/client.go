@@ -85,6 +85,34 @@ func (c *Client) Delete(ctx context.Context, name, namespace string) (ok bool, e
 	return true, nil
 }
 
+// List returns Pods in the namespace that match the label selector
+func (c *Client) List(ctx context.Context, namespace, labelSelector string) ([]v1.Pod, error) {
+	pods, err := c.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labelSelector,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing pods in namespace %s: %w", namespace, err)
+	}
+
+	return pods.Items, nil
+}
+
+// Logs returns the last tailLines lines of logs of the Pod's container. If
+// tailLines is not positive, all lines are returned.
+func (c *Client) Logs(ctx context.Context, name, namespace, container string, tailLines int64) ([]byte, error) {
+	opts := &v1.PodLogOptions{Container: container}
+	if tailLines > 0 {
+		opts.TailLines = &tailLines
+	}
+
+	logs, err := c.clientset.CoreV1().Pods(namespace).GetLogs(name, opts).DoRaw(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting logs of pod %s container %s in namespace %s: %w", name, container, namespace, err)
+	}
+
+	return logs, nil
+}
+
 func (c *Client) DeletePods(ctx context.Context, namespace, labelSelector string) (int, error) {
 	pods, err := c.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
 		LabelSelector: labelSelector,
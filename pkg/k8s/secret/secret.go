@@ -62,6 +62,15 @@ func (c *Client) Set(ctx context.Context, name, namespace string, o Options) (sc
 	return sc, err
 }
 
+// Get returns Secret
+func (c *Client) Get(ctx context.Context, name, namespace string) (*v1.Secret, error) {
+	sc, err := c.clientset.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("getting secret %s in namespace %s: %w", name, namespace, err)
+	}
+	return sc, nil
+}
+
 // Delete deletes Secret
 func (c *Client) Delete(ctx context.Context, name, namespace string) (err error) {
 	err = c.clientset.CoreV1().Secrets(namespace).Delete(ctx, name, metav1.DeleteOptions{})
@@ -172,3 +172,61 @@ func TestDelete(t *testing.T) {
 		})
 	}
 }
+
+func TestGet(t *testing.T) {
+	t.Parallel()
+	testTable := []struct {
+		name      string
+		objName   string
+		clientset kubernetes.Interface
+		want      map[string][]byte
+		errorMsg  error
+	}{
+		{
+			name:    "get_secret",
+			objName: "test_secret",
+			clientset: fake.NewSimpleClientset(&v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test_secret",
+					Namespace: "test",
+				},
+				Data: map[string][]byte{"key": []byte("value")},
+			}),
+			want: map[string][]byte{"key": []byte("value")},
+		},
+		{
+			name:      "get_not_found",
+			objName:   "test_secret",
+			clientset: fake.NewSimpleClientset(),
+			errorMsg:  fmt.Errorf(`getting secret test_secret in namespace test: secrets "test_secret" not found`),
+		},
+		{
+			name:      "get_error",
+			objName:   "test_secret",
+			clientset: k8stest.NewErrorClientset("get", "secrets", errors.New("mock error: cannot get secret")),
+			errorMsg:  fmt.Errorf("getting secret test_secret in namespace test: mock error: cannot get secret"),
+		},
+	}
+
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			client := secret.NewClient(test.clientset)
+			got, err := client.Get(t.Context(), test.objName, "test")
+			if test.errorMsg == nil {
+				if err != nil {
+					t.Fatalf("error not expected, got: %s", err.Error())
+				}
+				if !reflect.DeepEqual(got.Data, test.want) {
+					t.Errorf("response expected: %v, got: %v", test.want, got.Data)
+				}
+			} else {
+				if err == nil {
+					t.Fatalf("error not happened, expected: %s", test.errorMsg.Error())
+				}
+				if err.Error() != test.errorMsg.Error() {
+					t.Errorf("error expected: %s, got: %s", test.errorMsg.Error(), err.Error())
+				}
+			}
+		})
+	}
+}
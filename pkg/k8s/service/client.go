@@ -7,6 +7,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/kubernetes"
 )
 
@@ -134,3 +135,25 @@ func (c *Client) FindNode(ctx context.Context, namespace string, pod *v1.Pod) (*
 
 	return nil, nil, fmt.Errorf("no matching service found for pod %s", pod.Name)
 }
+
+// FindPods returns the pods selected by the Service with the name, which are
+// the pods of the node whose API the Service exposes.
+func (c *Client) FindPods(ctx context.Context, namespace, name string) ([]v1.Pod, error) {
+	svc, err := c.clientset.CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("getting service %s in namespace %s: %w", name, namespace, err)
+	}
+
+	if len(svc.Spec.Selector) == 0 {
+		return nil, fmt.Errorf("service %s in namespace %s has no pod selector", name, namespace)
+	}
+
+	pods, err := c.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(svc.Spec.Selector).String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing pods in namespace %s: %w", namespace, err)
+	}
+
+	return pods.Items, nil
+}
@@ -343,6 +343,68 @@ func TestFindNode(t *testing.T) {
 	}
 }
 
+func TestFindPods(t *testing.T) {
+	t.Parallel()
+	pod := func(name string, labels map[string]string) *v1.Pod {
+		return &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test", Labels: labels}}
+	}
+
+	testTable := []struct {
+		name         string
+		clientset    kubernetes.Interface
+		expectedPods []string
+		errorMsg     error
+	}{
+		{
+			name: "pods_found",
+			clientset: fake.NewClientset(
+				selectorSvc("bee-0", "10.0.0.1", map[string]string{"app": "bee", "node": "0"}, v1.ServicePort{Name: "api", Port: 1633}),
+				pod("bee-0-7d9f8", map[string]string{"app": "bee", "node": "0"}),
+				pod("bee-1-5c4b2", map[string]string{"app": "bee", "node": "1"}),
+			),
+			expectedPods: []string{"bee-0-7d9f8"},
+		},
+		{
+			name: "no_selector",
+			clientset: fake.NewClientset(
+				svc("bee-0", "10.0.0.1", nil, v1.ServicePort{Name: "api", Port: 1633}),
+			),
+			errorMsg: fmt.Errorf("service bee-0 in namespace test has no pod selector"),
+		},
+		{
+			name:      "service_not_found",
+			clientset: fake.NewClientset(),
+			errorMsg:  fmt.Errorf(`getting service bee-0 in namespace test: services "bee-0" not found`),
+		},
+	}
+
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			client := service.NewClient(test.clientset)
+			pods, err := client.FindPods(t.Context(), "test", "bee-0")
+			if test.errorMsg == nil {
+				if err != nil {
+					t.Errorf("error not expected, got: %s", err.Error())
+				}
+				var names []string
+				for _, p := range pods {
+					names = append(names, p.Name)
+				}
+				if !reflect.DeepEqual(names, test.expectedPods) {
+					t.Errorf("pods expected: %v, got: %v", test.expectedPods, names)
+				}
+			} else {
+				if err == nil {
+					t.Fatalf("error not happened, expected: %s", test.errorMsg.Error())
+				}
+				if err.Error() != test.errorMsg.Error() {
+					t.Errorf("error expected: %s, got: %s", test.errorMsg.Error(), err.Error())
+				}
+			}
+		})
+	}
+}
+
 // selectorSvc builds a Service with a spec Selector (used by FindNode) in
 // namespace "test".
 func selectorSvc(name string, clusterIP string, selector map[string]string, ports ...v1.ServicePort) *v1.Service {
@@ -1,10 +1,10 @@
 package metrics
 
 import (
+	"errors"
 	"reflect"
 
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/prometheus/client_golang/prometheus/push"
 )
 
 // Namespace is prefixed before every metric. If it is changed, it must be done
@@ -28,8 +28,16 @@ func PrometheusCollectorsFromFields(i any) (cs []prometheus.Collector) {
 	return cs
 }
 
-func RegisterCollectors(p *push.Pusher, c ...prometheus.Collector) {
+// RegisterCollectors registers collectors with the registerer. Collectors that
+// are already registered are skipped. It returns the first registration error.
+func RegisterCollectors(r prometheus.Registerer, c ...prometheus.Collector) error {
 	for _, cc := range c {
-		p.Collector(cc)
+		if err := r.Register(cc); err != nil {
+			if errors.As(err, new(prometheus.AlreadyRegisteredError)) {
+				continue
+			}
+			return err
+		}
 	}
+	return nil
 }
@@ -110,7 +110,9 @@ func (sc *Client) GetNodes(ctx context.Context) (nodes NodeList, err error) {
 
 	nodes = make(NodeList, 0, len(filteredClients))
 	for _, beeClient := range filteredClients {
-		nodes = append(nodes, *NewNode(beeClient.API(), sc.nodeName(beeClient.Name())))
+		n := NewNode(beeClient.API(), sc.nodeName(beeClient.Name()))
+		n.nodeGroup = beeClient.NodeGroup()
+		nodes = append(nodes, *n)
 	}
 
 	return nodes.Sort(), nil
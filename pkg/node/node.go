@@ -7,8 +7,9 @@ import (
 )
 
 type Node struct {
-	client *api.Client
-	name   string
+	client    *api.Client
+	name      string
+	nodeGroup string // set only for nodes of Beekeeper clusters
 }
 
 type NodeList []Node
@@ -28,6 +29,12 @@ func (n *Node) Client() *api.Client {
 	return n.client
 }
 
+// NodeGroup returns the name of the node group of the node in the Beekeeper
+// cluster, or an empty string if the node was found in a namespace
+func (n *Node) NodeGroup() string {
+	return n.nodeGroup
+}
+
 func (ns NodeList) Get(name string) *Node {
 	for _, n := range ns {
 		if n.Name() == name {
@@ -0,0 +1,64 @@
+package nuker
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/ethersphere/beekeeper/pkg/bee/api"
+	"github.com/ethersphere/beekeeper/pkg/bee/api/beetest"
+	"github.com/ethersphere/beekeeper/pkg/node"
+)
+
+func TestNeighborhoodProvider(t *testing.T) {
+	s, c := beetest.New(t)
+	nodes := node.NodeList{*node.NewNode(c, "bee-0")}
+	restartArgs := []string{"bee", "start"}
+	ctx := context.Background()
+
+	args, err := newNeighborhoodProvider(nil, nodes, false).GetArgs(ctx, "bee-0", restartArgs)
+	if err != nil {
+		t.Fatalf("args: %v", err)
+	}
+	if !slices.Equal(args, restartArgs) {
+		t.Fatalf("args without random neighborhood: got %v, want %v", args, restartArgs)
+	}
+
+	p := newNeighborhoodProvider(nil, nodes, true)
+
+	if _, err := p.GetArgs(ctx, "bee-1", restartArgs); err == nil {
+		t.Fatal("args of unknown node: got no error")
+	}
+
+	s.Respond("GET /status", http.StatusOK, api.StatusResponse{StorageRadius: 0})
+	if args, err := p.GetArgs(ctx, "bee-0", restartArgs); err != nil || !slices.Equal(args, restartArgs) {
+		t.Fatalf("args with storage radius 0: got %v, err %v", args, err)
+	}
+
+	// all four neighborhoods of storage radius 2 are used once
+	s.Respond("GET /status", http.StatusOK, api.StatusResponse{StorageRadius: 2})
+	seen := make(map[string]bool)
+	for range 4 {
+		args, err := p.GetArgs(ctx, "bee-0", restartArgs)
+		if err != nil {
+			t.Fatalf("args: %v", err)
+		}
+		if len(args) != len(restartArgs)+1 || !strings.HasPrefix(args[len(args)-1], "--target-neighborhood=") {
+			t.Fatalf("args: got %v, want target neighborhood", args)
+		}
+		seen[args[len(args)-1]] = true
+	}
+	if len(seen) != 4 {
+		t.Fatalf("neighborhoods: got %d unique, want 4", len(seen))
+	}
+	if args, err := p.GetArgs(ctx, "bee-0", restartArgs); err != nil || !slices.Equal(args, restartArgs) {
+		t.Fatalf("args with exhausted neighborhoods: got %v, err %v", args, err)
+	}
+
+	s.Fail("GET /status", http.StatusInternalServerError, -1)
+	if _, err := p.GetArgs(ctx, "bee-0", restartArgs); err == nil {
+		t.Fatal("args with failing status: got no error")
+	}
+}
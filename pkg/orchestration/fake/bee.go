@@ -0,0 +1,101 @@
+package fake
+
+import (
+	"math/big"
+	"net/http"
+	"sync"
+
+	"github.com/ethersphere/bee/v2/pkg/swarm"
+	"github.com/ethersphere/beekeeper/pkg/bee/api/beetest"
+)
+
+var (
+	// walletBZZ is the BZZ balance of nodes, enough to buy postage batches
+	// and stake
+	walletBZZ = new(big.Int).Exp(big.NewInt(10), big.NewInt(21), nil)
+	// walletNativeToken is the native token balance of nodes
+	walletNativeToken = new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+)
+
+// beeNode is an in-process stand-in for the Bee API of a single node. It is a
+// beetest server connected to the network of the cluster, which plays the
+// redistribution game of the node.
+type beeNode struct {
+	name     string
+	overlay  swarm.Address
+	fullNode bool
+	network  *network
+	server   *beetest.Server
+
+	mu         sync.Mutex
+	stopped    bool
+	staked     *big.Int
+	stakeRound uint64 // round in which the stake reached the minimum stake
+}
+
+func newBeeNode(name string, fullNode bool, capacity uint64, n *network) *beeNode {
+	b := &beeNode{
+		name:     name,
+		overlay:  n.overlay(name),
+		fullNode: fullNode,
+		network:  n,
+		staked:   new(big.Int),
+	}
+
+	opts := []beetest.Option{
+		beetest.WithOverlay(b.overlay),
+		beetest.WithNetwork(b),
+		beetest.WithRedistribution(b),
+		beetest.WithCapacity(capacity),
+		beetest.WithWallet(walletBZZ, walletNativeToken),
+	}
+	if !fullNode {
+		opts = append(opts, beetest.WithLightNode())
+	}
+	b.server = beetest.NewServer(opts...)
+
+	return b
+}
+
+func (b *beeNode) close() {
+	b.server.Close()
+}
+
+func (b *beeNode) isStopped() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.stopped
+}
+
+// setStopped stops or starts the node. The API of a stopped node responds
+// with an error to all requests.
+func (b *beeNode) setStopped(stopped bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.stopped = stopped
+	if stopped {
+		b.server.Fail("/", http.StatusServiceUnavailable, -1)
+	} else {
+		b.server.Fail("/", http.StatusServiceUnavailable, 0)
+	}
+}
+
+// Peers returns other running nodes of the network
+func (b *beeNode) Peers() []beetest.Peer {
+	var peers []beetest.Peer
+	for _, p := range b.network.peers(b) {
+		peers = append(peers, beetest.Peer{Overlay: p.overlay, LightNode: !p.fullNode})
+	}
+	return peers
+}
+
+// Push stores the chunk on the running full nodes closest to its address
+func (b *beeNode) Push(ch swarm.Chunk) {
+	b.network.push(ch)
+}
+
+// Retrieve returns the chunk from any running node that stores it
+func (b *beeNode) Retrieve(addr swarm.Address) ([]byte, bool) {
+	return b.network.retrieve(addr)
+}
@@ -0,0 +1,432 @@
+// Package fake implements an in-memory orchestration backend for unit tests
+// of checks and simulations. Every node is served by an in-process beetest
+// server, the mock Bee API of the api package tests, connected to the other
+// nodes of the cluster, so that checks can run against it without
+// Kubernetes.
+//
+// All running nodes are connected to each other. Overlays are derived from
+// the cluster and node names, making tests deterministic. Uploaded chunks
+// are stored on the full nodes closest to the chunk address, the versioned
+// chunks endpoint retrieves them from any node, and the unversioned one
+// reports only chunks stored on the node itself. Files and collections are
+// stored without manifests, on the uploading node and on the closest full
+// nodes. Nodes with a cache capacity keep at most that many unpinned chunks,
+// evicting the oldest ones.
+//
+// Full nodes that stake take part in a simulated storage incentives game with
+// short rounds, in which they form a single neighborhood and win in turns.
+package fake
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"math/rand"
+	"net/http"
+	"slices"
+	"sort"
+
+	"github.com/ethersphere/bee/v2/pkg/swarm"
+	"github.com/ethersphere/beekeeper/pkg/bee"
+	"github.com/ethersphere/beekeeper/pkg/logging"
+	"github.com/ethersphere/beekeeper/pkg/orchestration"
+)
+
+// compile check whether client implements interface
+var _ orchestration.Cluster = (*Cluster)(nil)
+
+// Cluster represents cluster of Bee nodes backed by in-process Bee API
+// stand-ins
+type Cluster struct {
+	name       string
+	opts       orchestration.ClusterOptions
+	network    *network
+	nodeGroups map[string]*NodeGroup
+	httpClient *http.Client
+	log        logging.Logger
+}
+
+// NewCluster returns new cluster without node groups. Close must be called
+// to shut down the stand-ins of its nodes.
+func NewCluster(name string, o orchestration.ClusterOptions, log logging.Logger) *Cluster {
+	if log == nil {
+		log = logging.New(io.Discard, 0)
+	}
+
+	return &Cluster{
+		name:       name,
+		opts:       o,
+		network:    newNetwork(name),
+		nodeGroups: make(map[string]*NodeGroup),
+		httpClient: &http.Client{},
+		log:        log,
+	}
+}
+
+// AddNodeGroup adds new node group to the cluster
+func (c *Cluster) AddNodeGroup(name string, o orchestration.NodeGroupOptions) {
+	c.nodeGroups[name] = newNodeGroup(name, o, c.network, c.httpClient, c.log)
+}
+
+// AddNodes adds count nodes named <group>-<index> with the given
+// configuration to the node group, adding the node group if it does not exist
+func (c *Cluster) AddNodes(ctx context.Context, group string, count int, config orchestration.Config) error {
+	if _, ok := c.nodeGroups[group]; !ok {
+		c.AddNodeGroup(group, orchestration.NodeGroupOptions{})
+	}
+	ng := c.nodeGroups[group]
+
+	for i := range count {
+		cfg := config
+		if err := ng.AddNode(ctx, fmt.Sprintf("%s-%d", group, i), false, orchestration.NodeOptions{Config: &cfg}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Close shuts down stand-ins of all nodes in the cluster
+func (c *Cluster) Close() {
+	for _, ng := range c.nodeGroups {
+		ng.close()
+	}
+}
+
+// Accounting returns ClusterAccounting
+func (c *Cluster) Accounting(ctx context.Context) (accounting orchestration.ClusterAccounting, err error) {
+	accounting = make(orchestration.ClusterAccounting)
+	for k, v := range c.nodeGroups {
+		a, err := v.Accounting(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", k, err)
+		}
+		accounting[k] = a
+	}
+	return accounting, nil
+}
+
+// Addresses returns ClusterAddresses
+func (c *Cluster) Addresses(ctx context.Context) (addrs map[string]orchestration.NodeGroupAddresses, err error) {
+	addrs = make(orchestration.ClusterAddresses)
+	for k, v := range c.nodeGroups {
+		a, err := v.Addresses(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", k, err)
+		}
+		addrs[k] = a
+	}
+	return addrs, nil
+}
+
+// Balances returns ClusterBalances
+func (c *Cluster) Balances(ctx context.Context) (balances orchestration.ClusterBalances, err error) {
+	balances = make(orchestration.ClusterBalances)
+	for k, v := range c.nodeGroups {
+		b, err := v.Balances(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", k, err)
+		}
+		balances[k] = b
+	}
+	return balances, nil
+}
+
+// FlattenAccounting returns aggregated NodeGroupAccounting
+func (c *Cluster) FlattenAccounting(ctx context.Context) (accounting orchestration.NodeGroupAccounting, err error) {
+	a, err := c.Accounting(ctx)
+	if err != nil {
+		return nil, err
+	}
+	accounting = make(orchestration.NodeGroupAccounting)
+	for _, v := range a {
+		maps.Copy(accounting, v)
+	}
+	return accounting, nil
+}
+
+// FlattenBalances returns aggregated NodeGroupBalances
+func (c *Cluster) FlattenBalances(ctx context.Context) (balances orchestration.NodeGroupBalances, err error) {
+	b, err := c.Balances(ctx)
+	if err != nil {
+		return nil, err
+	}
+	balances = make(orchestration.NodeGroupBalances)
+	for _, v := range b {
+		maps.Copy(balances, v)
+	}
+	return balances, nil
+}
+
+// FlattenOverlays returns aggregated ClusterOverlays excluding the provided node group names
+func (c *Cluster) FlattenOverlays(ctx context.Context, exclude ...string) (map[string]swarm.Address, error) {
+	o, err := c.Overlays(ctx, exclude...)
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[string]swarm.Address)
+	for _, v := range o {
+		maps.Copy(res, v)
+	}
+	return res, nil
+}
+
+// FlattenSettlements returns aggregated NodeGroupSettlements
+func (c *Cluster) FlattenSettlements(ctx context.Context) (settlements orchestration.NodeGroupSettlements, err error) {
+	s, err := c.Settlements(ctx)
+	if err != nil {
+		return nil, err
+	}
+	settlements = make(orchestration.NodeGroupSettlements)
+	for _, v := range s {
+		maps.Copy(settlements, v)
+	}
+	return settlements, nil
+}
+
+// FlattenTopologies returns an aggregate of Topologies
+func (c *Cluster) FlattenTopologies(ctx context.Context) (topologies map[string]bee.Topology, err error) {
+	t, err := c.Topologies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	topologies = make(map[string]bee.Topology)
+	for _, v := range t {
+		maps.Copy(topologies, v)
+	}
+	return topologies, nil
+}
+
+// FullNodeNames returns a sorted list of full node names
+func (c *Cluster) FullNodeNames() (names []string) {
+	for name, node := range c.Nodes() {
+		cfg := node.Config()
+		if cfg.FullNode && !cfg.BootnodeMode {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GlobalReplicationFactor returns the total number of nodes in the cluster that contain given chunk
+func (c *Cluster) GlobalReplicationFactor(ctx context.Context, a swarm.Address) (grf int, err error) {
+	for k, v := range c.nodeGroups {
+		ngrf, err := v.GroupReplicationFactor(ctx, a)
+		if err != nil {
+			return 0, fmt.Errorf("%s: %w", k, err)
+		}
+		grf += ngrf
+	}
+	return grf, nil
+}
+
+// LightNodeNames returns a sorted list of light node names
+func (c *Cluster) LightNodeNames() (names []string) {
+	for name, node := range c.Nodes() {
+		if !node.Config().FullNode {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Name returns name of the cluster
+func (c *Cluster) Name() string {
+	return c.name
+}
+
+// Namespace returns namespace of the cluster
+func (c *Cluster) Namespace() string {
+	return c.opts.Namespace
+}
+
+// NodeGroup returns node group
+func (c *Cluster) NodeGroup(name string) (ng orchestration.NodeGroup, err error) {
+	g, ok := c.nodeGroups[name]
+	if !ok {
+		return nil, fmt.Errorf("node group %s not found", name)
+	}
+	return g, nil
+}
+
+// NodeGroups returns map of node groups in the cluster
+func (c *Cluster) NodeGroups() (l map[string]orchestration.NodeGroup) {
+	l = make(map[string]orchestration.NodeGroup, len(c.nodeGroups))
+	for k, v := range c.nodeGroups {
+		l[k] = v
+	}
+	return l
+}
+
+// NodeNames returns a sorted list of node names in the cluster across all node groups
+func (c *Cluster) NodeNames() (names []string) {
+	for name := range c.Nodes() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Nodes returns map of nodes in the cluster
+func (c *Cluster) Nodes() map[string]orchestration.Node {
+	n := make(map[string]orchestration.Node)
+	for _, ng := range c.nodeGroups {
+		maps.Copy(n, ng.Nodes())
+	}
+	return n
+}
+
+// NodesClients returns map of node's clients in the cluster excluding stopped nodes
+func (c *Cluster) NodesClients(ctx context.Context) (map[string]*bee.Client, error) {
+	clients := make(map[string]*bee.Client)
+	for _, ng := range c.nodeGroups {
+		ngc, err := ng.NodesClients(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("nodes clients: %w", err)
+		}
+		maps.Copy(clients, ngc)
+	}
+	return clients, nil
+}
+
+// Overlays returns ClusterOverlays excluding the provided node group names
+func (c *Cluster) Overlays(ctx context.Context, exclude ...string) (overlays orchestration.ClusterOverlays, err error) {
+	overlays = make(orchestration.ClusterOverlays)
+	for k, v := range c.nodeGroups {
+		if slices.Contains(exclude, k) {
+			continue
+		}
+		o, err := v.Overlays(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", k, err)
+		}
+		overlays[k] = o
+	}
+	return overlays, nil
+}
+
+// Peers returns peers of all nodes in the cluster
+func (c *Cluster) Peers(ctx context.Context, exclude ...string) (peers orchestration.ClusterPeers, err error) {
+	peers = make(orchestration.ClusterPeers)
+	for k, v := range c.nodeGroups {
+		if slices.Contains(exclude, k) {
+			continue
+		}
+		p, err := v.Peers(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", k, err)
+		}
+		peers[k] = p
+	}
+	return peers, nil
+}
+
+// RandomNode returns random running node from a cluster
+func (c *Cluster) RandomNode(ctx context.Context, r *rand.Rand) (node orchestration.Node, err error) {
+	var running []orchestration.Node
+	nodes := c.Nodes()
+	for _, name := range c.NodeNames() {
+		n := nodes[name]
+		if ready, _ := n.Ready(ctx, c.Namespace()); ready {
+			running = append(running, n)
+		}
+	}
+	if len(running) == 0 {
+		return nil, fmt.Errorf("no running nodes")
+	}
+	return running[r.Intn(len(running))], nil
+}
+
+// Settlements returns ClusterSettlements
+func (c *Cluster) Settlements(ctx context.Context) (settlements orchestration.ClusterSettlements, err error) {
+	settlements = make(orchestration.ClusterSettlements)
+	for k, v := range c.nodeGroups {
+		s, err := v.Settlements(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", k, err)
+		}
+		settlements[k] = s
+	}
+	return settlements, nil
+}
+
+// ShuffledFullNodeClients returns a shuffled list of running full node clients
+func (c *Cluster) ShuffledFullNodeClients(ctx context.Context, r *rand.Rand) (orchestration.ClientList, error) {
+	clients, err := c.NodesClients(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var res orchestration.ClientList
+	for _, name := range c.FullNodeNames() {
+		if client, ok := clients[name]; ok {
+			res = append(res, client)
+		}
+	}
+	r.Shuffle(len(res), func(i, j int) {
+		res[i], res[j] = res[j], res[i]
+	})
+	return res, nil
+}
+
+// Size returns size of the cluster
+func (c *Cluster) Size() (size int) {
+	for _, ng := range c.nodeGroups {
+		size += ng.Size()
+	}
+	return size
+}
+
+// Topologies returns ClusterTopologies
+func (c *Cluster) Topologies(ctx context.Context) (topologies orchestration.ClusterTopologies, err error) {
+	topologies = make(orchestration.ClusterTopologies)
+	for k, v := range c.nodeGroups {
+		t, err := v.Topologies(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", k, err)
+		}
+		topologies[k] = t
+	}
+	return topologies, nil
+}
+
+// ClosestFullNodeClient returns the client of the running full node closest
+// to the node of the supplied client
+func (c *Cluster) ClosestFullNodeClient(ctx context.Context, s *bee.Client) (*bee.Client, error) {
+	overlay, err := s.Overlay(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	clients, err := c.NodesClients(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		closest        *bee.Client
+		closestOverlay swarm.Address
+	)
+	for _, name := range c.FullNodeNames() {
+		client, ok := clients[name]
+		if !ok || name == s.Name() {
+			continue
+		}
+		o := c.network.overlay(name)
+		if closest == nil {
+			closest, closestOverlay = client, o
+			continue
+		}
+		if closer, _ := o.Closer(overlay, closestOverlay); closer {
+			closest, closestOverlay = client, o
+		}
+	}
+
+	if closest == nil {
+		return nil, fmt.Errorf("cannot find closest fullnode")
+	}
+	return closest, nil
+}
@@ -0,0 +1,145 @@
+package fake_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/ethersphere/bee/v2/pkg/cac"
+	"github.com/ethersphere/beekeeper/pkg/bee/api"
+	"github.com/ethersphere/beekeeper/pkg/orchestration"
+	"github.com/ethersphere/beekeeper/pkg/orchestration/fake"
+)
+
+func newCluster(t *testing.T) *fake.Cluster {
+	t.Helper()
+
+	c := fake.NewCluster("test", orchestration.ClusterOptions{}, nil)
+	t.Cleanup(c.Close)
+
+	ctx := context.Background()
+	if err := c.AddNodes(ctx, "bee", 4, orchestration.Config{FullNode: true}); err != nil {
+		t.Fatalf("add full nodes: %v", err)
+	}
+	if err := c.AddNodes(ctx, "light", 1, orchestration.Config{}); err != nil {
+		t.Fatalf("add light nodes: %v", err)
+	}
+	return c
+}
+
+func TestClusterNodes(t *testing.T) {
+	c := newCluster(t)
+	ctx := context.Background()
+
+	if got, want := c.FullNodeNames(), []string{"bee-0", "bee-1", "bee-2", "bee-3"}; !equal(got, want) {
+		t.Fatalf("full nodes: got %v, want %v", got, want)
+	}
+	if got, want := c.LightNodeNames(), []string{"light-0"}; !equal(got, want) {
+		t.Fatalf("light nodes: got %v, want %v", got, want)
+	}
+
+	overlays, err := c.FlattenOverlays(ctx)
+	if err != nil {
+		t.Fatalf("overlays: %v", err)
+	}
+	again := fake.NewCluster("test", orchestration.ClusterOptions{}, nil)
+	defer again.Close()
+	if err := again.AddNodes(ctx, "bee", 1, orchestration.Config{FullNode: true}); err != nil {
+		t.Fatalf("add nodes: %v", err)
+	}
+	o, err := again.FlattenOverlays(ctx)
+	if err != nil {
+		t.Fatalf("overlays: %v", err)
+	}
+	if !o["bee-0"].Equal(overlays["bee-0"]) {
+		t.Fatalf("overlay is not stable: got %s, want %s", o["bee-0"], overlays["bee-0"])
+	}
+
+	peers, err := c.Peers(ctx)
+	if err != nil {
+		t.Fatalf("peers: %v", err)
+	}
+	if got := len(peers["bee"]["bee-0"]); got != 4 {
+		t.Fatalf("peers of bee-0: got %d, want 4", got)
+	}
+
+	if err := c.Nodes()["bee-1"].Stop(ctx, ""); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+	peers, err = c.Peers(ctx)
+	if err != nil {
+		t.Fatalf("peers: %v", err)
+	}
+	if _, ok := peers["bee"]["bee-1"]; ok {
+		t.Fatal("stopped node is reported")
+	}
+	if got := len(peers["bee"]["bee-0"]); got != 3 {
+		t.Fatalf("peers of bee-0 with stopped node: got %d, want 3", got)
+	}
+}
+
+func TestClusterChunks(t *testing.T) {
+	c := newCluster(t)
+	ctx := context.Background()
+
+	clients, err := c.NodesClients(ctx)
+	if err != nil {
+		t.Fatalf("clients: %v", err)
+	}
+	uploader := clients["light-0"]
+
+	batchID, err := uploader.CreatePostageBatch(ctx, 1000, 17, "test", false)
+	if err != nil {
+		t.Fatalf("create batch: %v", err)
+	}
+
+	ch, err := cac.New([]byte("fake"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr, err := uploader.UploadChunk(ctx, ch.Data(), api.UploadOptions{BatchID: batchID})
+	if err != nil {
+		t.Fatalf("upload: %v", err)
+	}
+	if !addr.Equal(ch.Address()) {
+		t.Fatalf("address: got %s, want %s", addr, ch.Address())
+	}
+
+	if found, err := uploader.HasChunk(ctx, addr); err != nil || found {
+		t.Fatalf("light node stores chunk: found %v, err %v", found, err)
+	}
+
+	grf, err := c.GlobalReplicationFactor(ctx, addr)
+	if err != nil {
+		t.Fatalf("replication factor: %v", err)
+	}
+	if grf != 2 {
+		t.Fatalf("replication factor: got %d, want 2", grf)
+	}
+
+	for name, client := range clients {
+		data, err := client.DownloadChunk(ctx, addr, "", nil)
+		if err != nil {
+			t.Fatalf("download %s: %v", name, err)
+		}
+		if !bytes.Equal(data, ch.Data()) {
+			t.Fatalf("download %s: data mismatch", name)
+		}
+	}
+
+	if _, err := uploader.UploadChunk(ctx, ch.Data(), api.UploadOptions{BatchID: "00"}); err == nil {
+		t.Fatal("upload with unknown batch succeeded")
+	}
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,115 @@
+package fake
+
+import (
+	"crypto/sha256"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethersphere/bee/v2/pkg/swarm"
+)
+
+// replicationFactor is the number of closest full nodes a pushed chunk is
+// stored on
+const replicationFactor = 2
+
+// network connects the Bee API stand-ins of a cluster. Every running node is
+// connected to every other running node, and chunks are pushed to the full
+// nodes closest to their address.
+type network struct {
+	name  string
+	start time.Time // start of the first round of the storage incentives game
+	mu    sync.RWMutex
+	nodes map[string]*beeNode
+}
+
+func newNetwork(name string) *network {
+	return &network{
+		name:  name,
+		start: time.Now(),
+		nodes: make(map[string]*beeNode),
+	}
+}
+
+func (n *network) add(node *beeNode) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.nodes[node.name] = node
+}
+
+func (n *network) remove(name string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	delete(n.nodes, name)
+}
+
+func (n *network) node(name string) (*beeNode, bool) {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	node, ok := n.nodes[name]
+	return node, ok
+}
+
+// running returns running nodes ordered by name
+func (n *network) running() []*beeNode {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	nodes := make([]*beeNode, 0, len(n.nodes))
+	for _, node := range n.nodes {
+		if !node.isStopped() {
+			nodes = append(nodes, node)
+		}
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].name < nodes[j].name })
+
+	return nodes
+}
+
+// peers returns running nodes other than the given one
+func (n *network) peers(self *beeNode) []*beeNode {
+	var peers []*beeNode
+	for _, node := range n.running() {
+		if node != self {
+			peers = append(peers, node)
+		}
+	}
+	return peers
+}
+
+// push stores the chunk on the running full nodes closest to its address
+func (n *network) push(ch swarm.Chunk) {
+	addr := ch.Address()
+	var full []*beeNode
+	for _, node := range n.running() {
+		if node.fullNode {
+			full = append(full, node)
+		}
+	}
+
+	sort.SliceStable(full, func(i, j int) bool {
+		closer, _ := full[i].overlay.Closer(addr, full[j].overlay)
+		return closer
+	})
+
+	for i := 0; i < len(full) && i < replicationFactor; i++ {
+		full[i].server.Put(ch)
+	}
+}
+
+// retrieve returns the chunk from any running node that stores it
+func (n *network) retrieve(addr swarm.Address) ([]byte, bool) {
+	for _, node := range n.running() {
+		if data, ok := node.server.Chunk(addr); ok {
+			return data, true
+		}
+	}
+	return nil, false
+}
+
+// overlay derives a stable overlay address of the node from its name, so
+// that tests are deterministic
+func (n *network) overlay(name string) swarm.Address {
+	h := sha256.Sum256([]byte(n.name + "/" + name))
+	return swarm.NewAddress(h[:])
+}
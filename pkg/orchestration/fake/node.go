@@ -0,0 +1,80 @@
+package fake
+
+import (
+	"context"
+
+	"github.com/ethersphere/beekeeper/pkg/bee"
+	"github.com/ethersphere/beekeeper/pkg/orchestration"
+)
+
+// compile check whether client implements interface
+var _ orchestration.Node = (*Node)(nil)
+
+// Node represents Bee node backed by an in-process Bee API stand-in
+type Node struct {
+	name   string
+	client *bee.Client
+	opts   orchestration.NodeOptions
+	bee    *beeNode
+}
+
+// Name returns node's name
+func (n *Node) Name() string {
+	return n.name
+}
+
+// Client returns node's client
+func (n *Node) Client() *bee.Client {
+	return n.client
+}
+
+// Config returns node's config
+func (n *Node) Config() *orchestration.Config {
+	return n.opts.Config
+}
+
+// LibP2PKey returns node's libP2PKey
+func (n *Node) LibP2PKey() string {
+	return n.opts.LibP2PKey
+}
+
+// SwarmKey returns node's swarmKey
+func (n *Node) SwarmKey() *orchestration.EncryptedKey {
+	return n.opts.SwarmKey
+}
+
+// SetSwarmKey sets node's Swarm key
+func (n *Node) SetSwarmKey(key *orchestration.EncryptedKey) orchestration.Node {
+	n.opts.SwarmKey = key
+	return n
+}
+
+// Create does nothing, as the stand-in is created with the node
+func (n *Node) Create(ctx context.Context, o orchestration.CreateOptions) (err error) {
+	return nil
+}
+
+// Delete stops the node
+func (n *Node) Delete(ctx context.Context, namespace string) (err error) {
+	n.bee.setStopped(true)
+	return nil
+}
+
+// Ready returns whether the node is running
+func (n *Node) Ready(ctx context.Context, namespace string) (ready bool, err error) {
+	return !n.bee.isStopped(), nil
+}
+
+// Start starts the stopped node. Chunks stored before it was stopped are
+// kept.
+func (n *Node) Start(ctx context.Context, namespace string) (err error) {
+	n.bee.setStopped(false)
+	return nil
+}
+
+// Stop stops the node. Its API responds with an error and it is not a peer
+// of other nodes until it is started again.
+func (n *Node) Stop(ctx context.Context, namespace string) (err error) {
+	n.bee.setStopped(true)
+	return nil
+}
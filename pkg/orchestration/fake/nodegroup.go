@@ -0,0 +1,414 @@
+package fake
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/ethersphere/bee/v2/pkg/swarm"
+	"github.com/ethersphere/beekeeper/pkg/bee"
+	"github.com/ethersphere/beekeeper/pkg/bee/api/beetest"
+	"github.com/ethersphere/beekeeper/pkg/logging"
+	"github.com/ethersphere/beekeeper/pkg/orchestration"
+	"github.com/ethersphere/beekeeper/pkg/swap"
+)
+
+// compile check whether client implements interface
+var _ orchestration.NodeGroup = (*NodeGroup)(nil)
+
+// NodeGroup represents group of Bee nodes backed by in-process Bee API
+// stand-ins
+type NodeGroup struct {
+	name       string
+	opts       orchestration.NodeGroupOptions
+	network    *network
+	httpClient *http.Client
+	log        logging.Logger
+
+	lock  sync.RWMutex
+	nodes map[string]*Node
+}
+
+func newNodeGroup(name string, o orchestration.NodeGroupOptions, n *network, httpClient *http.Client, log logging.Logger) *NodeGroup {
+	return &NodeGroup{
+		name:       name,
+		opts:       o,
+		network:    n,
+		httpClient: httpClient,
+		log:        log,
+		nodes:      make(map[string]*Node),
+	}
+}
+
+// AddNode starts a Bee API stand-in for the node and adds it to the node
+// group. The node is a full node if its configuration, or the configuration of
+// the node group, says so.
+func (g *NodeGroup) AddNode(ctx context.Context, name string, inCluster bool, o orchestration.NodeOptions, opts ...orchestration.BeeClientOption) (err error) {
+	if _, ok := g.network.node(name); ok {
+		return fmt.Errorf("node %s already exists", name)
+	}
+
+	config := o.Config
+	if config == nil {
+		config = g.opts.BeeConfig
+	}
+	if config == nil {
+		config = new(orchestration.Config)
+	}
+
+	b := newBeeNode(name, config.FullNode, config.CacheCapacity, g.network)
+
+	beeClientOpts := bee.ClientOptions{
+		Name:          name,
+		NodeGroupName: g.name,
+		APIURL:        b.server.URL(),
+		Retry:         1,
+		SwapClient:    blockTimeFetcher{},
+		HTTPClient:    g.httpClient,
+		Logger:        g.log,
+	}
+	for _, opt := range opts {
+		if err := opt(&beeClientOpts); err != nil {
+			b.close()
+			return fmt.Errorf("bee client option: %w", err)
+		}
+	}
+
+	client, err := bee.NewClient(beeClientOpts)
+	if err != nil {
+		b.close()
+		return fmt.Errorf("bee client: %w", err)
+	}
+
+	g.network.add(b)
+
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	g.nodes[name] = &Node{
+		name:   name,
+		client: client,
+		opts: orchestration.NodeOptions{
+			Config:    config,
+			LibP2PKey: o.LibP2PKey,
+			SwarmKey:  o.SwarmKey,
+		},
+		bee: b,
+	}
+
+	return nil
+}
+
+// DeployNode adds the node and returns its Ethereum address
+func (g *NodeGroup) DeployNode(ctx context.Context, name string, inCluster bool, o orchestration.NodeOptions) (ethAddress string, err error) {
+	if err := g.AddNode(ctx, name, inCluster, o); err != nil {
+		return "", err
+	}
+
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	return g.nodes[name].bee.server.EthereumAddress(), nil
+}
+
+// SetNetworkProfile validates and stores the network profile of the node
+// group, traffic of the stand-ins is not shaped
+func (g *NodeGroup) SetNetworkProfile(ctx context.Context, p orchestration.NetworkProfile) (err error) {
+	if _, err := p.NetemArgs(); err != nil {
+		return err
+	}
+
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	g.opts.NetworkProfile = &p
+
+	return nil
+}
+
+// UpdateNode replaces options of the node, keeping its stand-in running
+func (g *NodeGroup) UpdateNode(ctx context.Context, name string, inCluster bool, o orchestration.NodeOptions) (err error) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	n, ok := g.nodes[name]
+	if !ok {
+		return fmt.Errorf("node %s not found", name)
+	}
+
+	config := o.Config
+	if config == nil {
+		config = g.opts.BeeConfig
+	}
+	if config == nil {
+		config = n.opts.Config
+	}
+
+	n.opts = orchestration.NodeOptions{
+		Config:    config,
+		LibP2PKey: o.LibP2PKey,
+		SwarmKey:  o.SwarmKey,
+	}
+
+	return nil
+}
+
+// DeleteNode removes the node from the node group and shuts its stand-in down
+func (g *NodeGroup) DeleteNode(ctx context.Context, name string) (err error) {
+	g.lock.Lock()
+	n, ok := g.nodes[name]
+	delete(g.nodes, name)
+	g.lock.Unlock()
+
+	if !ok {
+		return fmt.Errorf("node %s not found", name)
+	}
+
+	g.network.remove(name)
+	n.bee.close()
+
+	return nil
+}
+
+// close shuts down stand-ins of all nodes
+func (g *NodeGroup) close() {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	for name, n := range g.nodes {
+		g.network.remove(name)
+		n.bee.close()
+	}
+	g.nodes = make(map[string]*Node)
+}
+
+// Accounting returns NodeGroupAccounting
+func (g *NodeGroup) Accounting(ctx context.Context) (accounting orchestration.NodeGroupAccounting, err error) {
+	accounting = make(orchestration.NodeGroupAccounting)
+	err = g.forEachRunning(func(name string, c *bee.Client) error {
+		a, err := c.Accounting(ctx)
+		if err != nil {
+			return err
+		}
+		accounting[name] = make(map[string]bee.Account)
+		for _, acc := range a.Accounting {
+			accounting[name][acc.Peer] = acc
+		}
+		return nil
+	})
+	return accounting, err
+}
+
+// Addresses returns NodeGroupAddresses
+func (g *NodeGroup) Addresses(ctx context.Context) (addrs orchestration.NodeGroupAddresses, err error) {
+	addrs = make(orchestration.NodeGroupAddresses)
+	err = g.forEachRunning(func(name string, c *bee.Client) error {
+		a, err := c.Addresses(ctx)
+		if err != nil {
+			return err
+		}
+		addrs[name] = a
+		return nil
+	})
+	return addrs, err
+}
+
+// Balances returns NodeGroupBalances
+func (g *NodeGroup) Balances(ctx context.Context) (balances orchestration.NodeGroupBalances, err error) {
+	balances = make(orchestration.NodeGroupBalances)
+	err = g.forEachRunning(func(name string, c *bee.Client) error {
+		b, err := c.Balances(ctx)
+		if err != nil {
+			return err
+		}
+		balances[name] = make(map[string]int64)
+		for _, bal := range b.Balances {
+			balances[name][bal.Peer] = bal.Balance
+		}
+		return nil
+	})
+	return balances, err
+}
+
+// GroupReplicationFactor returns the total number of nodes in the node group that contain given chunk
+func (g *NodeGroup) GroupReplicationFactor(ctx context.Context, a swarm.Address) (grf int, err error) {
+	err = g.forEachRunning(func(name string, c *bee.Client) error {
+		found, err := c.HasChunk(ctx, a)
+		if err != nil {
+			return err
+		}
+		if found {
+			grf++
+		}
+		return nil
+	})
+	return grf, err
+}
+
+// NodeClient returns node's client
+func (g *NodeGroup) NodeClient(name string) (*bee.Client, error) {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+
+	n, ok := g.nodes[name]
+	if !ok {
+		return nil, fmt.Errorf("node %s not found", name)
+	}
+	return n.client, nil
+}
+
+// Nodes returns map of nodes in the node group
+func (g *NodeGroup) Nodes() map[string]orchestration.Node {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+
+	nodes := make(map[string]orchestration.Node, len(g.nodes))
+	for name, n := range g.nodes {
+		nodes[name] = n
+	}
+	return nodes
+}
+
+// NodesClients returns map of node's clients in the node group excluding stopped nodes
+func (g *NodeGroup) NodesClients(ctx context.Context) (map[string]*bee.Client, error) {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+
+	clients := make(map[string]*bee.Client, len(g.nodes))
+	for name, n := range g.nodes {
+		if !n.bee.isStopped() {
+			clients[name] = n.client
+		}
+	}
+	return clients, nil
+}
+
+// NodesSorted returns list of nodes sorted by names from the node group.
+func (g *NodeGroup) NodesSorted() (l []string) {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+
+	for name := range g.nodes {
+		l = append(l, name)
+	}
+	sort.Strings(l)
+	return l
+}
+
+// Overlays returns NodeGroupOverlays
+func (g *NodeGroup) Overlays(ctx context.Context) (overlays orchestration.NodeGroupOverlays, err error) {
+	overlays = make(orchestration.NodeGroupOverlays)
+	err = g.forEachRunning(func(name string, c *bee.Client) error {
+		o, err := c.Overlay(ctx)
+		if err != nil {
+			return err
+		}
+		overlays[name] = o
+		return nil
+	})
+	return overlays, err
+}
+
+// Peers returns NodeGroupPeers
+func (g *NodeGroup) Peers(ctx context.Context) (peers orchestration.NodeGroupPeers, err error) {
+	peers = make(orchestration.NodeGroupPeers)
+	err = g.forEachRunning(func(name string, c *bee.Client) error {
+		p, err := c.Peers(ctx)
+		if err != nil {
+			return err
+		}
+		peers[name] = p
+		return nil
+	})
+	return peers, err
+}
+
+// RunningNodes returns list of running nodes
+func (g *NodeGroup) RunningNodes(ctx context.Context) (running []string, err error) {
+	for _, name := range g.NodesSorted() {
+		if n, ok := g.network.node(name); ok && !n.isStopped() {
+			running = append(running, name)
+		}
+	}
+	return running, nil
+}
+
+// Settlements returns NodeGroupSettlements
+func (g *NodeGroup) Settlements(ctx context.Context) (settlements orchestration.NodeGroupSettlements, err error) {
+	settlements = make(orchestration.NodeGroupSettlements)
+	err = g.forEachRunning(func(name string, c *bee.Client) error {
+		s, err := c.Settlements(ctx)
+		if err != nil {
+			return err
+		}
+		settlements[name] = make(map[string]orchestration.SentReceived)
+		for _, set := range s.Settlements {
+			settlements[name][set.Peer] = orchestration.SentReceived{
+				Received: set.Received,
+				Sent:     set.Sent,
+			}
+		}
+		return nil
+	})
+	return settlements, err
+}
+
+// Size returns size of the node group
+func (g *NodeGroup) Size() int {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	return len(g.nodes)
+}
+
+// StoppedNodes returns list of stopped nodes
+func (g *NodeGroup) StoppedNodes(ctx context.Context) (stopped []string, err error) {
+	for _, name := range g.NodesSorted() {
+		if n, ok := g.network.node(name); ok && n.isStopped() {
+			stopped = append(stopped, name)
+		}
+	}
+	return stopped, nil
+}
+
+// Topologies returns NodeGroupTopologies
+func (g *NodeGroup) Topologies(ctx context.Context) (topologies orchestration.NodeGroupTopologies, err error) {
+	topologies = make(orchestration.NodeGroupTopologies)
+	err = g.forEachRunning(func(name string, c *bee.Client) error {
+		t, err := c.Topology(ctx)
+		if err != nil {
+			return err
+		}
+		topologies[name] = t
+		return nil
+	})
+	return topologies, err
+}
+
+// forEachRunning calls f for running nodes in the order of their names. The
+// stand-ins respond immediately, so nodes are not queried concurrently.
+func (g *NodeGroup) forEachRunning(f func(name string, c *bee.Client) error) error {
+	clients, err := g.NodesClients(context.Background())
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(clients))
+	for name := range clients {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := f(name, clients[name]); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// blockTimeFetcher reports the block time of the simulated chain
+type blockTimeFetcher struct{}
+
+func (blockTimeFetcher) FetchBlockTime(ctx context.Context, opts ...swap.Option) (int64, error) {
+	return beetest.DefaultBlockTime, nil
+}
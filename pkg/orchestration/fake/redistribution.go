@@ -0,0 +1,116 @@
+package fake
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/ethersphere/beekeeper/pkg/bee/api"
+	"github.com/ethersphere/beekeeper/pkg/bigint"
+)
+
+const (
+	// roundDuration is the duration of a round of the simulated storage
+	// incentives game, short enough for checks to observe rounds in tests
+	roundDuration = 20 * time.Millisecond
+	// blocksPerRound is the number of blocks in a round
+	blocksPerRound = 152
+)
+
+var (
+	// minimumStake is the stake that full nodes need to play the game
+	minimumStake = big.NewInt(1e17)
+	// roundReward is the reward of the winner of a round
+	roundReward = big.NewInt(1e15)
+)
+
+// round returns the current round of the game
+func (n *network) round() uint64 {
+	return uint64(time.Since(n.start) / roundDuration)
+}
+
+// winner returns the name of the winner of the round, or an empty string if
+// no node played in it. Players win in turns in the order of node names.
+func (n *network) winner(round uint64) string {
+	var players []*beeNode
+	for _, node := range n.running() {
+		if node.plays(round) {
+			players = append(players, node)
+		}
+	}
+	if len(players) == 0 {
+		return ""
+	}
+	return players[round%uint64(len(players))].name
+}
+
+// plays returns whether the node plays in the round. All staked full nodes
+// form a single neighborhood that is selected in every round after the one
+// in which they staked.
+func (b *beeNode) plays(round uint64) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.fullNode && b.staked.Cmp(minimumStake) >= 0 && b.stakeRound < round
+}
+
+// Staked records the round in which the stake of the node reached the
+// minimum stake
+func (b *beeNode) Staked(stake *big.Int) {
+	round := b.network.round()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	wasPlaying := b.staked.Cmp(minimumStake) >= 0
+	b.staked.Set(stake)
+	if !wasPlaying && b.staked.Cmp(minimumStake) >= 0 {
+		b.stakeRound = round
+	}
+}
+
+// State reports rounds in which the node played and won since it staked, and
+// the sum of its rewards
+func (b *beeNode) State() api.RedistributionState {
+	elapsed := time.Since(b.network.start)
+	round := uint64(elapsed / roundDuration)
+
+	phase := "claim"
+	switch inRound := elapsed % roundDuration; {
+	case inRound < roundDuration/4:
+		phase = "commit"
+	case inRound < roundDuration/2:
+		phase = "reveal"
+	}
+
+	state := api.RedistributionState{
+		MinimumGasFunds:    bigint.Wrap(big.NewInt(1e15)),
+		HasSufficientFunds: true,
+		IsFullySynced:      true,
+		Phase:              phase,
+		Round:              round,
+		Block:              round*blocksPerRound + uint64(elapsed%roundDuration*blocksPerRound/roundDuration),
+		Reward:             bigint.Wrap(new(big.Int)),
+		Fees:               bigint.Wrap(new(big.Int)),
+		IsHealthy:          true,
+	}
+
+	// only finished rounds are played
+	for played := uint64(1); played < round; played++ {
+		if !b.plays(played) {
+			continue
+		}
+		state.LastSelectedRound = played
+		state.LastPlayedRound = played
+		if b.network.winner(played) == b.name {
+			state.LastWonRound = played
+			state.Reward.Add(state.Reward.Int, roundReward)
+		}
+	}
+
+	// like bee, the neighborhood selected for the next round is reported
+	// while sampling in the claim phase
+	if phase == "claim" && b.plays(round+1) {
+		state.LastSelectedRound = round + 1
+	}
+
+	return state
+}
@@ -31,11 +31,30 @@ type Cluster struct {
 	httpClient       *http.Client
 	k8sClient        *k8s.Client
 	swapClient       swap.Client
+	apiMetrics       *httpx.Metrics
 	log              logging.Logger
 }
 
+// ClusterOption sets optional parameters of the cluster
+type ClusterOption func(*Cluster)
+
+// WithNodeOrchestrator sets the orchestrator that creates, starts and stops
+// nodes of the cluster instead of Kubernetes
+func WithNodeOrchestrator(no orchestration.NodeOrchestrator) ClusterOption {
+	return func(c *Cluster) {
+		c.nodeOrchestrator = no
+	}
+}
+
+// WithAPIMetrics records metrics of API requests to nodes of the cluster
+func WithAPIMetrics(m *httpx.Metrics) ClusterOption {
+	return func(c *Cluster) {
+		c.apiMetrics = m
+	}
+}
+
 // NewCluster returns new cluster
-func NewCluster(name string, o orchestration.ClusterOptions, k8s *k8s.Client, swapClient swap.Client, log logging.Logger) *Cluster {
+func NewCluster(name string, o orchestration.ClusterOptions, k8s *k8s.Client, swapClient swap.Client, log logging.Logger, opts ...ClusterOption) *Cluster {
 	var nodeOrchestrator orchestration.NodeOrchestrator
 
 	if k8s == nil {
@@ -48,7 +67,7 @@ func NewCluster(name string, o orchestration.ClusterOptions, k8s *k8s.Client, sw
 		swapClient = &swap.NotSet{}
 	}
 
-	return &Cluster{
+	c := &Cluster{
 		name:             name,
 		nodeOrchestrator: nodeOrchestrator,
 		opts:             o,
@@ -66,11 +85,50 @@ func NewCluster(name string, o orchestration.ClusterOptions, k8s *k8s.Client, sw
 		k8sClient:  k8s,
 		swapClient: swapClient,
 	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
 }
 
 // AddNodeGroup adds new node group to the cluster
 func (c *Cluster) AddNodeGroup(name string, o orchestration.NodeGroupOptions) {
-	c.nodeGroups[name] = NewNodeGroup(name, c.opts, c.nodeOrchestrator, o, c.httpClient, c.swapClient, c.k8sClient, c.log)
+	ng := NewNodeGroup(name, c.opts, c.nodeOrchestrator, o, c.httpClient, c.swapClient, c.k8sClient, c.log)
+	ng.apiMetrics = c.apiMetrics
+	c.nodeGroups[name] = ng
+}
+
+// DeleteNode deletes the node from the k8s cluster, including nodes that do
+// not belong to any of the cluster's node groups
+func (c *Cluster) DeleteNode(ctx context.Context, name string) (err error) {
+	for _, ng := range c.nodeGroups {
+		if _, ok := ng.Nodes()[name]; ok {
+			return ng.DeleteNode(ctx, name)
+		}
+	}
+
+	return c.nodeOrchestrator.Delete(ctx, name, c.opts.Namespace)
+}
+
+// DeployedNodes returns sorted names of running and stopped nodes in the
+// cluster namespace, including nodes that are not added to the cluster
+func (c *Cluster) DeployedNodes(ctx context.Context) (names []string, err error) {
+	running, err := c.nodeOrchestrator.RunningNodes(ctx, c.opts.Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("running nodes in namespace %s: %w", c.opts.Namespace, err)
+	}
+
+	stopped, err := c.nodeOrchestrator.StoppedNodes(ctx, c.opts.Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("stopped nodes in namespace %s: %w", c.opts.Namespace, err)
+	}
+
+	names = append(running, stopped...)
+	slices.Sort(names)
+
+	return slices.Compact(names), nil
 }
 
 // Addresses returns ClusterAddresses
@@ -0,0 +1,42 @@
+package k8s_test
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/ethersphere/beekeeper/pkg/logging"
+	"github.com/ethersphere/beekeeper/pkg/orchestration"
+	orchestrationK8S "github.com/ethersphere/beekeeper/pkg/orchestration/k8s"
+)
+
+func TestClusterDeployedNodes(t *testing.T) {
+	statefulSet := func(name string, replicas int32) *appsv1.StatefulSet {
+		return &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace},
+			Status:     appsv1.StatefulSetStatus{Replicas: replicas},
+		}
+	}
+
+	k8sClient := newK8sClient(
+		statefulSet("bee-1", 1),
+		statefulSet("bee-0", 1),
+		statefulSet("bee-2", 0),
+		statefulSet("bootnode-0", 1),
+	)
+
+	c := orchestrationK8S.NewCluster("test", orchestration.ClusterOptions{Namespace: testNamespace}, k8sClient, nil, logging.New(io.Discard, 0))
+
+	got, err := c.DeployedNodes(context.Background())
+	if err != nil {
+		t.Fatalf("deployed nodes: %v", err)
+	}
+
+	if want := []string{"bee-0", "bee-1", "bee-2", "bootnode-0"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got deployed nodes %v, want %v", got, want)
+	}
+}
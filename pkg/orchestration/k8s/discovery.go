@@ -0,0 +1,149 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/ethersphere/beekeeper/pkg/k8s"
+	"github.com/ethersphere/beekeeper/pkg/logging"
+	"github.com/ethersphere/beekeeper/pkg/orchestration"
+	"github.com/ethersphere/beekeeper/pkg/swap"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	// DefaultGroupLabel is the pod label by which discovered nodes are grouped
+	DefaultGroupLabel = "app.kubernetes.io/component"
+	// DefaultNodeGroup is the node group of discovered nodes without the group label
+	DefaultNodeGroup = "bee"
+
+	beeModeFull = "full"
+)
+
+// ErrNoNodesDiscovered is returned when no running Bee nodes match the discovery options
+var ErrNoNodesDiscovered = errors.New("no nodes discovered")
+
+// DiscoveryOptions represents options for discovering a cluster of running Bee nodes
+type DiscoveryOptions struct {
+	Namespace     string
+	LabelSelector string
+	GroupLabel    string // pod label by which nodes are grouped, DefaultGroupLabel if empty
+	InCluster     bool   // use service endpoints instead of ingress hosts
+}
+
+// DiscoverCluster returns a cluster of the running Bee pods in the namespace
+// that match the label selector, regardless of how they were deployed. Pods
+// are grouped into node groups by the value of the group label and each
+// node is named by the service that exposes its API. Nodes are marked as
+// full or light by the mode reported by their status.
+func DiscoverCluster(ctx context.Context, name string, o DiscoveryOptions, k8sClient *k8s.Client, swapClient swap.Client, log logging.Logger, opts ...ClusterOption) (*Cluster, error) {
+	if o.Namespace == "" {
+		return nil, errors.New("namespace not provided")
+	}
+	if k8sClient == nil {
+		return nil, errors.New("k8s client not provided")
+	}
+	if o.GroupLabel == "" {
+		o.GroupLabel = DefaultGroupLabel
+	}
+	if name == "" {
+		name = o.Namespace
+	}
+
+	pods, err := k8sClient.Pods.List(ctx, o.Namespace, o.LabelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("list pods: %w", err)
+	}
+
+	hosts := make(map[string]string)
+	if !o.InCluster {
+		if hosts, err = ingressHosts(ctx, k8sClient, o.Namespace, o.LabelSelector); err != nil {
+			return nil, err
+		}
+	}
+
+	// sort pods to add nodes in a stable order
+	sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+
+	c := NewCluster(name, orchestration.ClusterOptions{Namespace: o.Namespace}, k8sClient, swapClient, log, opts...)
+
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Status.Phase != v1.PodRunning {
+			log.Debugf("skipping pod %s in phase %s", pod.Name, pod.Status.Phase)
+			continue
+		}
+
+		nodeInfo, _, err := k8sClient.Service.FindNode(ctx, o.Namespace, pod)
+		if err != nil {
+			return nil, fmt.Errorf("find api service of pod %s: %w", pod.Name, err)
+		}
+
+		endpoint := nodeInfo.Endpoint
+		if !o.InCluster {
+			host, ok := hosts[nodeInfo.Name]
+			if !ok {
+				return nil, fmt.Errorf("ingress of service %s not found", nodeInfo.Name)
+			}
+			endpoint = "http://" + host
+		}
+
+		groupName := pod.Labels[o.GroupLabel]
+		if groupName == "" {
+			groupName = DefaultNodeGroup
+		}
+
+		if _, ok := c.nodeGroups[groupName]; !ok {
+			c.AddNodeGroup(groupName, orchestration.NodeGroupOptions{})
+		}
+		ng := c.nodeGroups[groupName]
+
+		// the configuration is completed from the status of the node, after its client is created
+		config := &orchestration.Config{}
+		if err := ng.AddNode(ctx, nodeInfo.Name, o.InCluster, orchestration.NodeOptions{Config: config}, orchestration.WithURL(endpoint)); err != nil {
+			return nil, fmt.Errorf("add node %s: %w", nodeInfo.Name, err)
+		}
+
+		status, err := ng.Nodes()[nodeInfo.Name].Client().Status(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("node %s status: %w", nodeInfo.Name, err)
+		}
+		config.FullNode = status.BeeMode == beeModeFull
+
+		log.Debugf("discovered %s node %s in node group %s with endpoint %s", status.BeeMode, nodeInfo.Name, groupName, endpoint)
+	}
+
+	if c.Size() == 0 {
+		return nil, fmt.Errorf("namespace %s, label selector %q: %w", o.Namespace, o.LabelSelector, ErrNoNodesDiscovered)
+	}
+
+	log.Infof("discovered %d nodes in %d node groups in namespace %s", c.Size(), len(c.nodeGroups), o.Namespace)
+
+	return c, nil
+}
+
+// ingressHosts returns hosts of ingresses and ingress routes that match the
+// label selector, by their names
+func ingressHosts(ctx context.Context, k8sClient *k8s.Client, namespace, labelSelector string) (map[string]string, error) {
+	hosts := make(map[string]string)
+
+	ingressNodes, err := k8sClient.Ingress.GetNodes(ctx, namespace, labelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("list ingress api nodes hosts: %w", err)
+	}
+	for _, n := range ingressNodes {
+		hosts[n.Name] = n.Host
+	}
+
+	ingressRouteNodes, err := k8sClient.IngressRoute.GetNodes(ctx, namespace, labelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("list ingress route api nodes hosts: %w", err)
+	}
+	for _, n := range ingressRouteNodes {
+		hosts[n.Name] = n.Host
+	}
+
+	return hosts, nil
+}
@@ -0,0 +1,151 @@
+package k8s_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"slices"
+	"strconv"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/ethersphere/beekeeper/pkg/bee/api/beetest"
+	"github.com/ethersphere/beekeeper/pkg/k8s"
+	"github.com/ethersphere/beekeeper/pkg/k8s/configmap"
+	"github.com/ethersphere/beekeeper/pkg/k8s/customresource/ingressroute"
+	"github.com/ethersphere/beekeeper/pkg/k8s/customresource/ingressroute/mock"
+	"github.com/ethersphere/beekeeper/pkg/k8s/ingress"
+	"github.com/ethersphere/beekeeper/pkg/k8s/pod"
+	"github.com/ethersphere/beekeeper/pkg/k8s/secret"
+	"github.com/ethersphere/beekeeper/pkg/k8s/service"
+	"github.com/ethersphere/beekeeper/pkg/k8s/statefulset"
+	"github.com/ethersphere/beekeeper/pkg/logging"
+	orchestrationK8S "github.com/ethersphere/beekeeper/pkg/orchestration/k8s"
+)
+
+const (
+	testNamespace     = "test"
+	testLabelSelector = "app.kubernetes.io/name=bee"
+)
+
+func TestDiscoverCluster(t *testing.T) {
+	var objects []runtime.Object
+	addNode := func(name, component string, phase v1.PodPhase, opts ...beetest.Option) {
+		s := beetest.NewServer(opts...)
+		t.Cleanup(s.Close)
+
+		port, err := strconv.Atoi(s.URL().Port())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		labels := map[string]string{
+			"app.kubernetes.io/name":             "bee",
+			"statefulset.kubernetes.io/pod-name": name,
+		}
+		if component != "" {
+			labels[orchestrationK8S.DefaultGroupLabel] = component
+		}
+
+		objects = append(objects,
+			&v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace, Labels: labels},
+				Status:     v1.PodStatus{Phase: phase},
+			},
+			&v1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace},
+				Spec: v1.ServiceSpec{
+					ClusterIP: s.URL().Hostname(),
+					Selector:  map[string]string{"statefulset.kubernetes.io/pod-name": name},
+					Ports:     []v1.ServicePort{{Name: "api", Port: int32(port)}},
+				},
+			},
+		)
+	}
+
+	addNode("bee-0", "bee", v1.PodRunning)
+	addNode("bee-1", "", v1.PodRunning)
+	addNode("light-0", "light", v1.PodRunning, beetest.WithLightNode())
+	addNode("bee-2", "bee", v1.PodPending)
+
+	k8sClient := newK8sClient(objects...)
+
+	c, err := orchestrationK8S.DiscoverCluster(context.Background(), "", orchestrationK8S.DiscoveryOptions{
+		Namespace:     testNamespace,
+		LabelSelector: testLabelSelector,
+		InCluster:     true,
+	}, k8sClient, nil, logging.New(io.Discard, 0))
+	if err != nil {
+		t.Fatalf("discover cluster: %v", err)
+	}
+
+	if c.Name() != testNamespace {
+		t.Errorf("got name %s, want %s", c.Name(), testNamespace)
+	}
+	if c.Namespace() != testNamespace {
+		t.Errorf("got namespace %s, want %s", c.Namespace(), testNamespace)
+	}
+
+	groups := make(map[string][]string)
+	for name, ng := range c.NodeGroups() {
+		groups[name] = ng.NodesSorted()
+	}
+	wantGroups := map[string][]string{
+		"bee":   {"bee-0", "bee-1"},
+		"light": {"light-0"},
+	}
+	if !reflect.DeepEqual(groups, wantGroups) {
+		t.Errorf("got node groups %v, want %v", groups, wantGroups)
+	}
+
+	if got, want := sorted(c.FullNodeNames()), []string{"bee-0", "bee-1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got full nodes %v, want %v", got, want)
+	}
+	if got, want := c.LightNodeNames(), []string{"light-0"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got light nodes %v, want %v", got, want)
+	}
+
+	overlays, err := c.FlattenOverlays(context.Background())
+	if err != nil {
+		t.Fatalf("overlays: %v", err)
+	}
+	if len(overlays) != 3 {
+		t.Errorf("got %d overlays, want 3", len(overlays))
+	}
+}
+
+func TestDiscoverClusterNoNodes(t *testing.T) {
+	k8sClient := newK8sClient()
+
+	_, err := orchestrationK8S.DiscoverCluster(context.Background(), "test", orchestrationK8S.DiscoveryOptions{
+		Namespace:     testNamespace,
+		LabelSelector: testLabelSelector,
+		InCluster:     true,
+	}, k8sClient, nil, logging.New(io.Discard, 0))
+	if !errors.Is(err, orchestrationK8S.ErrNoNodesDiscovered) {
+		t.Fatalf("got error %v, want %v", err, orchestrationK8S.ErrNoNodesDiscovered)
+	}
+}
+
+func newK8sClient(objects ...runtime.Object) *k8s.Client {
+	clientset := fake.NewSimpleClientset(objects...)
+	return &k8s.Client{
+		ConfigMap:    configmap.NewClient(clientset),
+		Ingress:      ingress.NewClient(clientset, logging.New(io.Discard, 0)),
+		IngressRoute: ingressroute.NewClient(mock.New(), logging.New(io.Discard, 0)),
+		Pods:         pod.NewClient(clientset, logging.New(io.Discard, 0)),
+		Secret:       secret.NewClient(clientset),
+		Service:      service.NewClient(clientset),
+		StatefulSet:  statefulset.NewClient(clientset, logging.New(io.Discard, 0)),
+	}
+}
+
+func sorted(s []string) []string {
+	slices.Sort(s)
+	return s
+}
@@ -14,55 +14,29 @@ import (
 )
 
 const (
-	configTemplate = `
-allow-private-cidrs: {{ .AllowPrivateCIDRs }}
-api-addr: {{.APIAddr}}
-autotls-ca-endpoint: {{.AutoTLSCAEndpoint}}
-autotls-domain: {{.AutoTLSDomain}}
-autotls-registration-endpoint: {{.AutoTLSRegistrationEndpoint}}
-block-time: {{ .BlockTime }}
-blockchain-rpc-endpoint: {{.BlockchainRPCEndpoint}}
-bootnode-mode: {{.BootnodeMode}}
-bootnode: {{.Bootnodes}}
-cache-capacity: {{.CacheCapacity}}
-chequebook-enable: {{.ChequebookEnable}}
-cors-allowed-origins: {{.CORSAllowedOrigins}}
-data-dir: {{.DataDir}}
-db-block-cache-capacity: {{.DbBlockCacheCapacity}}
-db-disable-seeks-compaction: {{.DbDisableSeeksCompaction}}
-db-open-files-limit: {{.DbOpenFilesLimit}}
-db-write-buffer-size: {{.DbWriteBufferSize}}
-full-node: {{.FullNode}}
-mainnet: {{.Mainnet}}
-nat-addr: {{.NATAddr}}
-nat-wss-addr: {{.NATWSSAddr}}
-network-id: {{.NetworkID}}
-p2p-addr: {{.P2PAddr}}
-p2p-ws-enable: {{.P2PWSEnable}}
-p2p-wss-addr: {{.P2PWSSAddr}}
-p2p-wss-enable: {{.P2PWSSEnable}}
-password: {{.Password}}
-payment-early-percent: {{.PaymentEarly}}
-payment-threshold: {{.PaymentThreshold}}
-payment-tolerance-percent: {{.PaymentTolerance}}
-postage-stamp-address: {{ .PostageStampAddress }}
-postage-stamp-start-block: {{ .PostageContractStartBlock }}
-price-oracle-address: {{ .PriceOracleAddress }}
-redistribution-address: {{ .RedistributionAddress }}
-resolver-options: {{.ResolverOptions}}
-staking-address: {{ .StakingAddress }}
-storage-incentives-enable: {{ .StorageIncentivesEnable }}
-swap-enable: {{.SwapEnable}}
-swap-factory-address: {{.SwapFactoryAddress}}
-swap-initial-deposit: {{.SwapInitialDeposit}}
-tracing-enable: {{.TracingEnabled}}
-tracing-endpoint: {{.TracingEndpoint}}
-tracing-service-name: {{.TracingServiceName}}
-verbosity: {{.Verbosity}}
-warmup-time: {{.WarmupTime}}
-welcome-message: {{.WelcomeMessage}}
-withdrawal-addresses-whitelist: {{.WithdrawAddress}}
-`
+	// netemImage is the image of the sidecar container that shapes network
+	// traffic of the pod, it must provide sh and tc
+	netemImage = "nicolaka/netshoot:v0.13"
+	// netemConfigKey is the key of the node's configmap with tc netem
+	// arguments of its network profile
+	netemConfigKey = "netem"
+	// netemScript applies netem arguments from the mounted configmap whenever
+	// they change, so that the profile can be changed at runtime
+	netemScript = `applied=''
+while true; do
+  args=$(cat /etc/netem/netem 2>/dev/null)
+  if [ "$args" != "$applied" ]; then
+    if [ -z "$args" ]; then
+      tc qdisc del dev eth0 root 2>/dev/null
+      applied="$args"
+      echo 'network profile removed'
+    elif tc qdisc replace dev eth0 root netem $args; then
+      applied="$args"
+      echo "network profile set: $args"
+    fi
+  fi
+  sleep 5
+done`
 )
 
 type setInitContainersOptions struct {
@@ -127,6 +101,7 @@ type setContainersOptions struct {
 	LibP2PEnabled          bool
 	SwarmEnabled           bool
 	AutoTLSEnabled         bool
+	NetworkProfileEnabled  bool
 }
 
 func setContainers(o setContainersOptions) (c containers.Containers) {
@@ -207,6 +182,28 @@ func setContainers(o setContainersOptions) (c containers.Containers) {
 		}),
 	})
 
+	// network profile is applied by a sidecar, as the bee container runs
+	// without the capability to change the pod's network
+	if o.NetworkProfileEnabled {
+		c = append(c, containers.Container{
+			Name:    "netem",
+			Image:   netemImage,
+			Command: []string{"sh", "-c", netemScript},
+			SecurityContext: containers.SecurityContext{
+				Capabilities: containers.Capabilities{
+					Add: []string{"NET_ADMIN"},
+				},
+			},
+			VolumeMounts: containers.VolumeMounts{
+				{
+					Name:      "config",
+					MountPath: "/etc/netem",
+					ReadOnly:  true,
+				},
+			},
+		})
+	}
+
 	return c
 }
 
@@ -360,6 +357,14 @@ func parsePort(port string) (int32, error) {
 	return int32(p), err
 }
 
+// nodeLabels returns labels of Kubernetes resources of the node, which are
+// also the selector of its pods
+func nodeLabels(groupLabels map[string]string, name string) map[string]string {
+	return mergeMaps(groupLabels, map[string]string{
+		"app.kubernetes.io/instance": name,
+	})
+}
+
 func mergeMaps(a, b map[string]string) map[string]string {
 	m := map[string]string{}
 	maps.Copy(m, a)
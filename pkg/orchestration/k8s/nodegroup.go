@@ -15,6 +15,8 @@ import (
 
 	"github.com/ethersphere/bee/v2/pkg/swarm"
 	"github.com/ethersphere/beekeeper/pkg/bee"
+	"github.com/ethersphere/beekeeper/pkg/bee/api"
+	"github.com/ethersphere/beekeeper/pkg/httpx"
 	"github.com/ethersphere/beekeeper/pkg/k8s"
 	"github.com/ethersphere/beekeeper/pkg/swap"
 
@@ -37,6 +39,7 @@ type NodeGroup struct {
 	httpClient       *http.Client
 	swapClient       swap.Client
 	k8sClient        *k8s.Client
+	apiMetrics       *httpx.Metrics // set by the cluster
 	log              logging.Logger
 	lock             sync.RWMutex
 }
@@ -72,7 +75,11 @@ func NewNodeGroup(
 func (g *NodeGroup) AddNode(ctx context.Context, name string, inCluster bool, nodeOptions orchestration.NodeOptions, opts ...orchestration.BeeClientOption) (err error) {
 	var apiURL *url.URL
 
-	apiURL, err = g.clusterOpts.ApiURL(name, inCluster)
+	if p, ok := g.nodeOrchestrator.(orchestration.APIURLProvider); ok {
+		apiURL, err = p.APIURL(name, g.clusterOpts.Namespace)
+	} else {
+		apiURL, err = g.clusterOpts.ApiURL(name, inCluster)
+	}
 	if err != nil {
 		return fmt.Errorf("API URL %s: %w", name, err)
 	}
@@ -89,7 +96,8 @@ func (g *NodeGroup) AddNode(ctx context.Context, name string, inCluster bool, no
 		Name:          name,
 		NodeGroupName: g.name,
 		APIURL:        apiURL,
-		Retry:         5,
+		RetryPolicy:   &api.DefaultRetryPolicy,
+		Metrics:       g.apiMetrics,
 		SwapClient:    g.swapClient,
 		HTTPClient:    g.httpClient,
 		Logger:        g.log,
@@ -334,9 +342,7 @@ func (g *NodeGroup) BalancesStream(ctx context.Context) (<-chan BalancesStreamMs
 
 // CreateNode creates new node in the k8s cluster
 func (g *NodeGroup) createNode(ctx context.Context, name string) (err error) {
-	labels := mergeMaps(g.opts.Labels, map[string]string{
-		"app.kubernetes.io/instance": name,
-	})
+	labels := nodeLabels(g.opts.Labels, name)
 
 	n, err := g.getNode(name)
 	if err != nil {
@@ -358,6 +364,7 @@ func (g *NodeGroup) createNode(ctx context.Context, name string) (err error) {
 		IngressHost:               g.clusterOpts.IngressHost(name),
 		Labels:                    labels,
 		LibP2PKey:                 n.LibP2PKey(),
+		NetworkProfile:            g.opts.NetworkProfile,
 		NodeSelector:              g.opts.NodeSelector,
 		P2PWSSNodePort:            g.opts.P2PWSSNodePort,
 		PersistenceEnabled:        g.opts.PersistenceEnabled,
@@ -747,6 +754,47 @@ func (g *NodeGroup) DeployNode(ctx context.Context, name string, inCluster bool,
 	return ethAddress, nil
 }
 
+// UpdateNode recreates the node's resources in the k8s cluster with the
+// current node group options and restarts it, keeping its persistent volume
+func (g *NodeGroup) UpdateNode(ctx context.Context, name string, inCluster bool, nodeOptions orchestration.NodeOptions) (err error) {
+	g.log.Infof("updating node %s", name)
+
+	if err := g.AddNode(ctx, name, inCluster, nodeOptions); err != nil {
+		return fmt.Errorf("add node %s: %w", name, err)
+	}
+
+	if err := g.createNode(ctx, name); err != nil {
+		return fmt.Errorf("update node %s in k8s: %w", name, err)
+	}
+
+	if err := g.startNode(ctx, name); err != nil {
+		return fmt.Errorf("start node %s in k8s: %w", name, err)
+	}
+
+	return nil
+}
+
+// SetNetworkProfile changes the network profile of the node group's nodes.
+// Nodes must have been created with a network profile.
+func (g *NodeGroup) SetNetworkProfile(ctx context.Context, p orchestration.NetworkProfile) (err error) {
+	shaper, ok := g.nodeOrchestrator.(orchestration.NetworkShaper)
+	if !ok {
+		return fmt.Errorf("node group %s: network profiles are not supported by the orchestrator", g.name)
+	}
+
+	for _, name := range g.NodesSorted() {
+		if err := shaper.SetNetworkProfile(ctx, name, g.clusterOpts.Namespace, p); err != nil {
+			return fmt.Errorf("set network profile of node %s: %w", name, err)
+		}
+	}
+
+	g.lock.Lock()
+	g.opts.NetworkProfile = &p
+	g.lock.Unlock()
+
+	return nil
+}
+
 // Settlements returns NodeGroupSettlements
 func (g *NodeGroup) Settlements(ctx context.Context) (settlements orchestration.NodeGroupSettlements, err error) {
 	stream, err := g.SettlementsStream(ctx)
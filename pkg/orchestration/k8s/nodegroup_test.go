@@ -0,0 +1,89 @@
+package k8s_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/ethersphere/beekeeper/pkg/logging"
+	"github.com/ethersphere/beekeeper/pkg/orchestration"
+	orchestrationK8S "github.com/ethersphere/beekeeper/pkg/orchestration/k8s"
+)
+
+func TestNodeGroupSetNetworkProfile(t *testing.T) {
+	configMap := func(name string, data map[string]string) *v1.ConfigMap {
+		return &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace},
+			Data:       data,
+		}
+	}
+
+	k8sClient := newK8sClient(
+		configMap("bee-0", map[string]string{".bee.yaml": "full-node: true\n", "netem": ""}),
+		configMap("bee-1", map[string]string{".bee.yaml": "full-node: true\n", "netem": "delay 10ms"}),
+		configMap("light-0", map[string]string{".bee.yaml": "full-node: false\n"}),
+	)
+
+	c := orchestrationK8S.NewCluster("test", orchestration.ClusterOptions{Namespace: testNamespace, APIScheme: "http", APIDomain: "localhost"}, k8sClient, nil, logging.New(io.Discard, 0))
+	ctx := context.Background()
+
+	addNodes := func(group string, names ...string) orchestration.NodeGroup {
+		c.AddNodeGroup(group, orchestration.NodeGroupOptions{})
+		ng, err := c.NodeGroup(group)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, name := range names {
+			if err := ng.AddNode(ctx, name, false, orchestration.NodeOptions{}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return ng
+	}
+
+	bee := addNodes("bee", "bee-0", "bee-1")
+
+	profile := orchestration.NetworkProfile{Latency: 100 * time.Millisecond, Jitter: 20 * time.Millisecond, Loss: 1.5, Rate: "10mbit"}
+	if err := bee.SetNetworkProfile(ctx, profile); err != nil {
+		t.Fatalf("set network profile: %v", err)
+	}
+
+	for _, name := range []string{"bee-0", "bee-1"} {
+		cm, err := k8sClient.ConfigMap.Get(ctx, name, testNamespace)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := cm.Data["netem"], "delay 100ms 20ms loss 1.5% rate 10mbit"; got != want {
+			t.Errorf("node %s: got netem arguments %q, want %q", name, got, want)
+		}
+		if got := cm.Data[".bee.yaml"]; got != "full-node: true\n" {
+			t.Errorf("node %s: bee configuration changed to %q", name, got)
+		}
+	}
+
+	// removing the profile keeps the key, so that it can be set again
+	if err := bee.SetNetworkProfile(ctx, orchestration.NetworkProfile{}); err != nil {
+		t.Fatalf("remove network profile: %v", err)
+	}
+	cm, err := k8sClient.ConfigMap.Get(ctx, "bee-0", testNamespace)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := cm.Data["netem"]; !ok || got != "" {
+		t.Errorf("got netem arguments %q, want empty", got)
+	}
+
+	light := addNodes("light", "light-0")
+	if err := light.SetNetworkProfile(ctx, profile); !errors.Is(err, orchestration.ErrNetworkProfileNotEnabled) {
+		t.Errorf("got error %v, want %v", err, orchestration.ErrNetworkProfileNotEnabled)
+	}
+
+	if err := bee.SetNetworkProfile(ctx, orchestration.NetworkProfile{Rate: "fast"}); err == nil {
+		t.Error("expected error for invalid rate")
+	}
+}
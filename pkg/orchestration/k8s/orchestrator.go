@@ -1,10 +1,9 @@
 package k8s
 
 import (
-	"bytes"
 	"context"
 	"fmt"
-	"html/template"
+	"maps"
 
 	"github.com/ethersphere/beekeeper/pkg/k8s"
 	"github.com/ethersphere/beekeeper/pkg/k8s/configmap"
@@ -19,7 +18,10 @@ import (
 	"github.com/ethersphere/beekeeper/pkg/orchestration"
 )
 
-var _ orchestration.NodeOrchestrator = (*nodeOrchestrator)(nil)
+var (
+	_ orchestration.NodeOrchestrator = (*nodeOrchestrator)(nil)
+	_ orchestration.NetworkShaper    = (*nodeOrchestrator)(nil)
+)
 
 type nodeOrchestrator struct {
 	k8s *k8s.Client
@@ -55,18 +57,25 @@ func (n *nodeOrchestrator) StoppedNodes(ctx context.Context, namespace string) (
 // Create
 func (n *nodeOrchestrator) Create(ctx context.Context, o orchestration.CreateOptions) (err error) {
 	// bee configuration
-	var config bytes.Buffer
-	if err := template.Must(template.New("").Parse(configTemplate)).Execute(&config, o.Config); err != nil {
+	config, err := o.Config.YAML()
+	if err != nil {
 		return err
 	}
 
+	configData := map[string]string{
+		".bee.yaml": config,
+	}
+	if o.NetworkProfile != nil {
+		if configData[netemConfigKey], err = o.NetworkProfile.NetemArgs(); err != nil {
+			return err
+		}
+	}
+
 	configCM := o.Name
 	if _, err = n.k8s.ConfigMap.Set(ctx, configCM, o.Namespace, configmap.Options{
 		Annotations: o.Annotations,
 		Labels:      o.Labels,
-		Data: map[string]string{
-			".bee.yaml": config.String(),
-		},
+		Data:        configData,
 	}); err != nil {
 		return fmt.Errorf("set configmap in namespace %s: %w", o.Namespace, err)
 	}
@@ -331,6 +340,7 @@ func (n *nodeOrchestrator) Create(ctx context.Context, o orchestration.CreateOpt
 						LibP2PEnabled:          libP2PEnabled,
 						SwarmEnabled:           swarmEnabled,
 						AutoTLSEnabled:         autoTLSEnabled,
+						NetworkProfileEnabled:  o.NetworkProfile != nil,
 					}),
 					NodeSelector: o.NodeSelector,
 					PodSecurityContext: pod.PodSecurityContext{
@@ -366,6 +376,39 @@ func (n *nodeOrchestrator) Create(ctx context.Context, o orchestration.CreateOpt
 	return nil
 }
 
+// SetNetworkProfile implements orchestration.NetworkShaper. It updates the
+// netem arguments in the node's configmap, which are applied by the sidecar
+// once the mounted configmap is refreshed.
+func (n *nodeOrchestrator) SetNetworkProfile(ctx context.Context, name string, namespace string, p orchestration.NetworkProfile) (err error) {
+	args, err := p.NetemArgs()
+	if err != nil {
+		return err
+	}
+
+	cm, err := n.k8s.ConfigMap.Get(ctx, name, namespace)
+	if err != nil {
+		return err
+	}
+	if _, ok := cm.Data[netemConfigKey]; !ok {
+		return fmt.Errorf("node %s: %w", name, orchestration.ErrNetworkProfileNotEnabled)
+	}
+
+	data := maps.Clone(cm.Data)
+	data[netemConfigKey] = args
+
+	if _, err = n.k8s.ConfigMap.Set(ctx, name, namespace, configmap.Options{
+		Annotations: cm.Annotations,
+		Labels:      cm.Labels,
+		Data:        data,
+		BinaryData:  cm.BinaryData,
+	}); err != nil {
+		return fmt.Errorf("set configmap in namespace %s: %w", namespace, err)
+	}
+	n.log.Infof("network profile of node %s is set to %q in namespace %s", name, args, namespace)
+
+	return nil
+}
+
 func (n *nodeOrchestrator) Delete(ctx context.Context, name string, namespace string) (err error) {
 	// statefulset
 	if err := n.k8s.StatefulSet.Delete(ctx, name, namespace); err != nil {
@@ -0,0 +1,468 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ethersphere/beekeeper/pkg/k8s"
+	"github.com/ethersphere/beekeeper/pkg/orchestration"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// ChangeType represents the action that reconciles a node with its desired state
+type ChangeType string
+
+const (
+	ChangeAdd    ChangeType = "add"
+	ChangeRemove ChangeType = "remove"
+	ChangeUpdate ChangeType = "update"
+)
+
+// DesiredNode represents a node as it is defined in the cluster configuration
+type DesiredNode struct {
+	Name        string
+	NodeGroup   string
+	Image       string
+	Config      string            // rendered Bee configuration file
+	Labels      map[string]string // labels of the node group
+	Resources   Resources
+	Persistence Persistence
+	NetemArgs   string // tc netem arguments of the node group's network profile
+}
+
+// ObservedNode represents a node as it exists in Kubernetes
+type ObservedNode struct {
+	Name        string
+	Image       string
+	Config      string            // Bee configuration file from the node's configmap
+	Labels      map[string]string // labels of the node's statefulset
+	Resources   Resources
+	Persistence Persistence
+	NetemArgs   string   // tc netem arguments from the node's configmap
+	Missing     []string // resources of the node that do not exist
+}
+
+// Resources represents compute resources of the Bee container
+type Resources struct {
+	LimitCPU      string
+	LimitMemory   string
+	RequestCPU    string
+	RequestMemory string
+}
+
+// Persistence represents the persistent volume of the node's data
+type Persistence struct {
+	Enabled        bool
+	StorageClass   string
+	StorageRequest string
+}
+
+// Change represents a single action of the reconcile plan
+type Change struct {
+	Type      ChangeType
+	Node      string
+	NodeGroup string
+	Details   []string
+	// Recreate is set when immutable fields of the node's statefulset
+	// changed, so that it must be deleted before it is created again
+	Recreate bool
+}
+
+// Plan represents the actions that reconcile a cluster with its configuration
+type Plan []Change
+
+// Changes returns changes of the given type
+func (p Plan) Changes(t ChangeType) (changes []Change) {
+	for _, c := range p {
+		if c.Type == t {
+			changes = append(changes, c)
+		}
+	}
+	return changes
+}
+
+// String returns the plan as a human readable diff
+func (p Plan) String() string {
+	if len(p) == 0 {
+		return "no changes\n"
+	}
+
+	var b strings.Builder
+	for _, c := range p {
+		switch c.Type {
+		case ChangeAdd:
+			fmt.Fprintf(&b, "+ %s (node group %s)\n", c.Node, c.NodeGroup)
+		case ChangeRemove:
+			fmt.Fprintf(&b, "- %s\n", c.Node)
+		case ChangeUpdate:
+			if c.Recreate {
+				fmt.Fprintf(&b, "~ %s (node group %s, statefulset recreated)\n", c.Node, c.NodeGroup)
+				break
+			}
+			fmt.Fprintf(&b, "~ %s (node group %s)\n", c.Node, c.NodeGroup)
+		}
+		for _, d := range c.Details {
+			fmt.Fprintf(&b, "    %s\n", d)
+		}
+	}
+
+	add, update, remove := len(p.Changes(ChangeAdd)), len(p.Changes(ChangeUpdate)), len(p.Changes(ChangeRemove))
+	fmt.Fprintf(&b, "%d to add, %d to update, %d to remove\n", add, update, remove)
+
+	return b.String()
+}
+
+// ObserveNodes returns nodes in the namespace whose statefulsets match the
+// label selector, by their names, together with their image, Bee
+// configuration, the statefulset fields that come from node group options
+// and the resources that are missing
+func ObserveNodes(ctx context.Context, k8sClient *k8s.Client, namespace, labelSelector string) (map[string]ObservedNode, error) {
+	statefulSets, err := k8sClient.StatefulSet.StatefulSets(ctx, namespace, labelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("list statefulsets: %w", err)
+	}
+
+	services, err := k8sClient.Service.GetNodes(ctx, namespace, labelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("list api services: %w", err)
+	}
+	apiServices := make(map[string]bool, len(services))
+	for _, s := range services {
+		apiServices[s.Name] = true
+	}
+
+	ingresses, err := ingressHosts(ctx, k8sClient, namespace, labelSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make(map[string]ObservedNode, len(statefulSets))
+	for _, s := range statefulSets {
+		n := ObservedNode{
+			Name:        s.Name,
+			Image:       statefulSetImage(s),
+			Labels:      s.Labels,
+			Resources:   statefulSetResources(s),
+			Persistence: statefulSetPersistence(s),
+		}
+
+		cm, err := k8sClient.ConfigMap.Get(ctx, s.Name, namespace)
+		switch {
+		case apierrors.IsNotFound(err):
+			n.Missing = append(n.Missing, "configmap")
+		case err != nil:
+			return nil, fmt.Errorf("node %s configuration: %w", s.Name, err)
+		default:
+			n.Config = cm.Data[".bee.yaml"]
+			n.NetemArgs = cm.Data[netemConfigKey]
+		}
+
+		if !apiServices[s.Name] {
+			n.Missing = append(n.Missing, "api service")
+		}
+		if _, ok := ingresses[s.Name]; !ok {
+			n.Missing = append(n.Missing, "ingress")
+		}
+
+		nodes[s.Name] = n
+	}
+
+	return nodes, nil
+}
+
+// statefulSetImage returns the image of the Bee container of the statefulset
+func statefulSetImage(s *appsv1.StatefulSet) string {
+	if c := beeContainer(s); c != nil {
+		return c.Image
+	}
+	return ""
+}
+
+// statefulSetResources returns compute resources of the Bee container of the
+// statefulset
+func statefulSetResources(s *appsv1.StatefulSet) (r Resources) {
+	c := beeContainer(s)
+	if c == nil {
+		return r
+	}
+
+	quantity := func(l v1.ResourceList, name v1.ResourceName) string {
+		if q, ok := l[name]; ok {
+			return q.String()
+		}
+		return ""
+	}
+
+	return Resources{
+		LimitCPU:      quantity(c.Resources.Limits, v1.ResourceCPU),
+		LimitMemory:   quantity(c.Resources.Limits, v1.ResourceMemory),
+		RequestCPU:    quantity(c.Resources.Requests, v1.ResourceCPU),
+		RequestMemory: quantity(c.Resources.Requests, v1.ResourceMemory),
+	}
+}
+
+// statefulSetPersistence returns the data volume claim of the statefulset
+func statefulSetPersistence(s *appsv1.StatefulSet) (p Persistence) {
+	for _, t := range s.Spec.VolumeClaimTemplates {
+		if t.Name != "data" {
+			continue
+		}
+		p.Enabled = true
+		if t.Spec.StorageClassName != nil {
+			p.StorageClass = *t.Spec.StorageClassName
+		}
+		if q, ok := t.Spec.Resources.Requests[v1.ResourceStorage]; ok {
+			p.StorageRequest = q.String()
+		}
+	}
+	return p
+}
+
+// beeContainer returns the Bee container of the statefulset, which is named
+// after it, or the first container
+func beeContainer(s *appsv1.StatefulSet) *v1.Container {
+	containers := s.Spec.Template.Spec.Containers
+	for i := range containers {
+		if containers[i].Name == s.Name {
+			return &containers[i]
+		}
+	}
+	if len(containers) > 0 {
+		return &containers[0]
+	}
+	return nil
+}
+
+// PlanReconcile returns the plan that adds desired nodes that do not exist,
+// updates nodes whose image, configuration, compute resources, labels,
+// persistence, network profile or Kubernetes resources differ and removes
+// observed nodes of the node groups that are not desired. Statefulsets whose
+// labels or persistence differ are recreated, as their selector and volume
+// claims can not be updated. Observed nodes that are not named after one of
+// the node groups, like nodes of other clusters in the same namespace, are
+// never removed.
+func PlanReconcile(nodeGroups []string, desired []DesiredNode, observed map[string]ObservedNode) (p Plan) {
+	desiredNames := make(map[string]bool, len(desired))
+
+	for _, d := range desired {
+		desiredNames[d.Name] = true
+
+		o, ok := observed[d.Name]
+		if !ok {
+			p = append(p, Change{Type: ChangeAdd, Node: d.Name, NodeGroup: d.NodeGroup})
+			continue
+		}
+
+		var details []string
+		if o.Image != d.Image {
+			details = append(details, fmt.Sprintf("image: %s -> %s", o.Image, d.Image))
+		}
+		details = append(details, configDiff(o.Config, d.Config)...)
+		details = append(details, resourcesDiff(o.Resources, d.Resources)...)
+		if o.NetemArgs != d.NetemArgs {
+			details = append(details, fmt.Sprintf("network profile: %s -> %s", valueOrNone(o.NetemArgs), valueOrNone(d.NetemArgs)))
+		}
+
+		immutable := labelsDiff(o.Labels, nodeLabels(d.Labels, d.Name))
+		immutable = append(immutable, persistenceDiff(o.Persistence, d.Persistence)...)
+		details = append(details, immutable...)
+
+		for _, m := range o.Missing {
+			details = append(details, "missing "+m)
+		}
+
+		if len(details) > 0 {
+			p = append(p, Change{Type: ChangeUpdate, Node: d.Name, NodeGroup: d.NodeGroup, Details: details, Recreate: len(immutable) > 0})
+		}
+	}
+
+	var extra []string
+	for name := range observed {
+		if !desiredNames[name] && nodeGroupOf(name, nodeGroups) != "" {
+			extra = append(extra, name)
+		}
+	}
+	sort.Strings(extra)
+
+	for _, name := range extra {
+		p = append(p, Change{Type: ChangeRemove, Node: name, NodeGroup: nodeGroupOf(name, nodeGroups)})
+	}
+
+	return p
+}
+
+// nodeGroupOf returns the node group whose nodes are named like the node,
+// <node group>-<index>, or an empty string if there is none
+func nodeGroupOf(name string, nodeGroups []string) string {
+	for _, ng := range nodeGroups {
+		index, ok := strings.CutPrefix(name, ng+"-")
+		if !ok || index == "" {
+			continue
+		}
+		if _, err := strconv.ParseUint(index, 10, 64); err == nil {
+			return ng
+		}
+	}
+	return ""
+}
+
+// configDiff returns differences between keys of two Bee configuration files
+func configDiff(observed, desired string) (details []string) {
+	o, d := parseConfig(observed), parseConfig(desired)
+
+	keys := make(map[string]struct{}, len(o)+len(d))
+	for k := range o {
+		keys[k] = struct{}{}
+	}
+	for k := range d {
+		keys[k] = struct{}{}
+	}
+
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	for _, k := range sorted {
+		ov, oOK := o[k]
+		dv, dOK := d[k]
+		switch {
+		case !oOK:
+			details = append(details, fmt.Sprintf("config %s: + %s", k, dv))
+		case !dOK:
+			details = append(details, fmt.Sprintf("config %s: - %s", k, ov))
+		case ov != dv:
+			details = append(details, fmt.Sprintf("config %s: %s -> %s", k, ov, dv))
+		}
+	}
+
+	return details
+}
+
+// resourcesDiff returns differences between compute resources, comparing
+// quantities by their values
+func resourcesDiff(observed, desired Resources) (details []string) {
+	for _, r := range []struct {
+		name              string
+		observed, desired string
+	}{
+		{"limits.cpu", observed.LimitCPU, desired.LimitCPU},
+		{"limits.memory", observed.LimitMemory, desired.LimitMemory},
+		{"requests.cpu", observed.RequestCPU, desired.RequestCPU},
+		{"requests.memory", observed.RequestMemory, desired.RequestMemory},
+	} {
+		if !equalQuantities(r.observed, r.desired) {
+			details = append(details, fmt.Sprintf("resources %s: %s -> %s", r.name, valueOrNone(r.observed), valueOrNone(r.desired)))
+		}
+	}
+	return details
+}
+
+// labelsDiff returns differences between labels
+func labelsDiff(observed, desired map[string]string) (details []string) {
+	keys := slices.Sorted(maps.Keys(mergeMaps(observed, desired)))
+	for _, k := range keys {
+		ov, oOK := observed[k]
+		dv, dOK := desired[k]
+		switch {
+		case !oOK:
+			details = append(details, fmt.Sprintf("label %s: + %s", k, dv))
+		case !dOK:
+			details = append(details, fmt.Sprintf("label %s: - %s", k, ov))
+		case ov != dv:
+			details = append(details, fmt.Sprintf("label %s: %s -> %s", k, ov, dv))
+		}
+	}
+	return details
+}
+
+// persistenceDiff returns differences between persistent volumes of data
+func persistenceDiff(observed, desired Persistence) (details []string) {
+	if observed.Enabled != desired.Enabled {
+		return []string{fmt.Sprintf("persistence: %t -> %t", observed.Enabled, desired.Enabled)}
+	}
+	if !desired.Enabled {
+		return nil
+	}
+	if observed.StorageClass != desired.StorageClass {
+		details = append(details, fmt.Sprintf("persistence storage class: %s -> %s", valueOrNone(observed.StorageClass), valueOrNone(desired.StorageClass)))
+	}
+	if !equalQuantities(observed.StorageRequest, desired.StorageRequest) {
+		details = append(details, fmt.Sprintf("persistence storage request: %s -> %s", valueOrNone(observed.StorageRequest), valueOrNone(desired.StorageRequest)))
+	}
+	return details
+}
+
+// equalQuantities returns whether two resource quantities are equal, so that
+// for example 1Gi and 1024Mi are not reported as a difference
+func equalQuantities(a, b string) bool {
+	if a == "" || b == "" {
+		return a == b
+	}
+	qa, errA := resource.ParseQuantity(a)
+	qb, errB := resource.ParseQuantity(b)
+	if errA != nil || errB != nil {
+		return a == b
+	}
+	return qa.Cmp(qb) == 0
+}
+
+func valueOrNone(v string) string {
+	if v == "" {
+		return "none"
+	}
+	return v
+}
+
+// parseConfig returns values of a flat Bee configuration file by their keys
+func parseConfig(config string) map[string]string {
+	values := make(map[string]string)
+	for line := range strings.Lines(config) {
+		k, v, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		values[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+	return values
+}
+
+// NodeKeys returns keys of the node stored in its keys secret, so that they
+// are kept when the node is recreated
+func NodeKeys(ctx context.Context, k8sClient *k8s.Client, name, namespace string) (libP2PKey string, swarmKey *orchestration.EncryptedKey, err error) {
+	s, err := k8sClient.Secret.Get(ctx, fmt.Sprintf("%s-keys", name), namespace)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", nil, nil
+		}
+		return "", nil, fmt.Errorf("node %s keys: %w", name, err)
+	}
+
+	value := func(key string) string {
+		if v, ok := s.Data[key]; ok {
+			return string(v)
+		}
+		return s.StringData[key]
+	}
+
+	libP2PKey = value("libp2p")
+
+	if v := value("swarm"); v != "" {
+		swarmKey = new(orchestration.EncryptedKey)
+		if err := json.Unmarshal([]byte(v), swarmKey); err != nil {
+			return "", nil, fmt.Errorf("node %s swarm key: %w", name, err)
+		}
+	}
+
+	return libP2PKey, swarmKey, nil
+}
@@ -0,0 +1,260 @@
+package k8s_test
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/ethersphere/beekeeper/pkg/orchestration"
+	orchestrationK8S "github.com/ethersphere/beekeeper/pkg/orchestration/k8s"
+)
+
+func TestObserveNodes(t *testing.T) {
+	labels := map[string]string{"app.kubernetes.io/name": "bee"}
+	storageClass := "local-path"
+
+	var objects []runtime.Object
+	addNode := func(name, image string, withService, withIngress bool) {
+		objects = append(objects,
+			&appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace, Labels: labels},
+				Spec: appsv1.StatefulSetSpec{
+					Template: v1.PodTemplateSpec{
+						Spec: v1.PodSpec{
+							InitContainers: []v1.Container{{Name: "init", Image: "busybox"}},
+							Containers: []v1.Container{{
+								Name:  name,
+								Image: image,
+								Resources: v1.ResourceRequirements{
+									Limits:   v1.ResourceList{v1.ResourceMemory: resource.MustParse("2Gi")},
+									Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("500m")},
+								},
+							}},
+						},
+					},
+					VolumeClaimTemplates: []v1.PersistentVolumeClaim{{
+						ObjectMeta: metav1.ObjectMeta{Name: "data"},
+						Spec: v1.PersistentVolumeClaimSpec{
+							StorageClassName: &storageClass,
+							Resources: v1.VolumeResourceRequirements{
+								Requests: v1.ResourceList{v1.ResourceStorage: resource.MustParse("34Gi")},
+							},
+						},
+					}},
+				},
+			},
+			&v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace, Labels: labels},
+				Data:       map[string]string{".bee.yaml": "full-node: true\n", "netem": "loss 1%"},
+			},
+		)
+		if withService {
+			objects = append(objects, &v1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace, Labels: labels},
+				Spec: v1.ServiceSpec{
+					ClusterIP: "10.0.0.1",
+					Ports:     []v1.ServicePort{{Name: "api", Port: 1633}},
+				},
+			})
+		}
+		if withIngress {
+			objects = append(objects, &networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace, Labels: labels},
+				Spec: networkingv1.IngressSpec{
+					Rules: []networkingv1.IngressRule{{Host: name + ".localhost"}},
+				},
+			})
+		}
+	}
+
+	addNode("bee-0", "ethersphere/bee:2.5.0", true, true)
+	addNode("bee-1", "ethersphere/bee:2.6.0", false, false)
+
+	observed, err := orchestrationK8S.ObserveNodes(context.Background(), newK8sClient(objects...), testNamespace, testLabelSelector)
+	if err != nil {
+		t.Fatalf("observe nodes: %v", err)
+	}
+
+	resources := orchestrationK8S.Resources{LimitMemory: "2Gi", RequestCPU: "500m"}
+	persistence := orchestrationK8S.Persistence{Enabled: true, StorageClass: "local-path", StorageRequest: "34Gi"}
+	want := map[string]orchestrationK8S.ObservedNode{
+		"bee-0": {Name: "bee-0", Image: "ethersphere/bee:2.5.0", Config: "full-node: true\n", Labels: labels, Resources: resources, Persistence: persistence, NetemArgs: "loss 1%"},
+		"bee-1": {Name: "bee-1", Image: "ethersphere/bee:2.6.0", Config: "full-node: true\n", Labels: labels, Resources: resources, Persistence: persistence, NetemArgs: "loss 1%", Missing: []string{"api service", "ingress"}},
+	}
+	if !reflect.DeepEqual(observed, want) {
+		t.Errorf("got observed nodes %+v, want %+v", observed, want)
+	}
+}
+
+func TestPlanReconcile(t *testing.T) {
+	desired := []orchestrationK8S.DesiredNode{
+		{Name: "bee-0", NodeGroup: "bee", Image: "ethersphere/bee:2.6.0", Config: "full-node: true\nwarmup-time: 0s\n"},
+		{Name: "bee-1", NodeGroup: "bee", Image: "ethersphere/bee:2.6.0", Config: "full-node: true\n"},
+		{Name: "bee-2", NodeGroup: "bee", Image: "ethersphere/bee:2.6.0", Config: "full-node: true\n"},
+		{Name: "bee-3", NodeGroup: "bee", Image: "ethersphere/bee:2.6.0", Config: "full-node: true\n"},
+	}
+	observed := map[string]orchestrationK8S.ObservedNode{
+		"bee-0": {Name: "bee-0", Image: "ethersphere/bee:2.5.0", Config: "full-node: false\nmainnet: false\n", Labels: instanceLabels("bee-0")},
+		"bee-1": {Name: "bee-1", Image: "ethersphere/bee:2.6.0", Config: "full-node: true\n", Labels: instanceLabels("bee-1")},
+		"bee-2": {Name: "bee-2", Image: "ethersphere/bee:2.6.0", Config: "full-node: true\n", Labels: instanceLabels("bee-2"), Missing: []string{"ingress"}},
+		"bee-5": {Name: "bee-5", Image: "ethersphere/bee:2.6.0"},
+		"bee-4": {Name: "bee-4", Image: "ethersphere/bee:2.6.0"},
+		// nodes of other clusters in the namespace
+		"bee-light-0": {Name: "bee-light-0", Image: "ethersphere/bee:2.6.0"},
+		"bootnode-0":  {Name: "bootnode-0", Image: "ethersphere/bee:2.6.0"},
+	}
+
+	plan := orchestrationK8S.PlanReconcile([]string{"bee"}, desired, observed)
+
+	want := orchestrationK8S.Plan{
+		{Type: orchestrationK8S.ChangeUpdate, Node: "bee-0", NodeGroup: "bee", Details: []string{
+			"image: ethersphere/bee:2.5.0 -> ethersphere/bee:2.6.0",
+			"config full-node: false -> true",
+			"config mainnet: - false",
+			"config warmup-time: + 0s",
+		}},
+		{Type: orchestrationK8S.ChangeUpdate, Node: "bee-2", NodeGroup: "bee", Details: []string{"missing ingress"}},
+		{Type: orchestrationK8S.ChangeAdd, Node: "bee-3", NodeGroup: "bee"},
+		{Type: orchestrationK8S.ChangeRemove, Node: "bee-4", NodeGroup: "bee"},
+		{Type: orchestrationK8S.ChangeRemove, Node: "bee-5", NodeGroup: "bee"},
+	}
+	if !reflect.DeepEqual(plan, want) {
+		t.Fatalf("got plan %+v, want %+v", plan, want)
+	}
+
+	if !strings.HasSuffix(plan.String(), "1 to add, 2 to update, 2 to remove\n") {
+		t.Errorf("got plan summary:\n%s", plan)
+	}
+
+	if got := orchestrationK8S.PlanReconcile([]string{"bee"}, desired[1:2], observed); len(got.Changes(orchestrationK8S.ChangeRemove)) != len(got) || len(got) != 4 {
+		t.Errorf("got plan %+v, want removal of 4 extra nodes", got)
+	}
+	if got := orchestrationK8S.PlanReconcile(nil, nil, nil).String(); got != "no changes\n" {
+		t.Errorf("got empty plan %q", got)
+	}
+}
+
+func TestPlanReconcileNodeGroupOptions(t *testing.T) {
+	desired := orchestrationK8S.DesiredNode{
+		Name:      "bee-0",
+		NodeGroup: "bee",
+		Image:     "ethersphere/bee:2.6.0",
+		Labels:    map[string]string{"app.kubernetes.io/name": "bee"},
+		Resources: orchestrationK8S.Resources{
+			LimitCPU:      "2",
+			LimitMemory:   "2Gi",
+			RequestMemory: "1Gi",
+		},
+		Persistence: orchestrationK8S.Persistence{Enabled: true, StorageClass: "local-path", StorageRequest: "34Gi"},
+	}
+	observed := orchestrationK8S.ObservedNode{
+		Name:   "bee-0",
+		Image:  "ethersphere/bee:2.6.0",
+		Labels: map[string]string{"app.kubernetes.io/name": "bee", "app.kubernetes.io/instance": "bee-0"},
+		Resources: orchestrationK8S.Resources{
+			LimitCPU:      "2",
+			LimitMemory:   "2048Mi",
+			RequestMemory: "1Gi",
+		},
+		Persistence: orchestrationK8S.Persistence{Enabled: true, StorageClass: "local-path", StorageRequest: "34Gi"},
+	}
+
+	// equal quantities in different units are not a difference
+	if plan := orchestrationK8S.PlanReconcile([]string{"bee"}, []orchestrationK8S.DesiredNode{desired}, map[string]orchestrationK8S.ObservedNode{"bee-0": observed}); len(plan) != 0 {
+		t.Fatalf("got plan %+v, want no changes", plan)
+	}
+
+	changed := desired
+	changed.Resources.RequestCPU = "500m"
+	changed.Resources.LimitMemory = "4Gi"
+	plan := orchestrationK8S.PlanReconcile([]string{"bee"}, []orchestrationK8S.DesiredNode{changed}, map[string]orchestrationK8S.ObservedNode{"bee-0": observed})
+	want := orchestrationK8S.Plan{
+		{Type: orchestrationK8S.ChangeUpdate, Node: "bee-0", NodeGroup: "bee", Details: []string{
+			"resources limits.memory: 2048Mi -> 4Gi",
+			"resources requests.cpu: none -> 500m",
+		}},
+	}
+	if !reflect.DeepEqual(plan, want) {
+		t.Fatalf("got plan %+v, want %+v", plan, want)
+	}
+
+	changed = desired
+	changed.Labels = map[string]string{"app.kubernetes.io/name": "bee", "team": "swarm"}
+	changed.Persistence.StorageRequest = "64Gi"
+	plan = orchestrationK8S.PlanReconcile([]string{"bee"}, []orchestrationK8S.DesiredNode{changed}, map[string]orchestrationK8S.ObservedNode{"bee-0": observed})
+	want = orchestrationK8S.Plan{
+		{Type: orchestrationK8S.ChangeUpdate, Node: "bee-0", NodeGroup: "bee", Details: []string{
+			"label team: + swarm",
+			"persistence storage request: 34Gi -> 64Gi",
+		}, Recreate: true},
+	}
+	if !reflect.DeepEqual(plan, want) {
+		t.Fatalf("got plan %+v, want %+v", plan, want)
+	}
+	if !strings.HasPrefix(plan.String(), "~ bee-0 (node group bee, statefulset recreated)\n") {
+		t.Errorf("got plan:\n%s", plan)
+	}
+
+	changed = desired
+	changed.NetemArgs = "delay 100ms"
+	plan = orchestrationK8S.PlanReconcile([]string{"bee"}, []orchestrationK8S.DesiredNode{changed}, map[string]orchestrationK8S.ObservedNode{"bee-0": observed})
+	if len(plan) != 1 || !slices.Equal(plan[0].Details, []string{"network profile: none -> delay 100ms"}) || plan[0].Recreate {
+		t.Errorf("got plan %+v, want network profile change", plan)
+	}
+
+	changed = desired
+	changed.Persistence = orchestrationK8S.Persistence{}
+	plan = orchestrationK8S.PlanReconcile([]string{"bee"}, []orchestrationK8S.DesiredNode{changed}, map[string]orchestrationK8S.ObservedNode{"bee-0": observed})
+	if len(plan) != 1 || !slices.Equal(plan[0].Details, []string{"persistence: true -> false"}) || !plan[0].Recreate {
+		t.Errorf("got plan %+v, want recreation without persistence", plan)
+	}
+}
+
+func instanceLabels(name string) map[string]string {
+	return map[string]string{"app.kubernetes.io/instance": name}
+}
+
+func TestNodeKeys(t *testing.T) {
+	key, err := orchestration.NewEncryptedKey("password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	swarmKey, err := key.StringJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	k8sClient := newK8sClient(&v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "bee-0-keys", Namespace: testNamespace},
+		Data: map[string][]byte{
+			"libp2p": []byte(`{"address":"libp2p"}`),
+			"swarm":  []byte(swarmKey),
+		},
+	})
+
+	libP2PKey, gotSwarmKey, err := orchestrationK8S.NodeKeys(context.Background(), k8sClient, "bee-0", testNamespace)
+	if err != nil {
+		t.Fatalf("node keys: %v", err)
+	}
+	if libP2PKey != `{"address":"libp2p"}` {
+		t.Errorf("got libp2p key %q", libP2PKey)
+	}
+	if !reflect.DeepEqual(gotSwarmKey, key) {
+		t.Errorf("got swarm key %+v, want %+v", gotSwarmKey, key)
+	}
+
+	// nodes without keys secret have no keys
+	libP2PKey, gotSwarmKey, err = orchestrationK8S.NodeKeys(context.Background(), k8sClient, "bee-1", testNamespace)
+	if err != nil || libP2PKey != "" || gotSwarmKey != nil {
+		t.Errorf("got keys %q, %v (%v), want none", libP2PKey, gotSwarmKey, err)
+	}
+}
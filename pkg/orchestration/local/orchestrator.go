@@ -0,0 +1,504 @@
+// Package local implements a node orchestrator that runs Bee nodes as
+// processes of the bee binary on the local machine, for development and CI
+// environments without Kubernetes.
+//
+// Every node gets a directory named by the cluster namespace and the node
+// name, with its data, the generated .bee.yaml configuration file, keys, log
+// and the ID and start time of its process. Nodes are assigned a pair of API
+// and P2P ports on the loopback interface when they are added to the cluster,
+// which are kept in the node's directory, so that later commands reach the
+// same nodes.
+package local
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethersphere/beekeeper/pkg/logging"
+	"github.com/ethersphere/beekeeper/pkg/orchestration"
+)
+
+const (
+	// DefaultBeePath is the bee binary looked up in PATH
+	DefaultBeePath = "bee"
+	// DefaultBasePort is the API port of the first node, every node uses the
+	// two consecutive ports for its API and P2P listeners
+	DefaultBasePort = 21633
+
+	host       = "127.0.0.1"
+	configFile = ".bee.yaml"
+	stateFile  = "node.json"
+	pidFile    = "bee.pid"
+	logFile    = "bee.log"
+	keysDir    = "keys"
+)
+
+var (
+	// readyPollInterval is how often readiness of a starting node is
+	// checked. It is a package var so tests can shorten it.
+	readyPollInterval = time.Second
+	// stopTimeout is how long a node is given to shut down gracefully before
+	// its process is killed. It is a package var so tests can shorten it.
+	stopTimeout = 60 * time.Second
+
+	// bootnodeAddrRe matches addresses of Kubernetes services of bootnodes,
+	// for example /dns4/bootnode-0-headless.namespace.svc.cluster.local/tcp/1634
+	bootnodeAddrRe = regexp.MustCompile(`/dns4/([^/.]+)[^/]*/tcp/[0-9]+`)
+)
+
+var (
+	_ orchestration.NodeOrchestrator = (*NodeOrchestrator)(nil)
+	_ orchestration.APIURLProvider   = (*NodeOrchestrator)(nil)
+)
+
+// Options represents options of the local node orchestrator
+type Options struct {
+	Dir        string       // directory of node directories
+	BeePath    string       // bee binary, DefaultBeePath if empty
+	BasePort   int          // API port of the first node, DefaultBasePort if 0
+	HTTPClient *http.Client // client for readiness checks, http.DefaultClient if nil
+}
+
+// NodeOrchestrator runs Bee nodes as local processes
+type NodeOrchestrator struct {
+	dir        string
+	beePath    string
+	basePort   int
+	httpClient *http.Client
+	log        logging.Logger
+
+	mu        sync.Mutex
+	allocated map[string]ports // ports of nodes that are not created yet, by node directory
+}
+
+// ports are the ports assigned to the node
+type ports struct {
+	API int `json:"apiPort"`
+	P2P int `json:"p2pPort"`
+}
+
+// NewNodeOrchestrator returns a new local Bee node orchestrator
+func NewNodeOrchestrator(o Options, log logging.Logger) *NodeOrchestrator {
+	if o.BeePath == "" {
+		o.BeePath = DefaultBeePath
+	}
+	if o.BasePort == 0 {
+		o.BasePort = DefaultBasePort
+	}
+	if o.HTTPClient == nil {
+		o.HTTPClient = http.DefaultClient
+	}
+
+	return &NodeOrchestrator{
+		dir:        o.Dir,
+		beePath:    o.BeePath,
+		basePort:   o.BasePort,
+		httpClient: o.HTTPClient,
+		log:        log,
+		allocated:  make(map[string]ports),
+	}
+}
+
+// APIURL implements orchestration.APIURLProvider.
+func (n *NodeOrchestrator) APIURL(name string, namespace string) (*url.URL, error) {
+	p, err := n.ports(n.nodeDir(name, namespace))
+	if err != nil {
+		return nil, fmt.Errorf("ports of node %s: %w", name, err)
+	}
+	return &url.URL{Scheme: "http", Host: fmt.Sprintf("%s:%d", host, p.API)}, nil
+}
+
+// Create writes the configuration and keys of the node to its directory.
+// Listen addresses and the data directory of the configuration are replaced
+// with those of the node, and addresses of bootnodes that run locally are
+// replaced with their local P2P addresses.
+func (n *NodeOrchestrator) Create(ctx context.Context, o orchestration.CreateOptions) (err error) {
+	if o.NetworkProfile != nil {
+		n.log.Warningf("network profile of node %s is ignored, local processes share the host network", o.Name)
+	}
+
+	dir := n.nodeDir(o.Name, o.Namespace)
+
+	p, err := n.ports(dir)
+	if err != nil {
+		return fmt.Errorf("ports of node %s: %w", o.Name, err)
+	}
+
+	if err := os.MkdirAll(filepath.Join(dir, keysDir), 0o700); err != nil {
+		return fmt.Errorf("create directory of node %s: %w", o.Name, err)
+	}
+
+	config := o.Config
+	config.APIAddr = fmt.Sprintf("%s:%d", host, p.API)
+	config.P2PAddr = fmt.Sprintf(":%d", p.P2P)
+	config.DataDir = dir
+	config.AllowPrivateCIDRs = true
+	config.NATAddr = ""
+	// secure WebSockets require a public domain for certificates
+	config.P2PWSSEnable = false
+	config.P2PWSSAddr = ""
+	config.NATWSSAddr = ""
+	config.Bootnodes = n.localBootnodes(config.Bootnodes, o.Namespace)
+
+	yaml, err := config.YAML()
+	if err != nil {
+		return fmt.Errorf("bee configuration of node %s: %w", o.Name, err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, configFile), []byte(yaml), 0o600); err != nil {
+		return fmt.Errorf("write configuration of node %s: %w", o.Name, err)
+	}
+
+	if len(o.LibP2PKey) > 0 {
+		if err := os.WriteFile(filepath.Join(dir, keysDir, "libp2p_v2.key"), []byte(o.LibP2PKey), 0o600); err != nil {
+			return fmt.Errorf("write libp2p key of node %s: %w", o.Name, err)
+		}
+	}
+	if o.SwarmKey != nil {
+		key, err := o.SwarmKey.StringJSON()
+		if err != nil {
+			return fmt.Errorf("json encode swarm key: %w", err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, keysDir, "swarm.key"), []byte(key), 0o600); err != nil {
+			return fmt.Errorf("write swarm key of node %s: %w", o.Name, err)
+		}
+	}
+
+	state, err := json.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("json encode ports of node %s: %w", o.Name, err)
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if err := os.WriteFile(filepath.Join(dir, stateFile), state, 0o600); err != nil {
+		return fmt.Errorf("write state of node %s: %w", o.Name, err)
+	}
+	delete(n.allocated, dir)
+
+	n.log.Infof("node %s is created in %s with API port %d and P2P port %d", o.Name, dir, p.API, p.P2P)
+
+	return nil
+}
+
+// Delete stops the node and removes its directory, including its data
+func (n *NodeOrchestrator) Delete(ctx context.Context, name string, namespace string) (err error) {
+	if err := n.Stop(ctx, name, namespace); err != nil {
+		return err
+	}
+
+	dir := n.nodeDir(name, namespace)
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("remove directory of node %s: %w", name, err)
+	}
+	n.log.Infof("node %s is deleted from %s", name, dir)
+
+	return nil
+}
+
+// Ready waits until the API of the running node reports readiness. It
+// returns an error if the process of the node is not running.
+func (n *NodeOrchestrator) Ready(ctx context.Context, name string, namespace string) (ready bool, err error) {
+	dir := n.nodeDir(name, namespace)
+
+	p, err := readState(dir)
+	if err != nil {
+		return false, fmt.Errorf("node %s: %w", name, err)
+	}
+
+	readinessURL := fmt.Sprintf("http://%s:%d/readiness", host, p.API)
+
+	for {
+		if !running(dir) {
+			return false, fmt.Errorf("process of node %s is not running, see %s", name, filepath.Join(dir, logFile))
+		}
+
+		if n.readiness(ctx, readinessURL) {
+			return true, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case <-time.After(readyPollInterval):
+		}
+	}
+}
+
+func (n *NodeOrchestrator) readiness(ctx context.Context, readinessURL string) bool {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, readinessURL, nil)
+	if err != nil {
+		return false
+	}
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+
+	return resp.StatusCode == http.StatusOK
+}
+
+// Start starts the process of the node, if it is not running. The process
+// keeps running after beekeeper exits and writes its output to the log file
+// in the node's directory.
+func (n *NodeOrchestrator) Start(ctx context.Context, name string, namespace string) (err error) {
+	dir := n.nodeDir(name, namespace)
+
+	if _, err := readState(dir); err != nil {
+		return fmt.Errorf("node %s: %w", name, err)
+	}
+
+	if running(dir) {
+		n.log.Infof("node %s is already running", name)
+		return nil
+	}
+
+	logOutput, err := os.OpenFile(filepath.Join(dir, logFile), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("open log file of node %s: %w", name, err)
+	}
+	defer logOutput.Close()
+
+	cmd := exec.Command(n.beePath, "start", "--config", filepath.Join(dir, configFile))
+	cmd.Dir = dir
+	cmd.Stdout = logOutput
+	cmd.Stderr = logOutput
+	cmd.SysProcAttr = sysProcAttr()
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("start process of node %s: %w", name, err)
+	}
+
+	start, err := processStart(cmd.Process.Pid)
+	if err != nil {
+		_ = cmd.Process.Kill()
+		return fmt.Errorf("start time of process of node %s: %w", name, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, pidFile), []byte(strconv.Itoa(cmd.Process.Pid)+"\n"+start+"\n"), 0o600); err != nil {
+		_ = cmd.Process.Kill()
+		return fmt.Errorf("write process ID of node %s: %w", name, err)
+	}
+
+	// reap the process if it exits while beekeeper is running
+	go func() {
+		_ = cmd.Wait()
+	}()
+
+	n.log.Infof("node %s is started with process ID %d", name, cmd.Process.Pid)
+
+	return nil
+}
+
+// Stop terminates the process of the node and waits for it to exit. The
+// process is killed if it does not exit in time.
+func (n *NodeOrchestrator) Stop(ctx context.Context, name string, namespace string) (err error) {
+	dir := n.nodeDir(name, namespace)
+
+	pid, ok := nodeProcess(dir)
+	if !ok {
+		return removePID(dir)
+	}
+
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return fmt.Errorf("find process of node %s: %w", name, err)
+	}
+
+	if err := terminate(process); err != nil {
+		return fmt.Errorf("terminate process of node %s: %w", name, err)
+	}
+
+	deadline := time.Now().Add(stopTimeout)
+	for alive(pid) {
+		if time.Now().After(deadline) {
+			n.log.Warningf("node %s did not stop in %s, killing it", name, stopTimeout)
+			if err := process.Kill(); err != nil && !errors.Is(err, os.ErrProcessDone) {
+				return fmt.Errorf("kill process of node %s: %w", name, err)
+			}
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+
+	n.log.Infof("node %s is stopped", name)
+
+	return removePID(dir)
+}
+
+// RunningNodes implements orchestration.NodeOrchestrator.
+func (n *NodeOrchestrator) RunningNodes(ctx context.Context, namespace string) (running []string, err error) {
+	return n.nodes(namespace, true)
+}
+
+// StoppedNodes implements orchestration.NodeOrchestrator.
+func (n *NodeOrchestrator) StoppedNodes(ctx context.Context, namespace string) (stopped []string, err error) {
+	return n.nodes(namespace, false)
+}
+
+// nodes returns names of created nodes in the namespace that are running or
+// stopped
+func (n *NodeOrchestrator) nodes(namespace string, isRunning bool) (names []string, err error) {
+	entries, err := os.ReadDir(filepath.Join(n.dir, namespace))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("list nodes in namespace %s: %w", namespace, err)
+	}
+
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		dir := filepath.Join(n.dir, namespace, e.Name())
+		if _, err := readState(dir); err != nil {
+			continue
+		}
+		if running(dir) == isRunning {
+			names = append(names, e.Name())
+		}
+	}
+
+	return names, nil
+}
+
+func (n *NodeOrchestrator) nodeDir(name, namespace string) string {
+	return filepath.Join(n.dir, namespace, name)
+}
+
+// ports returns ports of the node in the directory. Ports of created nodes
+// are read from their state, other nodes are assigned the first pair of
+// ports that is not used by any node.
+func (n *NodeOrchestrator) ports(dir string) (ports, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if p, err := readState(dir); err == nil {
+		return p, nil
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return ports{}, err
+	}
+
+	if p, ok := n.allocated[dir]; ok {
+		return p, nil
+	}
+
+	var used []int
+	states, err := filepath.Glob(filepath.Join(n.dir, "*", "*", stateFile))
+	if err != nil {
+		return ports{}, err
+	}
+	for _, s := range states {
+		if p, err := readState(filepath.Dir(s)); err == nil {
+			used = append(used, p.API, p.P2P)
+		}
+	}
+	for _, p := range n.allocated {
+		used = append(used, p.API, p.P2P)
+	}
+
+	p := ports{API: n.basePort, P2P: n.basePort + 1}
+	for slices.Contains(used, p.API) || slices.Contains(used, p.P2P) {
+		p.API += 2
+		p.P2P += 2
+	}
+	n.allocated[dir] = p
+
+	return p, nil
+}
+
+// localBootnodes replaces addresses of bootnode services with local
+// addresses of created nodes with the same name
+func (n *NodeOrchestrator) localBootnodes(bootnodes, namespace string) string {
+	return bootnodeAddrRe.ReplaceAllStringFunc(bootnodes, func(addr string) string {
+		name := strings.TrimSuffix(bootnodeAddrRe.FindStringSubmatch(addr)[1], "-headless")
+		p, err := readState(n.nodeDir(name, namespace))
+		if err != nil {
+			return addr
+		}
+		return fmt.Sprintf("/ip4/%s/tcp/%d", host, p.P2P)
+	})
+}
+
+// readState returns ports of the created node in the directory
+func readState(dir string) (p ports, err error) {
+	b, err := os.ReadFile(filepath.Join(dir, stateFile))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return ports{}, fmt.Errorf("not created: %w", err)
+		}
+		return ports{}, err
+	}
+	if err := json.Unmarshal(b, &p); err != nil {
+		return ports{}, fmt.Errorf("decode %s: %w", stateFile, err)
+	}
+	return p, nil
+}
+
+// running returns whether the process of the node in the directory is running
+func running(dir string) bool {
+	_, ok := nodeProcess(dir)
+	return ok
+}
+
+// nodeProcess returns the ID of the running process of the node in the
+// directory. The start time of the process is saved next to its ID, so that
+// a process that reused the ID of an exited node is not taken for the node.
+func nodeProcess(dir string) (int, bool) {
+	pid, start, ok := readPID(dir)
+	if !ok || !alive(pid) {
+		return 0, false
+	}
+	s, err := processStart(pid)
+	if err != nil || s != start {
+		return 0, false
+	}
+	return pid, true
+}
+
+// readPID returns the process ID and the start time of the process from the
+// process ID file in the directory
+func readPID(dir string) (pid int, start string, ok bool) {
+	b, err := os.ReadFile(filepath.Join(dir, pidFile))
+	if err != nil {
+		return 0, "", false
+	}
+	id, start, found := strings.Cut(strings.TrimSpace(string(b)), "\n")
+	if !found {
+		return 0, "", false
+	}
+	pid, err = strconv.Atoi(id)
+	if err != nil {
+		return 0, "", false
+	}
+	return pid, strings.TrimSpace(start), true
+}
+
+func removePID(dir string) error {
+	if err := os.Remove(filepath.Join(dir, pidFile)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("remove process ID file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,288 @@
+package local
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/beekeeper/pkg/logging"
+	"github.com/ethersphere/beekeeper/pkg/orchestration"
+)
+
+// fakeBeeEnv makes the test binary act as the bee binary
+const fakeBeeEnv = "BEEKEEPER_LOCAL_FAKE_BEE"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(fakeBeeEnv) == "1" {
+		fakeBee()
+		return
+	}
+
+	readyPollInterval = 10 * time.Millisecond
+	stopTimeout = 5 * time.Second
+
+	os.Exit(m.Run())
+}
+
+// fakeBee serves the readiness endpoint on the API address of the
+// configuration file passed with the start command, until it is terminated.
+func fakeBee() {
+	if len(os.Args) != 4 || os.Args[1] != "start" || os.Args[2] != "--config" {
+		os.Exit(2)
+	}
+
+	config, err := os.ReadFile(os.Args[3])
+	if err != nil {
+		os.Exit(3)
+	}
+
+	var apiAddr string
+	for line := range strings.Lines(string(config)) {
+		if v, ok := strings.CutPrefix(line, "api-addr: "); ok {
+			apiAddr = strings.TrimSpace(v)
+		}
+	}
+
+	l, err := net.Listen("tcp", apiAddr)
+	if err != nil {
+		os.Exit(4)
+	}
+
+	go func() {
+		_ = http.Serve(l, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/readiness" {
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+			w.WriteHeader(http.StatusNotFound)
+		}))
+	}()
+
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGTERM, os.Interrupt)
+	<-ch
+	os.Exit(0)
+}
+
+func newTestOrchestrator(t *testing.T) *NodeOrchestrator {
+	t.Helper()
+
+	beePath, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(fakeBeeEnv, "1")
+
+	return NewNodeOrchestrator(Options{
+		Dir:      t.TempDir(),
+		BeePath:  beePath,
+		BasePort: freePort(t),
+	}, logging.New(io.Discard, 0))
+}
+
+// freePort returns a port that is free together with the following ports
+// used by two nodes
+func freePort(t *testing.T) int {
+	t.Helper()
+
+	for range 100 {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		port := l.Addr().(*net.TCPAddr).Port
+		l.Close()
+
+		free := true
+		for p := port + 1; p < port+4; p++ {
+			l, err := net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(p)))
+			if err != nil {
+				free = false
+				break
+			}
+			l.Close()
+		}
+		if free {
+			return port
+		}
+	}
+
+	t.Fatal("no free ports")
+	return 0
+}
+
+func TestCreate(t *testing.T) {
+	ctx := context.Background()
+	o := newTestOrchestrator(t)
+
+	bootnodeURL, err := o.APIURL("bootnode-0", "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	beeURL, err := o.APIURL("bee-0", "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bootnodeURL.String() == beeURL.String() {
+		t.Fatalf("nodes have the same API URL %s", beeURL)
+	}
+
+	key, err := orchestration.NewEncryptedKey("password")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := o.Create(ctx, orchestration.CreateOptions{
+		Name:      "bootnode-0",
+		Namespace: "test",
+		Config:    orchestration.Config{APIAddr: ":1633", P2PAddr: ":1634", DataDir: "/home/bee/.bee", BootnodeMode: true},
+		LibP2PKey: `{"address":"libp2p"}`,
+		SwarmKey:  key,
+	}); err != nil {
+		t.Fatalf("create bootnode: %v", err)
+	}
+	if err := o.Create(ctx, orchestration.CreateOptions{
+		Name:      "bee-0",
+		Namespace: "test",
+		Config: orchestration.Config{
+			APIAddr:   ":1633",
+			P2PAddr:   ":1634",
+			Bootnodes: "/dns4/bootnode-0-headless.test.svc.cluster.local/tcp/1634/p2p/QmaHzvd3iZduu275CMkMVZKwbsjXSyH3GJRj4UvFJApKcb",
+		},
+	}); err != nil {
+		t.Fatalf("create node: %v", err)
+	}
+
+	// ports are kept after nodes are created
+	if u, err := NewNodeOrchestrator(Options{Dir: o.dir}, logging.New(io.Discard, 0)).APIURL("bee-0", "test"); err != nil || u.String() != beeURL.String() {
+		t.Fatalf("got API URL %v (%v), want %s", u, err, beeURL)
+	}
+
+	bootnodePorts, err := readState(o.nodeDir("bootnode-0", "test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := os.ReadFile(filepath.Join(o.nodeDir("bee-0", "test"), configFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"api-addr: " + beeURL.Host + "\n",
+		"data-dir: " + o.nodeDir("bee-0", "test") + "\n",
+		"bootnode: /ip4/127.0.0.1/tcp/" + strconv.Itoa(bootnodePorts.P2P) + "/p2p/QmaHzvd3iZduu275CMkMVZKwbsjXSyH3GJRj4UvFJApKcb\n",
+		"allow-private-cidrs: true\n",
+	} {
+		if !strings.Contains(string(config), want) {
+			t.Errorf("configuration does not contain %q:\n%s", want, config)
+		}
+	}
+
+	libp2pKey, err := os.ReadFile(filepath.Join(o.nodeDir("bootnode-0", "test"), keysDir, "libp2p_v2.key"))
+	if err != nil || string(libp2pKey) != `{"address":"libp2p"}` {
+		t.Errorf("got libp2p key %q (%v)", libp2pKey, err)
+	}
+	if _, err := os.Stat(filepath.Join(o.nodeDir("bootnode-0", "test"), keysDir, "swarm.key")); err != nil {
+		t.Errorf("swarm key: %v", err)
+	}
+}
+
+func TestStartStop(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	o := newTestOrchestrator(t)
+
+	for _, name := range []string{"bee-0", "bee-1"} {
+		if err := o.Create(ctx, orchestration.CreateOptions{Name: name, Namespace: "test"}); err != nil {
+			t.Fatalf("create %s: %v", name, err)
+		}
+	}
+
+	if err := o.Start(ctx, "bee-0", "test"); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = o.Stop(context.Background(), "bee-0", "test")
+	})
+
+	ready, err := o.Ready(ctx, "bee-0", "test")
+	if err != nil || !ready {
+		t.Fatalf("got ready %v (%v), want true", ready, err)
+	}
+
+	assertNodes(t, o, []string{"bee-0"}, []string{"bee-1"})
+
+	if err := o.Stop(ctx, "bee-0", "test"); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+
+	assertNodes(t, o, nil, []string{"bee-0", "bee-1"})
+
+	if _, err := o.Ready(ctx, "bee-0", "test"); err == nil {
+		t.Fatal("stopped node is ready")
+	}
+
+	if err := o.Delete(ctx, "bee-0", "test"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := os.Stat(o.nodeDir("bee-0", "test")); !os.IsNotExist(err) {
+		t.Fatalf("node directory is not removed: %v", err)
+	}
+
+	assertNodes(t, o, nil, []string{"bee-1"})
+}
+
+func assertNodes(t *testing.T, o *NodeOrchestrator, wantRunning, wantStopped []string) {
+	t.Helper()
+
+	running, err := o.RunningNodes(context.Background(), "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(running, wantRunning) {
+		t.Errorf("got running nodes %v, want %v", running, wantRunning)
+	}
+
+	stopped, err := o.StoppedNodes(context.Background(), "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(stopped, wantStopped) {
+		t.Errorf("got stopped nodes %v, want %v", stopped, wantStopped)
+	}
+}
+
+func TestStopStalePID(t *testing.T) {
+	ctx := context.Background()
+
+	o := newTestOrchestrator(t)
+	if err := o.Create(ctx, orchestration.CreateOptions{Name: "bee-0", Namespace: "test"}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	// the process ID of an exited node reused by another process, this one
+	dir := o.nodeDir("bee-0", "test")
+	if err := os.WriteFile(filepath.Join(dir, pidFile), []byte(strconv.Itoa(os.Getpid())+"\n0\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	assertNodes(t, o, nil, []string{"bee-0"})
+
+	if err := o.Stop(ctx, "bee-0", "test"); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, pidFile)); !os.IsNotExist(err) {
+		t.Fatalf("process ID file is not removed: %v", err)
+	}
+}
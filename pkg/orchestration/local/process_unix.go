@@ -0,0 +1,28 @@
+//go:build !windows
+
+package local
+
+import (
+	"os"
+	"syscall"
+)
+
+// sysProcAttr starts the process in a new session, so that it is not
+// terminated together with beekeeper
+func sysProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{Setsid: true}
+}
+
+// alive returns whether the process with the ID exists
+func alive(pid int) bool {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	return p.Signal(syscall.Signal(0)) == nil
+}
+
+// terminate asks the process to shut down gracefully
+func terminate(p *os.Process) error {
+	return p.Signal(syscall.SIGTERM)
+}
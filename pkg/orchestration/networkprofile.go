@@ -0,0 +1,71 @@
+package orchestration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNetworkProfileNotEnabled is returned when the network profile of a node
+// is changed, but the node was created without one
+var ErrNetworkProfileNotEnabled = errors.New("node was created without network profile")
+
+// rateRe matches tc rates, e.g. 512kbit, 10mbit or 1.5mbps
+var rateRe = regexp.MustCompile(`^\d+(\.\d+)?([kmgt]i?)?(bit|bps)$`)
+
+// NetworkProfile represents shaping of outgoing network traffic of nodes. The
+// zero value does not shape the traffic.
+type NetworkProfile struct {
+	Latency time.Duration // delay of packets
+	Jitter  time.Duration // random variation of the delay
+	Loss    float64       // percentage of dropped packets
+	Rate    string        // bandwidth limit in tc units, e.g. 10mbit
+}
+
+// NetemArgs returns arguments of the tc netem queueing discipline that apply
+// the profile, or an empty string if the profile does not shape the traffic
+func (p NetworkProfile) NetemArgs() (string, error) {
+	if p.Latency < 0 || p.Jitter < 0 {
+		return "", errors.New("network profile latency and jitter must not be negative")
+	}
+	if p.Jitter > 0 && p.Latency == 0 {
+		return "", errors.New("network profile jitter requires latency")
+	}
+	if p.Loss < 0 || p.Loss > 100 {
+		return "", fmt.Errorf("network profile loss %v is not a percentage", p.Loss)
+	}
+	if p.Rate != "" && !rateRe.MatchString(strings.ToLower(p.Rate)) {
+		return "", fmt.Errorf("invalid network profile rate %s", p.Rate)
+	}
+
+	var args []string
+	if p.Latency > 0 {
+		args = append(args, "delay", netemTime(p.Latency))
+		if p.Jitter > 0 {
+			args = append(args, netemTime(p.Jitter))
+		}
+	}
+	if p.Loss > 0 {
+		args = append(args, "loss", strconv.FormatFloat(p.Loss, 'f', -1, 64)+"%")
+	}
+	if p.Rate != "" {
+		args = append(args, "rate", strings.ToLower(p.Rate))
+	}
+
+	return strings.Join(args, " "), nil
+}
+
+// netemTime formats the duration in milliseconds, as accepted by tc
+func netemTime(d time.Duration) string {
+	return strconv.FormatFloat(float64(d)/float64(time.Millisecond), 'f', -1, 64) + "ms"
+}
+
+// NetworkShaper is implemented by node orchestrators that can change network
+// profiles of running nodes
+type NetworkShaper interface {
+	SetNetworkProfile(ctx context.Context, name string, namespace string, p NetworkProfile) (err error)
+}
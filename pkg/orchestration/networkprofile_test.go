@@ -0,0 +1,54 @@
+package orchestration_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethersphere/beekeeper/pkg/orchestration"
+)
+
+func TestNetworkProfileNetemArgs(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		profile orchestration.NetworkProfile
+		want    string
+		wantErr bool
+	}{
+		{name: "empty"},
+		{
+			name:    "latency with jitter",
+			profile: orchestration.NetworkProfile{Latency: 250 * time.Millisecond, Jitter: 1500 * time.Microsecond},
+			want:    "delay 250ms 1.5ms",
+		},
+		{
+			name:    "all",
+			profile: orchestration.NetworkProfile{Latency: time.Second, Jitter: 50 * time.Millisecond, Loss: 0.5, Rate: "1Mbit"},
+			want:    "delay 1000ms 50ms loss 0.5% rate 1mbit",
+		},
+		{
+			name:    "rate",
+			profile: orchestration.NetworkProfile{Rate: "512kbps"},
+			want:    "rate 512kbps",
+		},
+		{name: "jitter without latency", profile: orchestration.NetworkProfile{Jitter: time.Millisecond}, wantErr: true},
+		{name: "negative latency", profile: orchestration.NetworkProfile{Latency: -time.Millisecond}, wantErr: true},
+		{name: "loss above 100", profile: orchestration.NetworkProfile{Loss: 101}, wantErr: true},
+		{name: "invalid rate", profile: orchestration.NetworkProfile{Rate: "10 mbit"}, wantErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.profile.NetemArgs()
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tc.want {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -1,8 +1,11 @@
 package orchestration
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"html/template"
+	"net/url"
 	"time"
 
 	"github.com/ethersphere/beekeeper/pkg/bee"
@@ -34,6 +37,13 @@ type NodeOrchestrator interface {
 	StoppedNodes(ctx context.Context, namespace string) (stopped []string, err error)
 }
 
+// APIURLProvider is implemented by node orchestrators that expose APIs of
+// nodes on their own addresses, instead of addresses derived from the API
+// domain of the cluster
+type APIURLProvider interface {
+	APIURL(name string, namespace string) (*url.URL, error)
+}
+
 // NodeOptions holds optional parameters for the Node.
 type NodeOptions struct {
 	Config    *Config
@@ -57,6 +67,7 @@ type CreateOptions struct {
 	IngressClass              string
 	IngressHost               string
 	LibP2PKey                 string
+	NetworkProfile            *NetworkProfile
 	NodeSelector              map[string]string
 	P2PWSSNodePort            int32
 	PersistenceEnabled        bool
@@ -123,3 +134,63 @@ type Config struct {
 	WelcomeMessage              string        // send a welcome message string during handshakes
 	WithdrawAddress             string        // allowed addresses for wallet withdrawal
 }
+
+// configTemplate is the template of the Bee configuration file
+const configTemplate = `
+allow-private-cidrs: {{ .AllowPrivateCIDRs }}
+api-addr: {{.APIAddr}}
+autotls-ca-endpoint: {{.AutoTLSCAEndpoint}}
+autotls-domain: {{.AutoTLSDomain}}
+autotls-registration-endpoint: {{.AutoTLSRegistrationEndpoint}}
+block-time: {{ .BlockTime }}
+blockchain-rpc-endpoint: {{.BlockchainRPCEndpoint}}
+bootnode-mode: {{.BootnodeMode}}
+bootnode: {{.Bootnodes}}
+cache-capacity: {{.CacheCapacity}}
+chequebook-enable: {{.ChequebookEnable}}
+cors-allowed-origins: {{.CORSAllowedOrigins}}
+data-dir: {{.DataDir}}
+db-block-cache-capacity: {{.DbBlockCacheCapacity}}
+db-disable-seeks-compaction: {{.DbDisableSeeksCompaction}}
+db-open-files-limit: {{.DbOpenFilesLimit}}
+db-write-buffer-size: {{.DbWriteBufferSize}}
+full-node: {{.FullNode}}
+mainnet: {{.Mainnet}}
+nat-addr: {{.NATAddr}}
+nat-wss-addr: {{.NATWSSAddr}}
+network-id: {{.NetworkID}}
+p2p-addr: {{.P2PAddr}}
+p2p-ws-enable: {{.P2PWSEnable}}
+p2p-wss-addr: {{.P2PWSSAddr}}
+p2p-wss-enable: {{.P2PWSSEnable}}
+password: {{.Password}}
+payment-early-percent: {{.PaymentEarly}}
+payment-threshold: {{.PaymentThreshold}}
+payment-tolerance-percent: {{.PaymentTolerance}}
+postage-stamp-address: {{ .PostageStampAddress }}
+postage-stamp-start-block: {{ .PostageContractStartBlock }}
+price-oracle-address: {{ .PriceOracleAddress }}
+redistribution-address: {{ .RedistributionAddress }}
+resolver-options: {{.ResolverOptions}}
+staking-address: {{ .StakingAddress }}
+storage-incentives-enable: {{ .StorageIncentivesEnable }}
+swap-enable: {{.SwapEnable}}
+swap-factory-address: {{.SwapFactoryAddress}}
+swap-initial-deposit: {{.SwapInitialDeposit}}
+tracing-enable: {{.TracingEnabled}}
+tracing-endpoint: {{.TracingEndpoint}}
+tracing-service-name: {{.TracingServiceName}}
+verbosity: {{.Verbosity}}
+warmup-time: {{.WarmupTime}}
+welcome-message: {{.WelcomeMessage}}
+withdrawal-addresses-whitelist: {{.WithdrawAddress}}
+`
+
+// YAML returns the Bee configuration file with the configuration
+func (c Config) YAML() (string, error) {
+	var b bytes.Buffer
+	if err := template.Must(template.New("").Parse(configTemplate)).Execute(&b, c); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
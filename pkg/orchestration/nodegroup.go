@@ -24,10 +24,12 @@ type NodeGroup interface {
 	Overlays(ctx context.Context) (overlays NodeGroupOverlays, err error)
 	Peers(ctx context.Context) (peers NodeGroupPeers, err error)
 	RunningNodes(ctx context.Context) (running []string, err error)
+	SetNetworkProfile(ctx context.Context, p NetworkProfile) (err error)
 	Settlements(ctx context.Context) (settlements NodeGroupSettlements, err error)
 	Size() int
 	StoppedNodes(ctx context.Context) (stopped []string, err error)
 	Topologies(ctx context.Context) (topologies NodeGroupTopologies, err error)
+	UpdateNode(ctx context.Context, name string, inCluster bool, o NodeOptions) (err error)
 }
 
 // NodeGroupOptions represents node group options
@@ -40,6 +42,7 @@ type NodeGroupOptions struct {
 	IngressAnnotations        map[string]string
 	IngressClass              string
 	Labels                    map[string]string
+	NetworkProfile            *NetworkProfile
 	NodeSelector              map[string]string
 	P2PWSSNodePort            int32
 	PersistenceEnabled        bool
@@ -0,0 +1,331 @@
+package report
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// Format represents the output format of a report
+type Format string
+
+const (
+	FormatJUnit Format = "junit"
+	FormatJSON  Format = "json"
+)
+
+// ParseFormat returns the Format for the given name
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(s); f {
+	case FormatJUnit, FormatJSON:
+		return f, nil
+	default:
+		return "", fmt.Errorf("unsupported report format %q: must be '%s' or '%s'", s, FormatJUnit, FormatJSON)
+	}
+}
+
+// Status represents the outcome of a single check or simulation
+type Status string
+
+const (
+	StatusPassed  Status = "passed"
+	StatusFlaky   Status = "flaky" // passed only after a retry
+	StatusFailed  Status = "failed"
+	StatusSkipped Status = "skipped"
+)
+
+// Result represents the outcome of a single check or simulation
+type Result struct {
+	Name      string
+	Type      string
+	Status    Status
+	Attempts  int
+	Duration  time.Duration
+	Error     error
+	Options   any
+	Seed      *int64
+	Artifact  string // path of the diagnostics bundle of a failure
+	Timestamp time.Time
+}
+
+// Report represents the results of a beekeeper run
+type Report struct {
+	Name      string // name of the command, e.g. check or simulate
+	Cluster   string
+	Seed      int64 // global seed, -1 if random
+	Timestamp time.Time
+	Duration  time.Duration
+	Results   []Result
+}
+
+// Counts returns the number of failed, skipped and flaky results
+func (r *Report) Counts() (failed, skipped, flaky int) {
+	for _, res := range r.Results {
+		switch res.Status {
+		case StatusFailed:
+			failed++
+		case StatusSkipped:
+			skipped++
+		case StatusFlaky:
+			flaky++
+		}
+	}
+	return failed, skipped, flaky
+}
+
+// Write writes the report to w in the given format
+func (r *Report) Write(w io.Writer, f Format) error {
+	switch f {
+	case FormatJUnit:
+		return r.writeJUnit(w)
+	case FormatJSON:
+		return r.writeJSON(w)
+	default:
+		return fmt.Errorf("unsupported report format %q", f)
+	}
+}
+
+// WriteFile writes the report to the file at path in the given format,
+// creating parent directories if needed.
+func (r *Report) WriteFile(path string, f Format) (err error) {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("creating report directory: %w", err)
+		}
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("creating report file: %w", err)
+	}
+	defer func() {
+		if cerr := file.Close(); cerr != nil && err == nil {
+			err = fmt.Errorf("closing report file: %w", cerr)
+		}
+	}()
+
+	return r.Write(file, f)
+}
+
+// SeedFromOptions returns the value of the Seed field of the options struct,
+// if it has one.
+func SeedFromOptions(o any) *int64 {
+	v := reflect.Indirect(reflect.ValueOf(o))
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+	f := v.FieldByName("Seed")
+	if !f.IsValid() || f.Kind() != reflect.Int64 {
+		return nil
+	}
+	seed := f.Int()
+	return &seed
+}
+
+type jsonResult struct {
+	Name      string          `json:"name"`
+	Type      string          `json:"type,omitempty"`
+	Status    Status          `json:"status"`
+	Attempts  int             `json:"attempts,omitempty"`
+	Duration  float64         `json:"duration_seconds"`
+	Error     string          `json:"error,omitempty"`
+	Options   json.RawMessage `json:"options,omitempty"`
+	Seed      *int64          `json:"seed,omitempty"`
+	Artifact  string          `json:"artifact,omitempty"`
+	Timestamp time.Time       `json:"timestamp"`
+}
+
+type jsonReport struct {
+	Name      string       `json:"name"`
+	Cluster   string       `json:"cluster"`
+	Seed      int64        `json:"seed"`
+	Timestamp time.Time    `json:"timestamp"`
+	Duration  float64      `json:"duration_seconds"`
+	Total     int          `json:"total"`
+	Failed    int          `json:"failed"`
+	Skipped   int          `json:"skipped"`
+	Flaky     int          `json:"flaky"`
+	Results   []jsonResult `json:"results"`
+}
+
+func (r *Report) writeJSON(w io.Writer) error {
+	failed, skipped, flaky := r.Counts()
+	jr := jsonReport{
+		Name:      r.Name,
+		Cluster:   r.Cluster,
+		Seed:      r.Seed,
+		Timestamp: r.Timestamp,
+		Duration:  r.Duration.Seconds(),
+		Total:     len(r.Results),
+		Failed:    failed,
+		Skipped:   skipped,
+		Flaky:     flaky,
+		Results:   make([]jsonResult, 0, len(r.Results)),
+	}
+
+	for _, res := range r.Results {
+		jr.Results = append(jr.Results, jsonResult{
+			Name:      res.Name,
+			Type:      res.Type,
+			Status:    res.Status,
+			Attempts:  res.Attempts,
+			Duration:  res.Duration.Seconds(),
+			Error:     errorString(res.Error),
+			Options:   encodeOptions(res.Options),
+			Seed:      res.Seed,
+			Artifact:  res.Artifact,
+			Timestamp: res.Timestamp,
+		})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(jr)
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name       string          `xml:"name,attr"`
+	Tests      int             `xml:"tests,attr"`
+	Failures   int             `xml:"failures,attr"`
+	Errors     int             `xml:"errors,attr"`
+	Skipped    int             `xml:"skipped,attr"`
+	Time       string          `xml:"time,attr"`
+	Timestamp  string          `xml:"timestamp,attr"`
+	Properties []junitProperty `xml:"properties>property,omitempty"`
+	TestCases  []junitTestCase `xml:"testcase"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitTestCase struct {
+	Name       string          `xml:"name,attr"`
+	Classname  string          `xml:"classname,attr"`
+	Time       string          `xml:"time,attr"`
+	Timestamp  string          `xml:"timestamp,attr,omitempty"`
+	Properties []junitProperty `xml:"properties>property,omitempty"`
+	Failure    *junitMessage   `xml:"failure,omitempty"`
+	Skipped    *junitMessage   `xml:"skipped,omitempty"`
+	SystemOut  string          `xml:"system-out,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Content string `xml:",chardata"`
+}
+
+func (r *Report) writeJUnit(w io.Writer) error {
+	failed, skipped, _ := r.Counts()
+	suite := junitTestSuite{
+		Name:      "beekeeper " + r.Name,
+		Tests:     len(r.Results),
+		Failures:  failed,
+		Skipped:   skipped,
+		Time:      formatSeconds(r.Duration),
+		Timestamp: r.Timestamp.Format(time.RFC3339),
+		Properties: []junitProperty{
+			{Name: "cluster", Value: r.Cluster},
+			{Name: "seed", Value: strconv.FormatInt(r.Seed, 10)},
+		},
+	}
+
+	for _, res := range r.Results {
+		tc := junitTestCase{
+			Name:      res.Name,
+			Classname: "beekeeper." + r.Name + "." + res.Type,
+			Time:      formatSeconds(res.Duration),
+			Timestamp: res.Timestamp.Format(time.RFC3339),
+		}
+
+		tc.Properties = append(tc.Properties, junitProperty{Name: "status", Value: string(res.Status)})
+
+		if res.Attempts > 0 {
+			tc.Properties = append(tc.Properties, junitProperty{Name: "attempts", Value: strconv.Itoa(res.Attempts)})
+		}
+
+		if res.Seed != nil {
+			tc.Properties = append(tc.Properties, junitProperty{Name: "seed", Value: strconv.FormatInt(*res.Seed, 10)})
+		}
+
+		if res.Artifact != "" {
+			tc.Properties = append(tc.Properties, junitProperty{Name: "artifact", Value: res.Artifact})
+		}
+
+		if opts := encodeOptions(res.Options); opts != nil {
+			tc.Properties = append(tc.Properties, junitProperty{Name: "options", Value: string(opts)})
+			tc.SystemOut = string(opts)
+		}
+
+		switch res.Status {
+		case StatusFailed:
+			tc.Failure = &junitMessage{Message: errorString(res.Error), Type: "failure", Content: errorString(res.Error)}
+		case StatusSkipped:
+			tc.Skipped = &junitMessage{Message: errorString(res.Error)}
+		}
+
+		suite.TestCases = append(suite.TestCases, tc)
+	}
+
+	suites := junitTestSuites{
+		Name:     "beekeeper",
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Skipped:  suite.Skipped,
+		Time:     suite.Time,
+		Suites:   []junitTestSuite{suite},
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(suites); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// encodeOptions returns JSON encoded options, falling back to their string
+// representation if they can not be encoded.
+func encodeOptions(o any) json.RawMessage {
+	if o == nil {
+		return nil
+	}
+	b, err := json.Marshal(o)
+	if err != nil {
+		b, _ = json.Marshal(fmt.Sprintf("%+v", o))
+	}
+	return b
+}
+
+func formatSeconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', 3, 64)
+}
+
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
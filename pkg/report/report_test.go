@@ -0,0 +1,157 @@
+package report_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/beekeeper/pkg/report"
+)
+
+type options struct {
+	ChunksPerNode int
+	Seed          int64
+}
+
+func newReport() *report.Report {
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	return &report.Report{
+		Name:      "check",
+		Cluster:   "local",
+		Seed:      42,
+		Timestamp: ts,
+		Duration:  3 * time.Second,
+		Results: []report.Result{
+			{
+				Name:      "ci-pingpong",
+				Type:      "pingpong",
+				Status:    report.StatusPassed,
+				Duration:  time.Second,
+				Options:   options{ChunksPerNode: 1, Seed: 42},
+				Seed:      report.SeedFromOptions(options{Seed: 42}),
+				Timestamp: ts,
+			},
+			{
+				Name:      "ci-pushsync",
+				Type:      "pushsync",
+				Status:    report.StatusFailed,
+				Duration:  2 * time.Second,
+				Error:     errors.New("exceeded number of retries"),
+				Timestamp: ts,
+			},
+			{
+				Name:      "ci-retrieval",
+				Type:      "retrieval",
+				Status:    report.StatusSkipped,
+				Error:     errors.New("dependency 'ci-pushsync' did not succeed"),
+				Timestamp: ts,
+			},
+		},
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for _, s := range []string{"junit", "json"} {
+		if _, err := report.ParseFormat(s); err != nil {
+			t.Fatalf("%s: unexpected error %v", s, err)
+		}
+	}
+	if _, err := report.ParseFormat("html"); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestSeedFromOptions(t *testing.T) {
+	if seed := report.SeedFromOptions(options{Seed: 7}); seed == nil || *seed != 7 {
+		t.Fatalf("got %v, want 7", seed)
+	}
+	if seed := report.SeedFromOptions(&options{Seed: 7}); seed == nil || *seed != 7 {
+		t.Fatalf("got %v, want 7", seed)
+	}
+	if seed := report.SeedFromOptions(struct{ Seed string }{}); seed != nil {
+		t.Fatalf("got %v, want nil", *seed)
+	}
+	if seed := report.SeedFromOptions(nil); seed != nil {
+		t.Fatalf("got %v, want nil", *seed)
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := newReport().Write(&buf, report.FormatJSON); err != nil {
+		t.Fatal(err)
+	}
+
+	var got struct {
+		Cluster string `json:"cluster"`
+		Total   int    `json:"total"`
+		Failed  int    `json:"failed"`
+		Skipped int    `json:"skipped"`
+		Results []struct {
+			Name     string          `json:"name"`
+			Status   string          `json:"status"`
+			Duration float64         `json:"duration_seconds"`
+			Error    string          `json:"error"`
+			Options  json.RawMessage `json:"options"`
+			Seed     *int64          `json:"seed"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Cluster != "local" || got.Total != 3 || got.Failed != 1 || got.Skipped != 1 {
+		t.Fatalf("unexpected summary: %+v", got)
+	}
+	if r := got.Results[0]; r.Status != "passed" || r.Duration != 1 || r.Seed == nil || *r.Seed != 42 || !strings.Contains(string(r.Options), `"ChunksPerNode"`) {
+		t.Fatalf("unexpected result: %+v", r)
+	}
+	if r := got.Results[1]; r.Status != "failed" || r.Error != "exceeded number of retries" {
+		t.Fatalf("unexpected result: %+v", r)
+	}
+}
+
+func TestWriteJUnit(t *testing.T) {
+	var buf bytes.Buffer
+	if err := newReport().Write(&buf, report.FormatJUnit); err != nil {
+		t.Fatal(err)
+	}
+
+	var got struct {
+		Tests    int `xml:"tests,attr"`
+		Failures int `xml:"failures,attr"`
+		Skipped  int `xml:"skipped,attr"`
+		Suites   []struct {
+			TestCases []struct {
+				Name    string `xml:"name,attr"`
+				Time    string `xml:"time,attr"`
+				Failure *struct {
+					Message string `xml:"message,attr"`
+				} `xml:"failure"`
+				Skipped *struct{} `xml:"skipped"`
+			} `xml:"testcase"`
+		} `xml:"testsuite"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Tests != 3 || got.Failures != 1 || got.Skipped != 1 {
+		t.Fatalf("unexpected summary: %+v", got)
+	}
+
+	cases := got.Suites[0].TestCases
+	if cases[0].Name != "ci-pingpong" || cases[0].Time != "1.000" || cases[0].Failure != nil {
+		t.Fatalf("unexpected test case: %+v", cases[0])
+	}
+	if cases[1].Failure == nil || cases[1].Failure.Message != "exceeded number of retries" {
+		t.Fatalf("unexpected test case: %+v", cases[1])
+	}
+	if cases[2].Skipped == nil {
+		t.Fatalf("unexpected test case: %+v", cases[2])
+	}
+}
@@ -0,0 +1,353 @@
+package restart
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethersphere/beekeeper/pkg/k8s/statefulset"
+	"github.com/ethersphere/beekeeper/pkg/node"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	// DefaultGroupLabel is the pod label with the node group of nodes found in a namespace
+	DefaultGroupLabel = "app.kubernetes.io/component"
+
+	defaultNodeGroup    = "default"
+	defaultGateTimeout  = 10 * time.Minute
+	defaultPollInterval = 5 * time.Second
+)
+
+// ErrGateFailed is returned when upgraded nodes do not pass the health gates
+var ErrGateFailed = errors.New("upgrade gate failed")
+
+// UpgradeOptions represents options of the health-gated rolling upgrade
+type UpgradeOptions struct {
+	Image           string
+	ByNodeGroup     bool          // upgrade one node group per batch
+	BatchPercentage int           // percentage of nodes upgraded per batch, when not upgraded by node group
+	GroupLabel      string        // pod label with the node group of nodes found in a namespace, DefaultGroupLabel if empty
+	MinPeers        uint64        // minimum number of connected peers of upgraded nodes
+	Version         string        // version reported by upgraded nodes, derived from the image tag if empty
+	GateTimeout     time.Duration // time upgraded nodes of a batch have to pass the gates
+	PollInterval    time.Duration // interval of checking the gates
+	Rollback        bool          // restore the previous image of upgraded nodes when a gate fails
+}
+
+// upgradeTarget represents a statefulset and the nodes it runs
+type upgradeTarget struct {
+	statefulSet   string
+	nodeGroup     string
+	previousImage string
+	onDelete      bool
+	nodes         []node.Node
+}
+
+// Upgrade updates the image of nodes in batches. After each batch, it waits
+// until the upgraded pods run the new image and are ready, and their nodes
+// report readiness, the minimum number of connected peers and the expected
+// version. When a gate fails, the upgrade stops and, if rollback is enabled,
+// the previous images of all upgraded nodes are restored.
+func (c *Client) Upgrade(ctx context.Context, o UpgradeOptions) error {
+	if o.Image == "" {
+		return errors.New("image not provided")
+	}
+	if !o.ByNodeGroup && (o.BatchPercentage < 1 || o.BatchPercentage > 100) {
+		return fmt.Errorf("invalid batch percentage %d", o.BatchPercentage)
+	}
+	if o.GroupLabel == "" {
+		o.GroupLabel = DefaultGroupLabel
+	}
+	if o.GateTimeout <= 0 {
+		o.GateTimeout = defaultGateTimeout
+	}
+	if o.PollInterval <= 0 {
+		o.PollInterval = defaultPollInterval
+	}
+	if o.Version == "" {
+		o.Version = imageVersion(o.Image)
+	}
+	if o.Version == "" {
+		c.logger.Warningf("version of image %s is unknown, version gate is disabled", o.Image)
+	}
+
+	nodes, err := c.nodeProvider.GetNodes(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get nodes: %w", err)
+	}
+
+	targets, err := c.upgradeTargets(ctx, nodes, o.GroupLabel)
+	if err != nil {
+		return err
+	}
+
+	batches := upgradeBatches(targets, o.ByNodeGroup, o.BatchPercentage)
+
+	c.logger.Infof("upgrading %d statefulsets to image %s in %d batches", len(targets), o.Image, len(batches))
+
+	var upgraded []upgradeTarget
+	for i, batch := range batches {
+		c.logger.Infof("upgrading batch %d/%d: %s", i+1, len(batches), targetNames(batch))
+
+		for _, t := range batch {
+			upgraded = append(upgraded, t)
+			if err := c.setImage(ctx, t, o.Image); err != nil {
+				return c.stopUpgrade(ctx, upgraded, o.Rollback, err)
+			}
+		}
+
+		if err := c.waitGates(ctx, batch, o); err != nil {
+			return c.stopUpgrade(ctx, upgraded, o.Rollback, fmt.Errorf("batch %d: %w", i+1, err))
+		}
+
+		c.logger.Infof("batch %d/%d upgraded", i+1, len(batches))
+	}
+
+	c.logger.Infof("successfully upgraded %d statefulsets to image %s", len(targets), o.Image)
+
+	return nil
+}
+
+// upgradeTargets returns statefulsets of the nodes, sorted by their names
+func (c *Client) upgradeTargets(ctx context.Context, nodes node.NodeList, groupLabel string) ([]upgradeTarget, error) {
+	namespace := c.nodeProvider.Namespace()
+	targets := make(map[string]*upgradeTarget)
+
+	for _, n := range nodes {
+		pod, err := c.k8sClient.Pods.Get(ctx, n.Name(), namespace)
+		if err != nil {
+			return nil, fmt.Errorf("getting pod %s: %w", n.Name(), err)
+		}
+
+		ss, err := c.k8sClient.Pods.GetControllingStatefulSet(ctx, n.Name(), namespace)
+		if err != nil {
+			return nil, fmt.Errorf("getting statefulset of pod %s: %w", n.Name(), err)
+		}
+
+		t, ok := targets[ss.Name]
+		if !ok {
+			if len(ss.Spec.Template.Spec.Containers) == 0 {
+				return nil, fmt.Errorf("statefulset %s has no containers", ss.Name)
+			}
+
+			nodeGroup := n.NodeGroup()
+			if nodeGroup == "" {
+				nodeGroup = pod.Labels[groupLabel]
+			}
+			if nodeGroup == "" {
+				nodeGroup = defaultNodeGroup
+			}
+
+			t = &upgradeTarget{
+				statefulSet:   ss.Name,
+				nodeGroup:     nodeGroup,
+				previousImage: ss.Spec.Template.Spec.Containers[0].Image,
+				onDelete:      ss.Spec.UpdateStrategy.Type == statefulset.UpdateStrategyOnDelete,
+			}
+			targets[ss.Name] = t
+		}
+		t.nodes = append(t.nodes, n)
+	}
+
+	list := make([]upgradeTarget, 0, len(targets))
+	for _, t := range targets {
+		list = append(list, *t)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].statefulSet < list[j].statefulSet })
+
+	return list, nil
+}
+
+// upgradeBatches splits targets into batches of node groups, in the order of
+// their names, or of the given percentage of targets
+func upgradeBatches(targets []upgradeTarget, byNodeGroup bool, percentage int) (batches [][]upgradeTarget) {
+	if byNodeGroup {
+		groups := make(map[string][]upgradeTarget)
+		var names []string
+		for _, t := range targets {
+			if _, ok := groups[t.nodeGroup]; !ok {
+				names = append(names, t.nodeGroup)
+			}
+			groups[t.nodeGroup] = append(groups[t.nodeGroup], t)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			batches = append(batches, groups[name])
+		}
+		return batches
+	}
+
+	size := max((len(targets)*percentage+99)/100, 1)
+	for i := 0; i < len(targets); i += size {
+		batches = append(batches, targets[i:min(i+size, len(targets))])
+	}
+
+	return batches
+}
+
+// setImage updates the image of the statefulset and deletes its pods if they
+// are not replaced by Kubernetes
+func (c *Client) setImage(ctx context.Context, t upgradeTarget, image string) error {
+	if err := c.k8sClient.StatefulSet.UpdateImage(ctx, t.statefulSet, c.nodeProvider.Namespace(), image); err != nil {
+		return fmt.Errorf("updating image for statefulset %s: %w", t.statefulSet, err)
+	}
+
+	if t.onDelete {
+		for _, n := range t.nodes {
+			if err := c.deletePod(ctx, n.Name()); err != nil {
+				return err
+			}
+		}
+	}
+
+	c.logger.Debugf("statefulset %s image set to %s", t.statefulSet, image)
+
+	return nil
+}
+
+// stopUpgrade restores previous images of upgraded statefulsets if rollback
+// is enabled and returns the error that stopped the upgrade
+func (c *Client) stopUpgrade(ctx context.Context, upgraded []upgradeTarget, rollback bool, err error) error {
+	if !rollback {
+		return fmt.Errorf("upgrade stopped: %w", err)
+	}
+
+	c.logger.Warningf("upgrade failed, rolling back %d statefulsets: %v", len(upgraded), err)
+
+	var rollbackErrs []error
+	for _, t := range upgraded {
+		if rErr := c.setImage(ctx, t, t.previousImage); rErr != nil {
+			rollbackErrs = append(rollbackErrs, rErr)
+		}
+	}
+	if rErr := errors.Join(rollbackErrs...); rErr != nil {
+		return fmt.Errorf("upgrade stopped: %w; rollback: %w", err, rErr)
+	}
+
+	return fmt.Errorf("upgrade stopped and rolled back: %w", err)
+}
+
+// waitGates waits until all nodes of the batch pass the health gates, or the
+// gate timeout expires
+func (c *Client) waitGates(ctx context.Context, batch []upgradeTarget, o UpgradeOptions) error {
+	ctx, cancel := context.WithTimeout(ctx, o.GateTimeout)
+	defer cancel()
+
+	var nodes []node.Node
+	for _, t := range batch {
+		nodes = append(nodes, t.nodes...)
+	}
+
+	pending := make(map[string]string) // node names with the reasons they do not pass the gates
+	for _, n := range nodes {
+		pending[n.Name()] = "not checked"
+	}
+
+	for {
+		for _, n := range nodes {
+			if _, ok := pending[n.Name()]; !ok {
+				continue
+			}
+			if reason := c.checkGates(ctx, n, o); reason != "" {
+				pending[n.Name()] = reason
+				continue
+			}
+			delete(pending, n.Name())
+			c.logger.Infof("node %s passed upgrade gates", n.Name())
+		}
+
+		if len(pending) == 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			reasons := make([]string, 0, len(pending))
+			for name, reason := range pending {
+				reasons = append(reasons, fmt.Sprintf("%s: %s", name, reason))
+			}
+			sort.Strings(reasons)
+			return fmt.Errorf("%w: %s", ErrGateFailed, strings.Join(reasons, "; "))
+		case <-time.After(o.PollInterval):
+		}
+	}
+}
+
+// checkGates returns the reason the node does not pass the health gates, or
+// an empty string if it does
+func (c *Client) checkGates(ctx context.Context, n node.Node, o UpgradeOptions) string {
+	pod, err := c.k8sClient.Pods.Get(ctx, n.Name(), c.nodeProvider.Namespace())
+	if err != nil {
+		return fmt.Sprintf("pod: %v", err)
+	}
+	if len(pod.Spec.Containers) == 0 || pod.Spec.Containers[0].Image != o.Image {
+		return "pod is not recreated with the new image"
+	}
+	if !podReady(pod) {
+		return "pod is not ready"
+	}
+
+	if _, err := n.Client().Node.Readiness(ctx); err != nil {
+		return fmt.Sprintf("readiness: %v", err)
+	}
+
+	status, err := n.Client().Status.Status(ctx)
+	if err != nil {
+		return fmt.Sprintf("status: %v", err)
+	}
+	if status.ConnectedPeers < o.MinPeers {
+		return fmt.Sprintf("connected to %d peers, want at least %d", status.ConnectedPeers, o.MinPeers)
+	}
+
+	if o.Version != "" {
+		health, err := n.Client().Node.Health(ctx)
+		if err != nil {
+			return fmt.Sprintf("health: %v", err)
+		}
+		if !strings.HasPrefix(strings.TrimPrefix(health.Version, "v"), o.Version) {
+			return fmt.Sprintf("version %s, want %s", health.Version, o.Version)
+		}
+	}
+
+	return ""
+}
+
+// podReady returns whether the pod's ready condition is true
+func podReady(pod *v1.Pod) bool {
+	for _, c := range pod.Status.Conditions {
+		if c.Type == v1.PodReady {
+			return c.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// imageVersion returns the version from the image tag, or an empty string if
+// the tag is not a version
+func imageVersion(image string) string {
+	image, _, _ = strings.Cut(image, "@")
+	i := strings.LastIndex(image, ":")
+	if i < 0 || strings.Contains(image[i:], "/") {
+		return ""
+	}
+
+	tag := strings.TrimPrefix(image[i+1:], "v")
+	if tag == "" || tag[0] < '0' || tag[0] > '9' {
+		return ""
+	}
+
+	return tag
+}
+
+func targetNames(targets []upgradeTarget) string {
+	names := make([]string, 0, len(targets))
+	for _, t := range targets {
+		names = append(names, t.statefulSet)
+	}
+	return strings.Join(names, ", ")
+}
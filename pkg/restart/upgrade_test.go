@@ -0,0 +1,187 @@
+package restart_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/bee/v2/pkg/swarm"
+	"github.com/ethersphere/beekeeper/pkg/bee/api/beetest"
+	"github.com/ethersphere/beekeeper/pkg/k8s"
+	"github.com/ethersphere/beekeeper/pkg/k8s/pod"
+	"github.com/ethersphere/beekeeper/pkg/k8s/statefulset"
+	"github.com/ethersphere/beekeeper/pkg/logging"
+	"github.com/ethersphere/beekeeper/pkg/node"
+	"github.com/ethersphere/beekeeper/pkg/restart"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+const (
+	testNamespace = "test"
+	oldImage      = "ethersphere/bee:0.0.0"
+)
+
+type nodeProvider node.NodeList
+
+func (p nodeProvider) GetNodes(context.Context) (node.NodeList, error) {
+	return node.NodeList(p), nil
+}
+
+func (p nodeProvider) Namespace() string {
+	return testNamespace
+}
+
+// newUpgradeClient returns a restart client of nodes run by statefulsets with
+// the given node groups, and the fake clientset in which pods are recreated
+// with the new image when their statefulsets are updated
+func newUpgradeClient(t *testing.T, groups ...string) (*restart.Client, *fake.Clientset) {
+	t.Helper()
+
+	var (
+		objects []runtime.Object
+		nodes   nodeProvider
+	)
+	for i, group := range groups {
+		name := fmt.Sprintf("bee-%d", i)
+		labels := map[string]string{restart.DefaultGroupLabel: group}
+		containers := []v1.Container{{Name: name, Image: oldImage}}
+
+		objects = append(objects,
+			&appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace},
+				Spec: appsv1.StatefulSetSpec{
+					Template: v1.PodTemplateSpec{Spec: v1.PodSpec{Containers: containers}},
+				},
+			},
+			&v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name + "-0",
+					Namespace: testNamespace,
+					Labels:    labels,
+					OwnerReferences: []metav1.OwnerReference{{
+						Kind:       "StatefulSet",
+						Name:       name,
+						Controller: func() *bool { b := true; return &b }(),
+					}},
+				},
+				Spec: v1.PodSpec{Containers: containers},
+				Status: v1.PodStatus{
+					Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: v1.ConditionTrue}},
+				},
+			},
+		)
+
+		_, client := beetest.New(t, beetest.WithPeers(swarm.RandAddress(t), swarm.RandAddress(t)))
+		nodes = append(nodes, *node.NewNode(client, name+"-0"))
+	}
+
+	clientset := fake.NewSimpleClientset(objects...)
+	clientset.PrependReactor("update", "statefulsets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		ss := action.(k8stesting.UpdateAction).GetObject().(*appsv1.StatefulSet)
+
+		podsResource := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+		obj, err := clientset.Tracker().Get(podsResource, testNamespace, ss.Name+"-0")
+		if err != nil {
+			return true, nil, err
+		}
+		p := obj.(*v1.Pod).DeepCopy()
+		p.Spec.Containers[0].Image = ss.Spec.Template.Spec.Containers[0].Image
+
+		return false, nil, clientset.Tracker().Update(podsResource, p, testNamespace)
+	})
+
+	k8sClient := &k8s.Client{
+		Pods:        pod.NewClient(clientset, logging.New(io.Discard, 0)),
+		StatefulSet: statefulset.NewClient(clientset, logging.New(io.Discard, 0)),
+	}
+
+	return restart.NewClient(nodes, k8sClient, logging.New(io.Discard, 0)), clientset
+}
+
+func statefulSetImages(t *testing.T, clientset *fake.Clientset, n int) (images []string) {
+	t.Helper()
+
+	for i := range n {
+		ss, err := clientset.AppsV1().StatefulSets(testNamespace).Get(context.Background(), fmt.Sprintf("bee-%d", i), metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		images = append(images, ss.Spec.Template.Spec.Containers[0].Image)
+	}
+
+	return images
+}
+
+func TestUpgrade(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       restart.UpgradeOptions
+		wantErr    error
+		wantImages []string
+	}{
+		{
+			name:       "by percentage",
+			opts:       restart.UpgradeOptions{Image: "ethersphere/bee:0.0.0-beetest", BatchPercentage: 50, MinPeers: 2},
+			wantImages: []string{"ethersphere/bee:0.0.0-beetest", "ethersphere/bee:0.0.0-beetest", "ethersphere/bee:0.0.0-beetest"},
+		},
+		{
+			name:       "by node group",
+			opts:       restart.UpgradeOptions{Image: "ethersphere/bee:latest", ByNodeGroup: true},
+			wantImages: []string{"ethersphere/bee:latest", "ethersphere/bee:latest", "ethersphere/bee:latest"},
+		},
+		{
+			name:       "version gate fails",
+			opts:       restart.UpgradeOptions{Image: "ethersphere/bee:2.7.0", BatchPercentage: 50},
+			wantErr:    restart.ErrGateFailed,
+			wantImages: []string{"ethersphere/bee:2.7.0", "ethersphere/bee:2.7.0", oldImage},
+		},
+		{
+			name:       "peers gate fails with rollback",
+			opts:       restart.UpgradeOptions{Image: "ethersphere/bee:latest", BatchPercentage: 50, MinPeers: 3, Rollback: true},
+			wantErr:    restart.ErrGateFailed,
+			wantImages: []string{oldImage, oldImage, oldImage},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			client, clientset := newUpgradeClient(t, "bootnode", "bee", "bee")
+
+			tc.opts.GateTimeout = 200 * time.Millisecond
+			tc.opts.PollInterval = 10 * time.Millisecond
+
+			err := client.Upgrade(context.Background(), tc.opts)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tc.wantErr)
+			}
+
+			images := statefulSetImages(t, clientset, 3)
+			for i := range images {
+				if images[i] != tc.wantImages[i] {
+					t.Errorf("got images %v, want %v", images, tc.wantImages)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestUpgradeInvalidOptions(t *testing.T) {
+	client, _ := newUpgradeClient(t, "bee")
+
+	if err := client.Upgrade(context.Background(), restart.UpgradeOptions{BatchPercentage: 50}); err == nil {
+		t.Error("expected error for missing image")
+	}
+	if err := client.Upgrade(context.Background(), restart.UpgradeOptions{Image: oldImage, BatchPercentage: 101}); err == nil {
+		t.Error("expected error for invalid batch percentage")
+	}
+}
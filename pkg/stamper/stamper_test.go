@@ -0,0 +1,107 @@
+package stamper_test
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/beekeeper/pkg/bee/api"
+	"github.com/ethersphere/beekeeper/pkg/bee/api/beetest"
+	"github.com/ethersphere/beekeeper/pkg/bigint"
+	"github.com/ethersphere/beekeeper/pkg/node"
+	"github.com/ethersphere/beekeeper/pkg/stamper"
+	"github.com/ethersphere/beekeeper/pkg/swap"
+)
+
+type nodeProvider node.NodeList
+
+func (p nodeProvider) GetNodes(context.Context) (node.NodeList, error) {
+	return node.NodeList(p), nil
+}
+
+func (p nodeProvider) Namespace() string {
+	return "test"
+}
+
+type blockTime int64
+
+func (b blockTime) FetchBlockTime(context.Context, ...swap.Option) (int64, error) {
+	return int64(b), nil
+}
+
+func batch(id, label string, utilization uint32, ttl int64) api.PostageStampResponse {
+	return api.PostageStampResponse{
+		BatchID:     id,
+		Label:       label,
+		Usable:      true,
+		Exists:      true,
+		Depth:       20,
+		BucketDepth: 16,
+		Utilization: utilization,
+		Amount:      bigint.Wrap(big.NewInt(1000)),
+		BatchTTL:    ttl,
+	}
+}
+
+func newStamper(t *testing.T, servers ...*beetest.Server) *stamper.Client {
+	t.Helper()
+
+	var nodes nodeProvider
+	for i, s := range servers {
+		nodes = append(nodes, *node.NewNode(s.Client(), fmt.Sprintf("bee-%d", i)))
+	}
+
+	return stamper.New(&stamper.ClientConfig{
+		SwapClient: blockTime(5),
+		NodeClient: nodes,
+	})
+}
+
+func TestTopup(t *testing.T) {
+	s, _ := beetest.New(t)
+	s.AddBatch(batch("aa", "keep", 1, 3600))
+	s.AddBatch(batch("bb", "other", 1, 3600))
+
+	failing, _ := beetest.New(t)
+	failing.AddBatch(batch("cc", "keep", 1, 3600))
+	failing.Fail("PATCH /stamps/topup/{id}/{amount}", http.StatusInternalServerError, -1)
+
+	st := newStamper(t, failing, s)
+	if err := st.Topup(context.Background(), 2*time.Hour, 3*time.Hour, stamper.WithPostageLabels([]string{"keep"})); err != nil {
+		t.Fatalf("topup: %v", err)
+	}
+
+	// 2 hours of blocks of 5 seconds at the default price per block
+	want := int64(2*3600/5) * beetest.DefaultCurrentPrice
+	if b, _ := s.Batch("aa"); b.Amount.Int64() != 1000+want {
+		t.Fatalf("amount: got %s, want %d", b.Amount, 1000+want)
+	}
+	if b, _ := s.Batch("bb"); b.Amount.Int64() != 1000 {
+		t.Fatalf("batch with other label was topped up to %s", b.Amount)
+	}
+	if got := failing.RequestCount(http.MethodPatch, "/stamps/topup/cc/"+big.NewInt(want).String()); got != 1 {
+		t.Fatalf("top up requests on failing node: got %d, want 1", got)
+	}
+}
+
+func TestDilute(t *testing.T) {
+	s, _ := beetest.New(t)
+	// 8 of 16 slots per bucket of a depth 20 batch are 50% usage
+	s.AddBatch(batch("aa", "label", 8, 3600))
+	s.AddBatch(batch("bb", "label", 1, 3600))
+
+	st := newStamper(t, s)
+	if err := st.Dilute(context.Background(), 50, 2, stamper.WithBatchIDs([]string{"aa", "bb"})); err != nil {
+		t.Fatalf("dilute: %v", err)
+	}
+
+	if b, _ := s.Batch("aa"); b.Depth != 22 {
+		t.Fatalf("depth: got %d, want 22", b.Depth)
+	}
+	if b, _ := s.Batch("bb"); b.Depth != 20 {
+		t.Fatalf("batch below threshold was diluted to depth %d", b.Depth)
+	}
+}
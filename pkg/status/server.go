@@ -0,0 +1,144 @@
+package status
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/ethersphere/beekeeper"
+	"github.com/ethersphere/beekeeper/pkg/logging"
+	"github.com/ethersphere/beekeeper/pkg/report"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Server serves the status of beekeeper over HTTP
+type Server struct {
+	server *http.Server
+	logger logging.Logger
+}
+
+// NewServer creates a Server listening on addr
+func NewServer(addr string, tracker *Tracker, gatherer prometheus.Gatherer, logger logging.Logger) *Server {
+	return &Server{
+		server: &http.Server{
+			Addr:              addr,
+			Handler:           NewHandler(tracker, gatherer),
+			ReadHeaderTimeout: 10 * time.Second,
+		},
+		logger: logger,
+	}
+}
+
+// Start starts listening and serves requests in the background
+func (s *Server) Start() error {
+	ln, err := net.Listen("tcp", s.server.Addr)
+	if err != nil {
+		return fmt.Errorf("status server listen on %s: %w", s.server.Addr, err)
+	}
+
+	go func() {
+		if err := s.server.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.logger.Errorf("status server: %v", err)
+		}
+	}()
+
+	s.logger.Infof("status server listening on %s", ln.Addr())
+	return nil
+}
+
+// Close gracefully shuts down the server
+func (s *Server) Close(ctx context.Context) error {
+	return s.server.Shutdown(ctx)
+}
+
+// NewHandler returns the handler with the health, status and metrics
+// endpoints.
+func NewHandler(tracker *Tracker, gatherer prometheus.Gatherer) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, healthResponse{Status: "ok", Version: beekeeper.Version})
+	})
+	mux.HandleFunc("GET /status", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, newStatusResponse(tracker, time.Now()))
+	})
+	mux.Handle("GET /metrics", promhttp.HandlerFor(gatherer, promhttp.HandlerOpts{}))
+	return mux
+}
+
+type healthResponse struct {
+	Status  string `json:"status"`
+	Version string `json:"version"`
+}
+
+type runningResponse struct {
+	Kind    string    `json:"kind"`
+	Name    string    `json:"name"`
+	Type    string    `json:"type,omitempty"`
+	Started time.Time `json:"started"`
+	Elapsed float64   `json:"elapsed_seconds"`
+}
+
+type resultResponse struct {
+	Kind      string        `json:"kind"`
+	Name      string        `json:"name"`
+	Type      string        `json:"type,omitempty"`
+	Status    report.Status `json:"status"`
+	Attempts  int           `json:"attempts,omitempty"`
+	Duration  float64       `json:"duration_seconds"`
+	Error     string        `json:"error,omitempty"`
+	Artifact  string        `json:"artifact,omitempty"`
+	Timestamp time.Time     `json:"timestamp"`
+}
+
+type statusResponse struct {
+	Running []runningResponse `json:"running"`
+	Results []resultResponse  `json:"results"`
+}
+
+func newStatusResponse(tracker *Tracker, now time.Time) statusResponse {
+	resp := statusResponse{
+		Running: []runningResponse{},
+		Results: []resultResponse{},
+	}
+
+	for _, r := range tracker.Running() {
+		resp.Running = append(resp.Running, runningResponse{
+			Kind:    r.Kind,
+			Name:    r.Name,
+			Type:    r.Type,
+			Started: r.Started,
+			Elapsed: now.Sub(r.Started).Seconds(),
+		})
+	}
+
+	for _, r := range tracker.Results() {
+		res := resultResponse{
+			Kind:      r.Kind,
+			Name:      r.Name,
+			Type:      r.Type,
+			Status:    r.Status,
+			Attempts:  r.Attempts,
+			Duration:  r.Duration.Seconds(),
+			Artifact:  r.Artifact,
+			Timestamp: r.Timestamp,
+		}
+		if r.Error != nil {
+			res.Error = r.Error.Error()
+		}
+		resp.Results = append(resp.Results, res)
+	}
+
+	return resp
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,102 @@
+package status_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethersphere/beekeeper/pkg/report"
+	"github.com/ethersphere/beekeeper/pkg/status"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestHandler(t *testing.T) {
+	tracker := status.NewTracker(2)
+
+	for i, name := range []string{"ci-pingpong", "ci-pushsync", "ci-retrieval"} {
+		finish := tracker.Start(status.KindCheck, name, "type")
+		var err error
+		if i == 2 {
+			err = errors.New("failed")
+		}
+		finish(report.Result{Name: name, Status: report.StatusPassed, Duration: time.Second, Error: err})
+	}
+	tracker.Start(status.KindSimulation, "upload", "upload")
+
+	registry := prometheus.NewRegistry()
+	counter := prometheus.NewCounter(prometheus.CounterOpts{Name: "test_total", Help: "test"})
+	registry.MustRegister(counter)
+	counter.Inc()
+
+	server := httptest.NewServer(status.NewHandler(tracker, registry))
+	defer server.Close()
+
+	get := func(path string) []byte {
+		t.Helper()
+		resp, err := http.Get(server.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("%s: got status code %d", path, resp.StatusCode)
+		}
+		b, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+
+	var health struct {
+		Status string `json:"status"`
+	}
+	if err := json.Unmarshal(get("/health"), &health); err != nil {
+		t.Fatal(err)
+	}
+	if health.Status != "ok" {
+		t.Fatalf("got health status %q, want ok", health.Status)
+	}
+
+	var st struct {
+		Running []struct {
+			Kind    string  `json:"kind"`
+			Name    string  `json:"name"`
+			Elapsed float64 `json:"elapsed_seconds"`
+		} `json:"running"`
+		Results []struct {
+			Name  string `json:"name"`
+			Error string `json:"error"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(get("/status"), &st); err != nil {
+		t.Fatal(err)
+	}
+	if len(st.Running) != 1 || st.Running[0].Kind != status.KindSimulation || st.Running[0].Name != "upload" || st.Running[0].Elapsed < 0 {
+		t.Fatalf("unexpected running: %+v", st.Running)
+	}
+	if len(st.Results) != 2 || st.Results[0].Name != "ci-retrieval" || st.Results[0].Error != "failed" || st.Results[1].Name != "ci-pushsync" {
+		t.Fatalf("unexpected results: %+v", st.Results)
+	}
+
+	if m := string(get("/metrics")); !strings.Contains(m, "test_total 1") {
+		t.Fatalf("metrics do not contain test_total: %s", m)
+	}
+}
+
+func TestTrackerDefaultLimit(t *testing.T) {
+	tracker := status.NewTracker(0)
+
+	for range status.DefaultResultsLimit + 10 {
+		tracker.Start(status.KindCheck, "ci-pingpong", "pingpong")(report.Result{Name: "ci-pingpong", Status: report.StatusPassed})
+	}
+
+	if got := len(tracker.Results()); got != status.DefaultResultsLimit {
+		t.Fatalf("got %d results, want %d", got, status.DefaultResultsLimit)
+	}
+}
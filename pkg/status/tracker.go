@@ -0,0 +1,123 @@
+// Package status tracks checks and simulations executed by beekeeper and
+// exposes them, together with metrics, over HTTP.
+package status
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethersphere/beekeeper/pkg/report"
+)
+
+// Kinds of tracked actions
+const (
+	KindCheck      = "check"
+	KindSimulation = "simulation"
+)
+
+// Running represents a check or simulation that is currently executed
+type Running struct {
+	Kind    string
+	Name    string
+	Type    string
+	Started time.Time
+}
+
+// Finished represents the result of an executed check or simulation
+type Finished struct {
+	Kind string
+	report.Result
+}
+
+// DefaultResultsLimit is the number of the most recent results kept by a
+// Tracker that is not given a positive limit
+const DefaultResultsLimit = 100
+
+// Tracker keeps track of running checks and simulations and of the results of
+// the most recent ones. It is safe for concurrent use.
+type Tracker struct {
+	mu      sync.Mutex
+	nextID  uint64
+	running map[uint64]Running
+	results []Finished // oldest first
+	limit   int
+}
+
+// NewTracker creates a Tracker that keeps at most limit results, or
+// DefaultResultsLimit results if limit is not positive
+func NewTracker(limit int) *Tracker {
+	if limit <= 0 {
+		limit = DefaultResultsLimit
+	}
+
+	return &Tracker{
+		running: make(map[uint64]Running),
+		limit:   limit,
+	}
+}
+
+// Start marks the check or simulation as running. The returned function
+// must be called with its result once it is finished.
+func (t *Tracker) Start(kind, name, typeName string) (finish func(report.Result)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	id := t.nextID
+	t.nextID++
+	t.running[id] = Running{
+		Kind:    kind,
+		Name:    name,
+		Type:    typeName,
+		Started: time.Now(),
+	}
+
+	var once sync.Once
+	return func(r report.Result) {
+		once.Do(func() {
+			t.finish(id, kind, r)
+		})
+	}
+}
+
+func (t *Tracker) finish(id uint64, kind string, r report.Result) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.running, id)
+
+	t.results = append(t.results, Finished{Kind: kind, Result: r})
+	if len(t.results) > t.limit {
+		t.results = t.results[len(t.results)-t.limit:]
+	}
+}
+
+// Running returns checks and simulations that are currently executed, in the
+// order they were started.
+func (t *Tracker) Running() []Running {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	running := make([]Running, 0, len(t.running))
+	for _, r := range t.running {
+		running = append(running, r)
+	}
+	sort.Slice(running, func(i, j int) bool {
+		return running[i].Started.Before(running[j].Started)
+	})
+
+	return running
+}
+
+// Results returns the most recent results, newest first
+func (t *Tracker) Results() []Finished {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	results := make([]Finished, 0, len(t.results))
+	for i := len(t.results) - 1; i >= 0; i-- {
+		results = append(results, t.results[i])
+	}
+
+	return results
+}
@@ -0,0 +1,155 @@
+// Package transactions lists pending blockchain transactions of Bee nodes and
+// resends or cancels the ones that are stuck.
+package transactions
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethersphere/beekeeper/pkg/bee/api"
+	"github.com/ethersphere/beekeeper/pkg/logging"
+	"github.com/ethersphere/beekeeper/pkg/node"
+)
+
+// Transaction is a pending transaction of a node
+type Transaction struct {
+	Node string
+	api.TransactionInfo
+}
+
+// Age returns the time passed since the transaction was created
+func (t Transaction) Age(now time.Time) time.Duration {
+	return now.Sub(t.Created)
+}
+
+type ClientConfig struct {
+	Log        logging.Logger
+	NodeClient node.NodeProvider
+}
+
+type Client struct {
+	log        logging.Logger
+	nodeClient node.NodeProvider
+}
+
+func New(cfg *ClientConfig) *Client {
+	if cfg == nil {
+		return nil
+	}
+
+	if cfg.Log == nil {
+		cfg.Log = logging.New(io.Discard, 0)
+	}
+
+	if cfg.NodeClient == nil {
+		cfg.NodeClient = &node.NotSet{}
+	}
+
+	return &Client{
+		log:        cfg.Log,
+		nodeClient: cfg.NodeClient,
+	}
+}
+
+// Pending returns pending transactions of all nodes that are older than
+// minAge, ordered by node name and nonce. Nodes whose transactions can not be
+// listed are skipped, and their errors are returned joined together with the
+// transactions of the other nodes.
+func (c *Client) Pending(ctx context.Context, minAge time.Duration) ([]Transaction, error) {
+	nodes, err := c.nodeClient.GetNodes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get nodes: %w", err)
+	}
+
+	return c.pending(ctx, nodes, minAge)
+}
+
+func (c *Client) pending(ctx context.Context, nodes node.NodeList, minAge time.Duration) ([]Transaction, error) {
+	now := time.Now()
+
+	var (
+		txs  []Transaction
+		errs []error
+	)
+	for _, n := range nodes.Sort() {
+		pending, err := n.Client().Transactions.Pending(ctx)
+		if err != nil {
+			c.log.Errorf("node %s: list pending transactions: %v", n.Name(), err)
+			errs = append(errs, fmt.Errorf("node %s: list pending transactions: %w", n.Name(), err))
+			continue
+		}
+
+		slices.SortFunc(pending, func(a, b api.TransactionInfo) int { return cmp.Compare(a.Nonce, b.Nonce) })
+		for _, tx := range pending {
+			t := Transaction{Node: n.Name(), TransactionInfo: tx}
+			if t.Age(now) < minAge {
+				continue
+			}
+			txs = append(txs, t)
+		}
+	}
+
+	return txs, errors.Join(errs...)
+}
+
+// Resend sends pending transactions older than minAge again and returns the
+// number of resent transactions.
+func (c *Client) Resend(ctx context.Context, minAge time.Duration) (int, error) {
+	return c.apply(ctx, "resend", minAge, func(ctx context.Context, client *api.Client, hash common.Hash) (common.Hash, error) {
+		return client.Transactions.Resend(ctx, hash)
+	})
+}
+
+// Cancel cancels pending transactions older than minAge and returns the number
+// of cancelled transactions.
+func (c *Client) Cancel(ctx context.Context, minAge time.Duration) (int, error) {
+	return c.apply(ctx, "cancel", minAge, func(ctx context.Context, client *api.Client, hash common.Hash) (common.Hash, error) {
+		return client.Transactions.Cancel(ctx, hash)
+	})
+}
+
+// apply calls f for every pending transaction older than minAge. Failures are
+// logged and reported together after all transactions were processed.
+func (c *Client) apply(ctx context.Context, action string, minAge time.Duration, f func(ctx context.Context, client *api.Client, hash common.Hash) (common.Hash, error)) (int, error) {
+	if minAge <= 0 {
+		return 0, fmt.Errorf("minimum age of transactions to %s must be greater than 0", action)
+	}
+
+	nodes, err := c.nodeClient.GetNodes(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("get nodes: %w", err)
+	}
+
+	// transactions of nodes that can be listed are processed regardless of
+	// the nodes that can not
+	txs, listErr := c.pending(ctx, nodes, minAge)
+
+	count := 0
+	for _, tx := range txs {
+		hash, err := f(ctx, nodes.Get(tx.Node).Client(), tx.TransactionHash)
+		if err != nil {
+			c.log.Errorf("node %s: %s transaction %s: %v", tx.Node, action, tx.TransactionHash, err)
+			continue
+		}
+		count++
+
+		c.log.WithFields(map[string]any{
+			"nonce":       tx.Nonce,
+			"age":         tx.Age(time.Now()).Round(time.Second),
+			"description": tx.Description,
+		}).Infof("node %s: %s transaction %s: %s", tx.Node, action, tx.TransactionHash, hash)
+	}
+
+	var failedErr error
+	if count < len(txs) {
+		failedErr = fmt.Errorf("failed to %s %d of %d transactions", action, len(txs)-count, len(txs))
+	}
+
+	return count, errors.Join(listErr, failedErr)
+}
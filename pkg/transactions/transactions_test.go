@@ -0,0 +1,147 @@
+package transactions_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethersphere/beekeeper/pkg/bee/api"
+	"github.com/ethersphere/beekeeper/pkg/bee/api/beetest"
+	"github.com/ethersphere/beekeeper/pkg/node"
+	"github.com/ethersphere/beekeeper/pkg/transactions"
+)
+
+type nodeProvider node.NodeList
+
+func (p nodeProvider) GetNodes(context.Context) (node.NodeList, error) {
+	return node.NodeList(p), nil
+}
+
+func (p nodeProvider) Namespace() string {
+	return "test"
+}
+
+func newClient(t *testing.T, servers ...*beetest.Server) *transactions.Client {
+	t.Helper()
+
+	var nodes nodeProvider
+	for i, s := range servers {
+		nodes = append(nodes, *node.NewNode(s.Client(), fmt.Sprintf("bee-%d", i)))
+	}
+
+	return transactions.New(&transactions.ClientConfig{NodeClient: nodes})
+}
+
+func tx(hash string, nonce uint64, age time.Duration) api.TransactionInfo {
+	return api.TransactionInfo{
+		TransactionHash: common.HexToHash(hash),
+		Nonce:           nonce,
+		Created:         time.Now().Add(-age),
+	}
+}
+
+func TestPending(t *testing.T) {
+	s0, _ := beetest.New(t)
+	s0.AddTransaction(tx("0x02", 2, time.Hour))
+	s0.AddTransaction(tx("0x01", 1, time.Minute))
+
+	s1, _ := beetest.New(t)
+	s1.AddTransaction(tx("0x03", 7, 2*time.Hour))
+
+	failing, _ := beetest.New(t)
+	failing.AddTransaction(tx("0x04", 1, time.Hour))
+	failing.Fail("GET /transactions", http.StatusInternalServerError, -1)
+
+	c := newClient(t, s0, s1, failing)
+
+	// transactions of the other nodes are returned with the error of the
+	// failing node
+	txs, err := c.Pending(context.Background(), 0)
+	if err == nil || !strings.Contains(err.Error(), "node bee-2") {
+		t.Fatalf("pending: got error %v, want error of node bee-2", err)
+	}
+	var got []string
+	for _, tx := range txs {
+		got = append(got, fmt.Sprintf("%s/%d", tx.Node, tx.Nonce))
+	}
+	if want := "[bee-0/1 bee-0/2 bee-1/7]"; fmt.Sprint(got) != want {
+		t.Fatalf("got pending transactions %v, want %s", got, want)
+	}
+
+	txs, _ = c.Pending(context.Background(), 30*time.Minute)
+	if len(txs) != 2 || txs[0].Nonce != 2 || txs[1].Nonce != 7 {
+		t.Fatalf("got pending transactions %+v, want nonces 2 and 7", txs)
+	}
+}
+
+func TestPendingAllNodesFailing(t *testing.T) {
+	s0, _ := beetest.New(t)
+	s0.Fail("GET /transactions", http.StatusInternalServerError, -1)
+	s1, _ := beetest.New(t)
+	s1.Fail("GET /transactions", http.StatusInternalServerError, -1)
+
+	c := newClient(t, s0, s1)
+
+	txs, err := c.Pending(context.Background(), 0)
+	if err == nil || !strings.Contains(err.Error(), "node bee-0") || !strings.Contains(err.Error(), "node bee-1") {
+		t.Fatalf("pending: got error %v, want errors of both nodes", err)
+	}
+	if len(txs) != 0 {
+		t.Fatalf("got pending transactions %+v, want none", txs)
+	}
+
+	if _, err := c.Cancel(context.Background(), time.Minute); err == nil {
+		t.Fatal("cancel: expected error of unreachable nodes")
+	}
+}
+
+func TestResend(t *testing.T) {
+	s0, _ := beetest.New(t)
+	s0.AddTransaction(tx("0x01", 1, time.Hour))
+	s0.AddTransaction(tx("0x02", 2, time.Minute))
+
+	s1, _ := beetest.New(t)
+	s1.AddTransaction(tx("0x03", 1, time.Hour))
+	s1.Fail("POST /transactions/{hash}", http.StatusInternalServerError, -1)
+
+	c := newClient(t, s0, s1)
+
+	n, err := c.Resend(context.Background(), 30*time.Minute)
+	if err == nil {
+		t.Fatal("resend: expected error of the failing node")
+	}
+	if n != 1 {
+		t.Fatalf("resend: got %d resent transactions, want 1", n)
+	}
+	if s0.Resent(common.HexToHash("0x01")) != 1 || s0.Resent(common.HexToHash("0x02")) != 0 {
+		t.Fatal("resend: only the old transaction must be resent")
+	}
+
+	if _, err := c.Resend(context.Background(), 0); err == nil {
+		t.Fatal("resend: expected error of zero minimum age")
+	}
+}
+
+func TestCancel(t *testing.T) {
+	s, _ := beetest.New(t)
+	s.AddTransaction(tx("0x01", 1, time.Hour))
+	s.AddTransaction(tx("0x02", 2, time.Hour))
+	s.AddTransaction(tx("0x03", 3, time.Minute))
+
+	c := newClient(t, s)
+
+	n, err := c.Cancel(context.Background(), 30*time.Minute)
+	if err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+	if n != 2 {
+		t.Fatalf("cancel: got %d cancelled transactions, want 2", n)
+	}
+	if txs := s.PendingTransactions(); len(txs) != 1 || txs[0].Nonce != 3 {
+		t.Fatalf("got pending transactions %+v after cancel, want nonce 3", txs)
+	}
+}